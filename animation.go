@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxAnimationFrames bounds a recording; capture stops once it is reached.
+const maxAnimationFrames = 1000
+
+var errNoFrames = errors.New("no frames recorded")
+
+// animationFormats are the export formats of a recording: GIF has 256
+// colors per frame, APNG and WebP full color and are usually smaller.
+var animationFormats = []string{"GIF", "APNG", "WebP"}
+
+// animation is a recording of a run, kept as the ages and palette of every
+// generation and drawn at export time, in the flat view, at any cell size.
+type animation struct {
+	gridSize int
+	cellSize int           // when recording started
+	delay    time.Duration // between two generations
+	grids    [][]uint8     // cell values of each frame, row-major
+	palettes []ColorPalette
+}
+
+func newAnimation(gridSize, cellSize int, delay time.Duration) *animation {
+	return &animation{gridSize: gridSize, cellSize: cellSize, delay: delay}
+}
+
+// capture records a generation and reports whether there was room for it.
+func (a *animation) capture(grid *Grid, palette ColorPalette) bool {
+	if len(a.grids) >= maxAnimationFrames {
+		return false
+	}
+	if grid.size != a.gridSize {
+		return true // a run of another size, left out
+	}
+	a.grids = append(a.grids, slices.Clone(grid.cells))
+	a.palettes = append(a.palettes, palette)
+	return true
+}
+
+// frameSource draws frame i of count, so long recordings are encoded one
+// frame at a time.
+type frameSource struct {
+	count int
+	frame func(i int) image.Image
+}
+
+// frames draws every skip-th recorded generation at cellSize.
+func (a *animation) frames(cellSize, skip int) frameSource {
+	grid := newGrid(a.gridSize)
+	img := image.NewRGBA(image.Rect(0, 0, a.gridSize*cellSize, a.gridSize*cellSize))
+	return frameSource{
+		count: (len(a.grids) + skip - 1) / skip,
+		frame: func(i int) image.Image {
+			copy(grid.cells, a.grids[i*skip])
+			drawGridDynamic(grid, img, a.palettes[i*skip], cellSize, a.gridSize)
+			return img
+		},
+	}
+}
+
+// writeGIF writes frames as an endlessly looping GIF. Frames of the flat
+// view rarely have more than 256 colors, so each gets its exact colors;
+// others are dithered to the Plan 9 palette.
+func writeGIF(out io.Writer, frames frameSource, delay time.Duration) error {
+	if frames.count == 0 {
+		return errNoFrames
+	}
+	anim := &gif.GIF{}
+	for i := range frames.count {
+		frame := frames.frame(i)
+		b := frame.Bounds()
+		var colors color.Palette
+		seen := make(map[color.Color]bool)
+		for y := b.Min.Y; y < b.Max.Y && len(colors) <= 256; y++ {
+			for x := b.Min.X; x < b.Max.X && len(colors) <= 256; x++ {
+				if c := frame.At(x, y); !seen[c] {
+					seen[c] = true
+					colors = append(colors, c)
+				}
+			}
+		}
+		paletted := image.NewPaletted(b, colors)
+		if len(colors) > 256 {
+			paletted.Palette = palette.Plan9
+			draw.FloydSteinberg.Draw(paletted, b, frame, b.Min)
+		} else {
+			draw.Draw(paletted, b, frame, b.Min, draw.Src)
+		}
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond))) // in 100ths of a second
+	}
+	return gif.EncodeAll(out, anim)
+}
+
+// showAnimationExportDialog asks for the format, cell size and frame skip
+// of a recording, then saves it.
+func showAnimationExportDialog(w fyne.Window, a *animation) {
+	formatSelect := widget.NewSelect(animationFormats, nil)
+	formatSelect.SetSelected("WebP")
+
+	cellLabel := widget.NewLabel(fmt.Sprintf(lang.L("Quality: %d px per cell"), a.cellSize))
+	cellSlider := widget.NewSlider(1, 8)
+	cellSlider.Value = float64(a.cellSize)
+	cellSlider.OnChanged = func(v float64) {
+		cellLabel.SetText(fmt.Sprintf(lang.L("Quality: %d px per cell"), int(v)))
+	}
+	skipLabel := widget.NewLabel(fmt.Sprintf(lang.L("Frame skip: keep 1 in %d"), 1))
+	skipSlider := widget.NewSlider(1, 10)
+	skipSlider.Value = 1
+	skipSlider.OnChanged = func(v float64) {
+		skipLabel.SetText(fmt.Sprintf(lang.L("Frame skip: keep 1 in %d"), int(v)))
+	}
+
+	content := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf(lang.L("%d generations recorded."), len(a.grids))),
+		container.NewBorder(nil, nil, widget.NewLabel(lang.L("Format")), nil, formatSelect),
+		cellLabel, cellSlider,
+		skipLabel, skipSlider,
+	)
+	dialog.ShowCustomConfirm(lang.L("🎞 Export animation"), lang.L("Save"), lang.L("Cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		skip := int(skipSlider.Value)
+		frames := a.frames(int(cellSlider.Value), skip)
+		delay := a.delay * time.Duration(skip)
+		switch formatSelect.Selected {
+		case "GIF":
+			saveFile(w, "living-numbers.gif", "image/gif", func(out io.Writer) error {
+				return writeGIF(out, frames, delay)
+			})
+		case "APNG":
+			saveFile(w, "living-numbers.png", "image/apng", func(out io.Writer) error {
+				return writeAPNG(out, frames, delay)
+			})
+		default:
+			saveFile(w, "living-numbers.webp", "image/webp", func(out io.Writer) error {
+				return writeAnimatedWebP(out, frames, delay)
+			})
+		}
+	}, w)
+}
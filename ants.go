@@ -0,0 +1,72 @@
+package main
+
+import (
+	"image"
+	"image/color"
+)
+
+// maxAnts is the largest number of ants offered by the Ants slider.
+const maxAnts = 20
+
+// ant is a Langton's ant walking the grid, heading one of four directions:
+// 0 up, 1 right, 2 down, 3 left.
+type ant struct {
+	x, y, dir int
+}
+
+var antMoves = [4]image.Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// setAnts keeps the first n ants, adding ants on random cells when there
+// are fewer.
+func (s *Simulation) setAnts(n int) {
+	if n <= len(s.ants) {
+		s.ants = s.ants[:n]
+		return
+	}
+	for len(s.ants) < n {
+		s.ants = append(s.ants, ant{s.rng.Intn(s.gridSize), s.rng.Intn(s.gridSize), s.rng.Intn(4)})
+	}
+}
+
+// moveAnts moves every ant one cell. An ant turns right on a cell of even
+// age (an empty cell included) and left on an odd one, then ages the cell
+// by one, a cell at maxCellAge dying: each visit flips the parity, as the
+// color of the classic ant's cell. Ants wrap around the grid edges.
+func (s *Simulation) moveAnts() {
+	for i := range s.ants {
+		a := &s.ants[i]
+		val := s.grid.at(a.x, a.y)
+		if val%2 == 0 {
+			a.dir = (a.dir + 1) % 4
+		} else {
+			a.dir = (a.dir + 3) % 4
+		}
+		s.setCell(a.x, a.y, (val+1)%(maxCellAge+1))
+		a.x = (a.x + antMoves[a.dir].X + s.gridSize) % s.gridSize
+		a.y = (a.y + antMoves[a.dir].Y + s.gridSize) % s.gridSize
+	}
+}
+
+// drawAnts marks every ant as a white cell outlined in black, the outline
+// being skipped on cells too small for it.
+func drawAnts(img *image.RGBA, ants []ant, cellSize int) {
+	white := color.RGBA{255, 255, 255, 255}
+	black := color.RGBA{0, 0, 0, 255}
+	bounds := img.Bounds()
+	for _, a := range ants {
+		for dy := 0; dy < cellSize; dy++ {
+			for dx := 0; dx < cellSize; dx++ {
+				p := image.Pt(bounds.Min.X+a.x*cellSize+dx, bounds.Min.Y+a.y*cellSize+dy)
+				if !p.In(bounds) {
+					continue
+				}
+				edge := dx == 0 || dy == 0 || dx == cellSize-1 || dy == cellSize-1
+				if edge && cellSize >= 4 {
+					img.SetRGBA(p.X, p.Y, black)
+				} else {
+					img.SetRGBA(p.X, p.Y, white)
+				}
+			}
+		}
+	}
+}
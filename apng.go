@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"io"
+	"time"
+)
+
+// pngChunk writes a PNG chunk with its CRC.
+func pngChunk(w io.Writer, kind string, data []byte) {
+	binary.Write(w, binary.BigEndian, uint32(len(data)))
+	crc := crc32.NewIEEE()
+	io.WriteString(crc, kind)
+	crc.Write(data)
+	io.WriteString(w, kind)
+	w.Write(data)
+	binary.Write(w, binary.BigEndian, crc.Sum32())
+}
+
+// apngImageData compresses img as 8-bit RGBA scanlines with the Sub filter,
+// which suits rows of flat cells.
+func apngImageData(img image.Image) []byte {
+	b := img.Bounds()
+	var buf bytes.Buffer
+	zw, _ := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	row := make([]byte, 1+4*b.Dx())
+	raw := make([]byte, 4*b.Dx())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row[0] = 1 // Sub
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			i := 4 * (x - b.Min.X)
+			cur := [4]byte{c.R, c.G, c.B, c.A}
+			for k := range 4 {
+				var left byte
+				if i > 0 {
+					left = raw[i-4+k]
+				}
+				row[1+i+k] = cur[k] - left
+				raw[i+k] = cur[k]
+			}
+		}
+		zw.Write(row)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+// writeAPNG writes frames as an endlessly looping animated PNG, each shown
+// for delay. Players without APNG support show the first frame.
+func writeAPNG(out io.Writer, frames frameSource, delay time.Duration) error {
+	if frames.count == 0 {
+		return errNoFrames
+	}
+	size := frames.frame(0).Bounds().Size()
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	ihdr := binary.BigEndian.AppendUint32(nil, uint32(size.X))
+	ihdr = binary.BigEndian.AppendUint32(ihdr, uint32(size.Y))
+	ihdr = append(ihdr, 8, 6, 0, 0, 0) // 8-bit RGBA, no interlace
+	pngChunk(&buf, "IHDR", ihdr)
+	actl := binary.BigEndian.AppendUint32(nil, uint32(frames.count))
+	actl = binary.BigEndian.AppendUint32(actl, 0) // loop forever
+	pngChunk(&buf, "acTL", actl)
+
+	seq := uint32(0)
+	for i := range frames.count {
+		fctl := binary.BigEndian.AppendUint32(nil, seq)
+		fctl = binary.BigEndian.AppendUint32(fctl, uint32(size.X))
+		fctl = binary.BigEndian.AppendUint32(fctl, uint32(size.Y))
+		fctl = binary.BigEndian.AppendUint32(fctl, 0) // x offset
+		fctl = binary.BigEndian.AppendUint32(fctl, 0) // y offset
+		fctl = binary.BigEndian.AppendUint16(fctl, uint16(delay.Milliseconds()))
+		fctl = binary.BigEndian.AppendUint16(fctl, 1000)
+		fctl = append(fctl, 0, 0) // no disposal, replace
+		pngChunk(&buf, "fcTL", fctl)
+		seq++
+		data := apngImageData(frames.frame(i))
+		if i == 0 {
+			pngChunk(&buf, "IDAT", data)
+			continue
+		}
+		pngChunk(&buf, "fdAT", append(binary.BigEndian.AppendUint32(nil, seq), data...))
+		seq++
+	}
+	pngChunk(&buf, "IEND", nil)
+	_, err := out.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Keyframe pins a parameter to a value at a given generation.
+type Keyframe struct {
+	generation int
+	value      float64
+}
+
+// AutomationCurve interpolates linearly between keyframes and holds the
+// first and last values outside their range.
+type AutomationCurve struct {
+	keyframes []Keyframe
+}
+
+// parseKeyframes reads "gen:value" pairs separated by commas, e.g.
+// "0:0.3, 400:0.05, 800:0.4".
+func parseKeyframes(text string) (AutomationCurve, error) {
+	var c AutomationCurve
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		genText, valueText, ok := strings.Cut(part, ":")
+		if !ok {
+			return c, fmt.Errorf("keyframe %q is not gen:value", part)
+		}
+		gen, err := strconv.Atoi(strings.TrimSpace(genText))
+		if err != nil || gen < 0 {
+			return c, fmt.Errorf("invalid generation in %q", part)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(valueText), 64)
+		if err != nil {
+			return c, fmt.Errorf("invalid value in %q", part)
+		}
+		c.keyframes = append(c.keyframes, Keyframe{gen, value})
+	}
+	sort.SliceStable(c.keyframes, func(i, j int) bool {
+		return c.keyframes[i].generation < c.keyframes[j].generation
+	})
+	return c, nil
+}
+
+func (c AutomationCurve) String() string {
+	parts := make([]string, len(c.keyframes))
+	for i, k := range c.keyframes {
+		parts[i] = fmt.Sprintf("%d:%g", k.generation, k.value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (c AutomationCurve) isEmpty() bool {
+	return len(c.keyframes) == 0
+}
+
+// valueAt returns the curve value at generation gen.
+func (c AutomationCurve) valueAt(gen int) float64 {
+	k := c.keyframes
+	if gen <= k[0].generation {
+		return k[0].value
+	}
+	for i := 1; i < len(k); i++ {
+		if gen <= k[i].generation {
+			span := float64(k[i].generation - k[i-1].generation)
+			t := float64(gen-k[i-1].generation) / span
+			return k[i-1].value + (k[i].value-k[i-1].value)*t
+		}
+	}
+	return k[len(k)-1].value
+}
+
+// isKeyframe reports whether a keyframe sits exactly on generation gen.
+func (c AutomationCurve) isKeyframe(gen int) bool {
+	for _, k := range c.keyframes {
+		if k.generation == gen {
+			return true
+		}
+	}
+	return false
+}
+
+// Automation holds the parameter curves applied during a run.
+type Automation struct {
+	enabled  bool
+	growth   AutomationCurve
+	mutation AutomationCurve
+}
+
+// apply updates the state's parameters for generation gen and returns the
+// messages to log when a keyframe is reached.
+func (a *Automation) apply(state *SimulationState, gen int) []string {
+	if !a.enabled {
+		return nil
+	}
+	var msgs []string
+	if !a.growth.isEmpty() {
+		state.growthRate = clampFloat(a.growth.valueAt(gen), 0.05, 0.5)
+		if a.growth.isKeyframe(gen) {
+			msgs = append(msgs, fmt.Sprintf("Growth rate keyframe: %.2f", state.growthRate))
+		}
+	}
+	if !a.mutation.isEmpty() {
+		state.mutationChance = clampFloat(a.mutation.valueAt(gen), 0, 0.1)
+		if a.mutation.isKeyframe(gen) {
+			msgs = append(msgs, fmt.Sprintf("Mutation keyframe: %.3f", state.mutationChance))
+		}
+	}
+	return msgs
+}
+
+func clampFloat(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// automationPresets are ready-made "story" experiments.
+var automationPresets = []struct {
+	name     string
+	growth   string
+	mutation string
+}{
+	{"Drought then abundance", "0:0.3, 300:0.05, 600:0.05, 900:0.4", ""},
+	{"Rising chaos", "", "0:0, 1000:0.08"},
+	{"Boom and bust", "0:0.4, 200:0.05, 400:0.4, 600:0.05, 800:0.4", "0:0.01"},
+}
+
+// showAutomationDialog edits the keyframes of the growth and mutation curves.
+func showAutomationDialog(w fyne.Window, state *SimulationState) {
+	state.mu.Lock()
+	current := state.automation
+	state.mu.Unlock()
+	auto := &current
+
+	growthEntry := widget.NewEntry()
+	growthEntry.SetPlaceHolder(lang.L("e.g. 0:0.3, 400:0.05, 800:0.4"))
+	growthEntry.SetText(auto.growth.String())
+	mutationEntry := widget.NewEntry()
+	mutationEntry.SetPlaceHolder(lang.L("e.g. 0:0, 1000:0.05"))
+	mutationEntry.SetText(auto.mutation.String())
+
+	enabledCheck := widget.NewCheck(lang.L("Apply automation during runs"), nil)
+	enabledCheck.Checked = auto.enabled
+
+	presetNames := make([]string, len(automationPresets))
+	for i, p := range automationPresets {
+		presetNames[i] = p.name
+	}
+	presetSelect := widget.NewSelect(localized(presetNames), func(shown string) {
+		name := unlocalized(presetNames, shown)
+		for _, p := range automationPresets {
+			if p.name == name {
+				growthEntry.SetText(p.growth)
+				mutationEntry.SetText(p.mutation)
+				enabledCheck.SetChecked(true)
+			}
+		}
+	})
+	presetSelect.PlaceHolder = lang.L("Load a preset...")
+
+	form := widget.NewForm(
+		widget.NewFormItem(lang.L("Growth keyframes"), growthEntry),
+		widget.NewFormItem(lang.L("Mutation keyframes"), mutationEntry),
+	)
+	content := container.NewVBox(
+		widget.NewLabel(lang.L("Keyframes are gen:value pairs, linearly interpolated.\nLeave a curve empty to keep its slider value.")),
+		form,
+		presetSelect,
+		enabledCheck,
+	)
+
+	d := dialog.NewCustomConfirm(lang.L("Parameter automation"), lang.L("Apply"), lang.L("Cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		growth, err := parseKeyframes(growthEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		mutation, err := parseKeyframes(mutationEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		state.mu.Lock()
+		state.automation = Automation{enabled: enabledCheck.Checked, growth: growth, mutation: mutation}
+		state.mu.Unlock()
+	}, w)
+	d.Resize(fyne.NewSize(450, 0))
+	d.Show()
+}
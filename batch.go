@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"os"
+	"os/signal"
+	"strconv"
+)
+
+// BatchConfig is a batch of independent runs of one parameter set, for
+// -batch.
+type BatchConfig struct {
+	growthRate     float64
+	mutationChance float64
+	runs           int
+	generations    int // limit of each run
+	gridSize       int
+	seed           int64 // run i uses seed+i
+}
+
+// BatchRun is the outcome of one run of a batch. A run ends when its grid
+// fills, when its population dies out, or at the generation limit.
+type BatchRun struct {
+	seed           int64
+	generations    int // when the run ended
+	filled         bool
+	extinct        bool
+	peakPopulation int
+	peakEntropy    float64
+	finalDensity   float64
+}
+
+// runBatchRun runs one seeded simulation of the batch.
+func runBatchRun(cfg BatchConfig, seed int64) BatchRun {
+	sim := newSimulation(cfg.gridSize, 0)
+	sim.growthRate = cfg.growthRate
+	sim.mutationChance = cfg.mutationChance
+	sim.reset(seed)
+	return measureRun(sim, cfg.generations, nil)
+}
+
+// measureRun steps a freshly seeded sim until its grid fills, its
+// population dies out or generations, calling each, when not nil, after
+// every generation.
+func measureRun(sim *Simulation, generations int, each func()) BatchRun {
+	run := BatchRun{seed: sim.seed, peakPopulation: sim.stats.population, peakEntropy: sim.stats.entropy}
+	for sim.generation < generations {
+		sim.step()
+		if each != nil {
+			each()
+		}
+		run.peakPopulation = max(run.peakPopulation, sim.stats.population)
+		run.peakEntropy = max(run.peakEntropy, sim.stats.entropy)
+		if sim.isFull() {
+			run.filled = true
+			break
+		}
+		if sim.stats.population == 0 {
+			run.extinct = true
+			break
+		}
+	}
+	run.generations = sim.generation
+	run.finalDensity = sim.stats.density
+	return run
+}
+
+// runBatch runs the batch, calling progress after each run. It stops early,
+// returning the runs done so far, when ctx is done.
+func runBatch(ctx context.Context, cfg BatchConfig, progress func(done, total int)) []BatchRun {
+	runs := make([]BatchRun, 0, cfg.runs)
+	for i := range cfg.runs {
+		if ctx.Err() != nil {
+			return runs
+		}
+		runs = append(runs, runBatchRun(cfg, cfg.seed+int64(i)))
+		if progress != nil {
+			progress(len(runs), cfg.runs)
+		}
+	}
+	return runs
+}
+
+// sampleStats is the mean and sample standard deviation of n values; the
+// deviation is 0 below two values.
+type sampleStats struct {
+	n            int
+	mean, stddev float64
+}
+
+func summarize(values []float64) sampleStats {
+	s := sampleStats{n: len(values)}
+	if s.n == 0 {
+		return s
+	}
+	for _, v := range values {
+		s.mean += v
+	}
+	s.mean /= float64(s.n)
+	if s.n < 2 {
+		return s
+	}
+	for _, v := range values {
+		s.stddev += (v - s.mean) * (v - s.mean)
+	}
+	s.stddev = math.Sqrt(s.stddev / float64(s.n-1))
+	return s
+}
+
+// BatchSummary is what a batch measured over its runs. The times to fill
+// and to extinction only count the runs that filled or died out.
+type BatchSummary struct {
+	runs             int
+	fillRate         float64
+	extinctionRate   float64
+	gensToFill       sampleStats
+	gensToExtinction sampleStats
+	peakPopulation   sampleStats
+	peakEntropy      sampleStats
+	finalDensity     sampleStats
+}
+
+func summarizeBatch(runs []BatchRun) BatchSummary {
+	var toFill, toExtinction, population, entropy, density []float64
+	for _, r := range runs {
+		if r.filled {
+			toFill = append(toFill, float64(r.generations))
+		}
+		if r.extinct {
+			toExtinction = append(toExtinction, float64(r.generations))
+		}
+		population = append(population, float64(r.peakPopulation))
+		entropy = append(entropy, r.peakEntropy)
+		density = append(density, r.finalDensity)
+	}
+	s := BatchSummary{
+		runs:             len(runs),
+		gensToFill:       summarize(toFill),
+		gensToExtinction: summarize(toExtinction),
+		peakPopulation:   summarize(population),
+		peakEntropy:      summarize(entropy),
+		finalDensity:     summarize(density),
+	}
+	if s.runs > 0 {
+		s.fillRate = float64(len(toFill)) / float64(s.runs)
+		s.extinctionRate = float64(len(toExtinction)) / float64(s.runs)
+	}
+	return s
+}
+
+// batchColumns are the columns of the summary CSV, one row per batch, so
+// the summaries of several parameter sets can be appended to one table.
+var batchColumns = []string{
+	"growth_rate", "mutation_chance", "grid_size", "max_generations", "seed", "runs",
+	"fill_rate", "extinction_rate",
+	"gens_to_fill_mean", "gens_to_fill_stddev",
+	"gens_to_extinction_mean", "gens_to_extinction_stddev",
+	"peak_population_mean", "peak_population_stddev",
+	"peak_entropy_mean", "peak_entropy_stddev",
+	"final_density_mean", "final_density_stddev",
+}
+
+// writeBatchCSV writes the summary of a batch as CSV, with a header unless
+// header is false. Means over no runs are left empty.
+func writeBatchCSV(w io.Writer, cfg BatchConfig, s BatchSummary, header bool) error {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', 6, 64) }
+	stat := func(st sampleStats) []string {
+		if st.n == 0 {
+			return []string{"", ""}
+		}
+		return []string{f(st.mean), f(st.stddev)}
+	}
+	row := []string{
+		f(cfg.growthRate), f(cfg.mutationChance), strconv.Itoa(cfg.gridSize), strconv.Itoa(cfg.generations),
+		strconv.FormatInt(cfg.seed, 10), strconv.Itoa(s.runs),
+		f(s.fillRate), f(s.extinctionRate),
+	}
+	for _, st := range []sampleStats{s.gensToFill, s.gensToExtinction, s.peakPopulation, s.peakEntropy, s.finalDensity} {
+		row = append(row, stat(st)...)
+	}
+	cw := csv.NewWriter(w)
+	if header {
+		cw.Write(batchColumns)
+	}
+	cw.Write(row)
+	cw.Flush()
+	return cw.Error()
+}
+
+// String is the summary as a few lines of text, for the terminal.
+func (s BatchSummary) String() string {
+	stat := func(st sampleStats, format string) string {
+		if st.n == 0 {
+			return "-"
+		}
+		return fmt.Sprintf(format+" ± "+format, st.mean, st.stddev)
+	}
+	return fmt.Sprintf("%d runs: %.0f%% filled, %.0f%% extinct\n"+
+		"generations to fill: %s\ngenerations to extinction: %s\n"+
+		"peak population: %s\npeak entropy: %s\nfinal density: %s",
+		s.runs, s.fillRate*100, s.extinctionRate*100,
+		stat(s.gensToFill, "%.1f"), stat(s.gensToExtinction, "%.1f"),
+		stat(s.peakPopulation, "%.1f"), stat(s.peakEntropy, "%.3f"), stat(s.finalDensity, "%.3f"))
+}
+
+// runBatchMode runs the -batch simulations of the parameters given on the
+// command line without opening a window. It prints their summary to stderr
+// and appends it to -batch-out, with a header when the file is new. Ctrl+C
+// ends the batch early, summarizing the runs done.
+func runBatchMode(opts launchOptions) error {
+	seed := opts.seed
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+	cfg := BatchConfig{
+		growthRate:     opts.growthRate,
+		mutationChance: opts.mutationChance,
+		runs:           opts.batch,
+		generations:    opts.batchGens,
+		gridSize:       gridSide(0, opts.cellSize),
+		seed:           seed,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	runs := runBatch(ctx, cfg, func(done, total int) {
+		slog.Info("batch run done", "run", done, "of", total)
+	})
+	summary := summarizeBatch(runs)
+	fmt.Fprintf(os.Stderr, "Seed %d, %dx%d grid\n%s\n", cfg.seed, cfg.gridSize, cfg.gridSize, summary)
+
+	if opts.batchOut == "-" {
+		return writeBatchCSV(os.Stdout, cfg, summary, true)
+	}
+	f, err := os.OpenFile(opts.batchOut, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := writeBatchCSV(f, cfg, summary, info.Size() == 0); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
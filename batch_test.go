@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	s := summarize([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	if s.n != 8 || s.mean != 5 || math.Abs(s.stddev-2.138) > 0.001 {
+		t.Errorf("summarize: %+v, want mean 5, stddev 2.138", s)
+	}
+	if s := summarize([]float64{3}); s.mean != 3 || s.stddev != 0 {
+		t.Errorf("one value: %+v, want mean 3, stddev 0", s)
+	}
+	if s := summarize(nil); s.n != 0 || s.mean != 0 {
+		t.Errorf("no values: %+v", s)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	cfg := BatchConfig{growthRate: 0.3, mutationChance: 0.01, runs: 4, generations: 300, gridSize: 30, seed: 42}
+	runs := runBatch(context.Background(), cfg, nil)
+	if len(runs) != 4 || runs[3].seed != 45 {
+		t.Fatalf("runs %+v, want 4 from seed 42", runs)
+	}
+	if again := runBatch(context.Background(), cfg, nil); !slices.Equal(again, runs) {
+		t.Errorf("the same batch ran differently: %+v then %+v", runs, again)
+	}
+	for _, r := range runs {
+		if r.filled && r.extinct || r.generations > cfg.generations || r.peakPopulation > cfg.gridSize*cfg.gridSize {
+			t.Errorf("inconsistent run %+v", r)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if runs := runBatch(ctx, cfg, nil); len(runs) != 0 {
+		t.Errorf("%d runs after cancelling", len(runs))
+	}
+
+	summary := summarizeBatch([]BatchRun{
+		{generations: 100, filled: true, peakPopulation: 900, finalDensity: 1},
+		{generations: 200, filled: true, peakPopulation: 900, finalDensity: 1},
+		{generations: 50, extinct: true, peakPopulation: 300},
+		{generations: 300, peakPopulation: 600, finalDensity: 0.5},
+	})
+	if summary.fillRate != 0.5 || summary.extinctionRate != 0.25 || summary.gensToFill.mean != 150 || summary.peakPopulation.mean != 675 {
+		t.Errorf("summary %+v", summary)
+	}
+
+	var b strings.Builder
+	if err := writeBatchCSV(&b, cfg, summary, true); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil || len(rows) != 2 || len(rows[1]) != len(batchColumns) {
+		t.Fatalf("CSV %q: %v", b.String(), err)
+	}
+	row := map[string]string{}
+	for i, col := range batchColumns {
+		row[col] = rows[1][i]
+	}
+	if row["seed"] != "42" || row["fill_rate"] != "0.5" || row["gens_to_fill_mean"] != "150" || row["gens_to_extinction_stddev"] != "0" {
+		t.Errorf("CSV row %v", row)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// birthSums are the neighbour sums of the points of the birth curve.
+var birthSums = [...]float64{0, 25, 50, 100, 150, 200, 300, 400}
+
+// birthCurve is the birth weight of an empty cell at each of birthSums,
+// interpolated linearly in between and flat beyond the last point: the cell
+// is born with the chance growth rate × weight.
+type birthCurve [len(birthSums)]float64
+
+// defaultBirthCurve is the classic weight of the built-in rules, sum/50.
+var defaultBirthCurve = birthCurve{0, 0.5, 1, 2, 3, 4, 6, 8}
+
+// birthCurveTop is the highest weight the editor offers.
+const birthCurveTop = 8
+
+// weight is the birth weight at a neighbour sum.
+func (c *birthCurve) weight(sum float64) float64 {
+	for i := 1; i < len(birthSums); i++ {
+		if sum <= birthSums[i] {
+			t := (sum - birthSums[i-1]) / (birthSums[i] - birthSums[i-1])
+			return c[i-1] + t*(c[i]-c[i-1])
+		}
+	}
+	return c[len(c)-1]
+}
+
+// showBirthCurveDialog edits the birth curve; changes apply to a running
+// simulation at once.
+func showBirthCurveDialog(w fyne.Window, state *SimulationState) {
+	state.mu.Lock()
+	curve := state.birthCurve
+	state.mu.Unlock()
+	values := curve[:]
+	labels := make([]string, len(birthSums))
+	for i, sum := range birthSums {
+		labels[i] = fmt.Sprint(sum)
+	}
+
+	editor := newCurveEditor(values, labels, birthCurveTop, func(i int, v float64) {
+		state.mu.Lock()
+		state.birthCurve[i] = v
+		state.mu.Unlock()
+		logParam(fmt.Sprintf("birth_weight_%v", birthSums[i]), v)
+	})
+	reset := widget.NewButton(lang.L("Reset"), func() {
+		state.mu.Lock()
+		state.birthCurve = defaultBirthCurve
+		state.mu.Unlock()
+		copy(values, defaultBirthCurve[:])
+		editor.Refresh()
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel(lang.L("Birth weight by neighbour sum: an empty cell is born\nwith the chance growth rate × weight. The default is sum/50.")),
+		editor,
+		container.NewHBox(reset),
+	)
+	d := dialog.NewCustom(lang.L("🐣 Birth curve"), lang.L("Close"), content, w)
+	d.Resize(fyne.NewSize(560, 320))
+	d.Show()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"image"
+	"sync"
+)
+
+// topic delivers the events of type T to its subscribers, in the order they
+// subscribed, on the goroutine that publishes them. Subscribers touching
+// widgets hop to the UI thread themselves.
+type topic[T any] struct {
+	mu          sync.Mutex
+	subscribers []func(T)
+}
+
+func (t *topic[T]) subscribe(fn func(T)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.subscribers = append(t.subscribers, fn)
+}
+
+func (t *topic[T]) publish(e T) {
+	t.mu.Lock()
+	subscribers := t.subscribers
+	t.mu.Unlock()
+	for _, fn := range subscribers {
+		fn(e)
+	}
+}
+
+// eventBus is what runs report to their frontends: the window, the stats
+// and OSC outputs, and any headless or remote frontend, which subscribe to
+// its topics rather than being called by the simulation goroutine. The
+// goroutine publishes without state.mu held, except eventLogged, published
+// by addEvent from wherever an event happens: its subscribers must neither
+// take state.mu nor wait for the UI thread.
+type eventBus struct {
+	statsUpdated topic[statsUpdate]
+	frameReady   topic[frameReady]
+	eventLogged  topic[Event]
+	runEnded     topic[runEnd]
+}
+
+// statsUpdate is published after every generation of a run.
+type statsUpdate struct {
+	stats Stats
+	// labels is set when they are due, at most labelRate times a
+	// second, and whenever a trigger fires; nil otherwise.
+	labels    *statsLabels
+	triggered bool   // a trigger fired this generation
+	paused    bool   // the run paused itself, for a trigger or a scenario
+	scenario  string // message of the scenario that finished, if any
+}
+
+// statsLabels is the content of the statistics labels and charts.
+type statsLabels struct {
+	status, stats, events, scenario string
+	rebirths                        []int // per generation, oldest first
+	ages                            [maxCellAge]int
+	palette                         ColorPalette
+	// the automation changed the growth rate and mutation chance in the
+	// model
+	automated bool
+}
+
+// frameReady is published once a drawn generation is ready to show. The
+// frame is one of the offscreen buffers: the display hands it back, see
+// frameBuffers.present.
+type frameReady struct {
+	frame      *image.RGBA
+	generation int
+}
+
+// runEnd is published when a run ends by itself, its grid full, with its
+// last frame and statistics.
+type runEnd struct {
+	message  string
+	frame    *image.RGBA
+	rebirths []int
+	ages     [maxCellAge]int
+	palette  ColorPalette
+}
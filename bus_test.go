@@ -0,0 +1,32 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTopicDeliversInOrder(t *testing.T) {
+	var tp topic[int]
+	var got []string
+	tp.subscribe(func(n int) { got = append(got, "a", string(rune('0'+n))) })
+	tp.subscribe(func(n int) { got = append(got, "b", string(rune('0'+n))) })
+	tp.publish(1)
+	tp.publish(2)
+	if want := []string{"a", "1", "b", "1", "a", "2", "b", "2"}; !slices.Equal(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+}
+
+func TestAddEventPublishes(t *testing.T) {
+	state := &SimulationState{events: newEventHistory(10, nil)}
+	var logged []Event
+	state.bus.eventLogged.subscribe(func(e Event) { logged = append(logged, e) })
+	state.stats.generation = 7
+	addEvent(state, "TEST", "published")
+	if len(logged) != 1 || logged[0].eventType != "TEST" || logged[0].generation != 7 {
+		t.Fatalf("logged %+v", logged)
+	}
+	if recent := state.events.recent(1); len(recent) != 1 || recent[0] != logged[0] {
+		t.Errorf("history holds %+v, want the published event", recent)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// catastropheKinds are the disasters the scheduler can set off.
+var catastropheKinds = []string{"Supernova", "Epidemic"}
+
+// Catastrophes sets off a disaster at regular or random intervals, for
+// long resilience experiments.
+type Catastrophes struct {
+	enabled bool
+	kind    string // one of catastropheKinds
+	minGap  int    // generations between two disasters, drawn from
+	maxGap  int    // minGap to maxGap inclusive
+	next    int    // generation of the next disaster, 0 until scheduled
+}
+
+// parseGaps reads an interval, either "N" generations or a "MIN-MAX" range.
+func parseGaps(text string) (minGap, maxGap int, err error) {
+	lo, hi, isRange := strings.Cut(text, "-")
+	minGap, err = strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil || minGap <= 0 {
+		return 0, 0, fmt.Errorf("invalid interval %q", text)
+	}
+	if !isRange {
+		return minGap, minGap, nil
+	}
+	maxGap, err = strconv.Atoi(strings.TrimSpace(hi))
+	if err != nil || maxGap < minGap {
+		return 0, 0, fmt.Errorf("invalid interval %q", text)
+	}
+	return minGap, maxGap, nil
+}
+
+func (c Catastrophes) gaps() string {
+	if c.maxGap > c.minGap {
+		return fmt.Sprintf("%d-%d", c.minGap, c.maxGap)
+	}
+	return strconv.Itoa(c.minGap)
+}
+
+// due reports whether a disaster strikes at this generation, scheduling the
+// next one when it does or when none is scheduled yet. rng is the run's
+// source, so a seeded run strikes at the same generations.
+func (c *Catastrophes) due(generation int, rng Rand) bool {
+	if !c.enabled {
+		return false
+	}
+	struck := c.next > 0 && generation >= c.next
+	if c.next == 0 || struck {
+		c.next = generation + c.minGap + rng.Intn(c.maxGap-c.minGap+1)
+	}
+	return struck
+}
+
+func showCatastrophesDialog(w fyne.Window, state *SimulationState) {
+	state.mu.Lock()
+	current := state.catastrophes
+	state.mu.Unlock()
+
+	kindSelect := widget.NewSelect(localized(catastropheKinds), nil)
+	kindSelect.SetSelected(lang.L(current.kind))
+	gapEntry := widget.NewEntry()
+	gapEntry.SetPlaceHolder(lang.L("e.g. 200 or 100-300"))
+	if current.minGap > 0 {
+		gapEntry.SetText(current.gaps())
+	}
+	enabledCheck := widget.NewCheck(lang.L("Schedule disasters during runs"), nil)
+	enabledCheck.Checked = current.enabled
+
+	form := widget.NewForm(
+		widget.NewFormItem(lang.L("Disaster"), kindSelect),
+		widget.NewFormItem(lang.L("Every (generations)"), gapEntry),
+	)
+	content := container.NewVBox(
+		widget.NewLabel(lang.L("A fixed interval, or a range the interval is drawn from.\nEpidemics only strike the built-in rules.")),
+		form,
+		enabledCheck,
+	)
+
+	d := dialog.NewCustomConfirm(lang.L("Scheduled catastrophes"), lang.L("Apply"), lang.L("Cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		minGap, maxGap, err := parseGaps(gapEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		state.mu.Lock()
+		state.catastrophes = Catastrophes{
+			enabled: enabledCheck.Checked,
+			kind:    unlocalized(catastropheKinds, kindSelect.Selected),
+			minGap:  minGap,
+			maxGap:  maxGap,
+		}
+		state.mu.Unlock()
+	}, w)
+	d.Resize(fyne.NewSize(450, 0))
+	d.Show()
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"encoding/csv"
+	"image"
+	"image/color"
+	"io"
+	"slices"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// drawBarChart renders values as vertical bars scaled to the largest value,
+// newest on the right. Older values than fit in the image are skipped.
+func drawBarChart(img *image.RGBA, values []int, bg, fg color.RGBA) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(bounds.Min.X+x, bounds.Min.Y+y, bg)
+		}
+	}
+
+	if len(values) > w {
+		values = values[len(values)-w:]
+	}
+	maxVal := 1
+	for _, v := range values {
+		if v > maxVal {
+			maxVal = v
+		}
+	}
+
+	offset := w - len(values)
+	for i, v := range values {
+		barHeight := v * h / maxVal
+		for y := h - barHeight; y < h; y++ {
+			img.SetRGBA(bounds.Min.X+offset+i, bounds.Min.Y+y, fg)
+		}
+	}
+}
+
+// drawAgePyramid renders an age histogram as a population pyramid: one band
+// per age, the youngest at the bottom, centered and as wide as its cohort
+// relative to the largest one, in the palette's color for that age.
+func drawAgePyramid(img *image.RGBA, ages [maxCellAge]int, palette ColorPalette, bg color.RGBA) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	largest := 1
+	for _, n := range ages {
+		largest = max(largest, n)
+	}
+	lut := cellColorTable(palette)
+	for y := 0; y < h; y++ {
+		age := (h-1-y)*maxCellAge/h + 1
+		half := ages[age-1] * w / 2 / largest
+		if ages[age-1] > 0 {
+			half = max(half, 1)
+		}
+		c := lut[age]
+		for x := 0; x < w; x++ {
+			col := bg
+			if x >= w/2-half && x < w/2+half {
+				col = color.RGBA{c[0], c[1], c[2], 255}
+			}
+			img.SetRGBA(bounds.Min.X+x, bounds.Min.Y+y, col)
+		}
+	}
+}
+
+// drawRebirthFlash paints every cell reborn during the last generation white.
+func drawRebirthFlash(img *image.RGBA, reborn [][]bool, cellSize int) {
+	white := color.RGBA{255, 255, 255, 255}
+	for y := range reborn {
+		for x := range reborn[y] {
+			if !reborn[y][x] {
+				continue
+			}
+			for dy := 0; dy < cellSize; dy++ {
+				for dx := 0; dx < cellSize; dx++ {
+					img.SetRGBA(x*cellSize+dx, y*cellSize+dy, white)
+				}
+			}
+		}
+	}
+}
+
+// maxHistoryPoints bounds the series of a StatsHistory.
+const maxHistoryPoints = 8192
+
+// StatsHistory keeps the per-generation series plotted in the charts pane.
+// The parameters in effect are recorded too, so automated runs can be
+// reviewed afterwards. Once the series reach maxHistoryPoints every other
+// point is dropped and only every stride-th generation is recorded from
+// then on, so long runs keep their whole shape in bounded memory.
+type StatsHistory struct {
+	generations []int
+	population  []int
+	births      []int
+	entropy     []float64
+	avgAge      []float64
+	growth      []float64
+	mutation    []float64
+	prey        []float64 // Wa-Tor fish and sharks, as fractions of the grid
+	predators   []float64
+	species     bool // some generation had fish or sharks
+	stride      int  // generations per point, 1 (or 0) until thinned
+	skipped     int  // generations not recorded since the last point
+}
+
+func (h *StatsHistory) add(s Stats, growthRate, mutationChance float64, cells int) {
+	h.species = h.species || s.prey+s.predators > 0
+	if h.skipped+1 < h.stride {
+		h.skipped++
+		return
+	}
+	h.skipped = 0
+	h.generations = append(h.generations, s.generation)
+	h.population = append(h.population, s.population)
+	h.births = append(h.births, s.births)
+	h.entropy = append(h.entropy, s.entropy)
+	h.avgAge = append(h.avgAge, s.avgAge)
+	h.growth = append(h.growth, growthRate)
+	h.mutation = append(h.mutation, mutationChance)
+	h.prey = append(h.prey, float64(s.prey)/float64(cells))
+	h.predators = append(h.predators, float64(s.predators)/float64(cells))
+	if len(h.generations) >= maxHistoryPoints {
+		h.thin()
+	}
+}
+
+// clone returns a copy of the history.
+func (h *StatsHistory) clone() *StatsHistory {
+	c := *h
+	c.generations = slices.Clone(h.generations)
+	c.population = slices.Clone(h.population)
+	c.births = slices.Clone(h.births)
+	c.entropy = slices.Clone(h.entropy)
+	c.avgAge = slices.Clone(h.avgAge)
+	c.growth = slices.Clone(h.growth)
+	c.mutation = slices.Clone(h.mutation)
+	c.prey = slices.Clone(h.prey)
+	c.predators = slices.Clone(h.predators)
+	return &c
+}
+
+// thin drops every other point and halves the recording rate.
+func (h *StatsHistory) thin() {
+	h.generations = thinOut(h.generations)
+	h.population = thinOut(h.population)
+	h.births = thinOut(h.births)
+	h.entropy = thinOut(h.entropy)
+	h.avgAge = thinOut(h.avgAge)
+	h.growth = thinOut(h.growth)
+	h.mutation = thinOut(h.mutation)
+	h.prey = thinOut(h.prey)
+	h.predators = thinOut(h.predators)
+	h.stride = max(h.stride, 1) * 2
+}
+
+// thinOut keeps the points of s at even indices, in place.
+func thinOut[T any](s []T) []T {
+	n := 0
+	for i := 0; i < len(s); i += 2 {
+		s[n] = s[i]
+		n++
+	}
+	return s[:n]
+}
+
+func (h *StatsHistory) reset() {
+	h.generations = h.generations[:0]
+	h.population = h.population[:0]
+	h.births = h.births[:0]
+	h.entropy = h.entropy[:0]
+	h.avgAge = h.avgAge[:0]
+	h.growth = h.growth[:0]
+	h.mutation = h.mutation[:0]
+	h.prey = h.prey[:0]
+	h.predators = h.predators[:0]
+	h.species = false
+	h.stride, h.skipped = 1, 0
+}
+
+// writeCSV exports the recorded series, one recorded generation per row.
+func (h *StatsHistory) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"generation", "population", "births", "entropy", "avg_age", "growth_rate", "mutation_chance"}); err != nil {
+		return err
+	}
+	for i, g := range h.generations {
+		row := []string{
+			strconv.Itoa(g),
+			strconv.Itoa(h.population[i]),
+			strconv.Itoa(h.births[i]),
+			strconv.FormatFloat(h.entropy[i], 'f', 4, 64),
+			strconv.FormatFloat(h.avgAge[i], 'f', 3, 64),
+			strconv.FormatFloat(h.growth[i], 'f', 4, 64),
+			strconv.FormatFloat(h.mutation[i], 'f', 4, 64),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// drawLineChart plots each series as a polyline over the last points that
+// fit in the image; values are divided by scale so that 1 means the top edge.
+func drawLineChart(img *image.RGBA, series [][]float64, scales []float64, colors []color.RGBA, bg color.RGBA) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(bounds.Min.X+x, bounds.Min.Y+y, bg)
+		}
+	}
+
+	// Quarter grid lines
+	gridColor := color.RGBA{60, 60, 60, 255}
+	for q := 1; q < 4; q++ {
+		y := h * q / 4
+		for x := 0; x < w; x++ {
+			img.SetRGBA(bounds.Min.X+x, bounds.Min.Y+y, gridColor)
+		}
+	}
+
+	toY := func(v, scale float64) int {
+		y := h - 1 - int(v/scale*float64(h-1))
+		if y < 0 {
+			y = 0
+		}
+		if y >= h {
+			y = h - 1
+		}
+		return y
+	}
+
+	for si, values := range series {
+		if len(values) > w {
+			values = values[len(values)-w:]
+		}
+		offset := w - len(values)
+		prevY := -1
+		for i, v := range values {
+			y := toY(v, scales[si])
+			x := bounds.Min.X + offset + i
+			// Join consecutive points with a vertical run so steep changes stay visible
+			from, to := y, y
+			if prevY >= 0 {
+				from, to = min(prevY, y), max(prevY, y)
+			}
+			for yy := from; yy <= to; yy++ {
+				img.SetRGBA(x, bounds.Min.Y+yy, colors[si])
+			}
+			prevY = y
+		}
+	}
+}
+
+var (
+	entropyColor   = color.RGBA{80, 200, 255, 255}
+	avgAgeColor    = color.RGBA{255, 170, 40, 255}
+	preyColor      = color.RGBA{120, 230, 120, 255}
+	predatorsColor = color.RGBA{240, 80, 80, 255}
+)
+
+// seriesPane is the charts tab plotting entropy and average age over time,
+// and the fish and shark counts of Wa-Tor runs.
+type seriesPane struct {
+	history   *StatsHistory
+	state     *SimulationState
+	img       *image.RGBA
+	canvasImg *canvas.Image
+}
+
+func newSeriesPane(history *StatsHistory, state *SimulationState) *seriesPane {
+	p := &seriesPane{
+		history: history,
+		state:   state,
+		img:     image.NewRGBA(image.Rect(0, 0, displaySize, 200)),
+	}
+	p.canvasImg = canvas.NewImageFromImage(p.img)
+	p.canvasImg.FillMode = canvas.ImageFillStretch
+	p.canvasImg.SetMinSize(fyne.NewSize(float32(displaySize), 200))
+	p.render()
+	return p
+}
+
+// render redraws the chart image; call Refresh on canvasImg afterwards.
+func (p *seriesPane) render() {
+	series := [][]float64{p.history.entropy, p.history.avgAge}
+	scales := []float64{1, 50}
+	colors := []color.RGBA{entropyColor, avgAgeColor}
+	if p.history.species {
+		series = append(series, p.history.prey, p.history.predators)
+		scales = append(scales, 1, 1)
+		colors = append(colors, preyColor, predatorsColor)
+	}
+	drawLineChart(p.img, series, scales, colors, color.RGBA{20, 20, 20, 255})
+}
+
+func (p *seriesPane) content(w fyne.Window) fyne.CanvasObject {
+	swatch := func(c color.RGBA, text string) fyne.CanvasObject {
+		r := canvas.NewRectangle(c)
+		r.SetMinSize(fyne.NewSize(12, 12))
+		return container.NewHBox(r, widget.NewLabel(text))
+	}
+
+	// The series are copied under the lock and written without it, so the
+	// simulation does not wait for the disk
+	exportButton := widget.NewButton(lang.L("💾 Export CSV"), func() {
+		saveFile(w, "stats.csv", "text/csv", func(out io.Writer) error {
+			p.state.mu.Lock()
+			history := p.history.clone()
+			p.state.mu.Unlock()
+			return history.writeCSV(out)
+		})
+	})
+
+	midiButton := widget.NewButton(lang.L("🎵 Export MIDI"), func() {
+		saveFile(w, "run.mid", "audio/midi", func(out io.Writer) error {
+			events, err := p.state.events.all()
+			if err != nil {
+				return err
+			}
+			p.state.mu.Lock()
+			history := p.history.clone()
+			cells := p.state.gridSize * p.state.gridSize
+			p.state.mu.Unlock()
+			return writeRunMIDI(out, history, events, cells)
+		})
+	})
+
+	return container.NewVBox(
+		widget.NewLabel(lang.L("📈 Entropy & average age over generations")),
+		widget.NewSeparator(),
+		p.canvasImg,
+		container.NewHBox(swatch(entropyColor, lang.L("Entropy (0-1)")), swatch(avgAgeColor, lang.L("Avg age (0-50)"))),
+		container.NewHBox(swatch(preyColor, lang.L("Fish (Wa-Tor)")), swatch(predatorsColor, lang.L("Sharks (Wa-Tor)"))),
+		container.NewHBox(exportButton, midiButton),
+	)
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestStatsHistoryIsBounded(t *testing.T) {
+	var h StatsHistory
+	for g := 1; g <= 5*maxHistoryPoints; g++ {
+		h.add(Stats{generation: g, population: g}, 0.1, 0.01, 100)
+	}
+	n := len(h.generations)
+	if n >= maxHistoryPoints || n < maxHistoryPoints/4 {
+		t.Fatalf("%d points after %d generations, want at most %d", n, 5*maxHistoryPoints, maxHistoryPoints)
+	}
+	if h.generations[0] != 1 || h.generations[n-1] < 5*maxHistoryPoints-h.stride {
+		t.Errorf("points span generations %d-%d, want the whole run", h.generations[0], h.generations[n-1])
+	}
+	for i, g := range h.generations {
+		if h.population[i] != g {
+			t.Fatalf("point %d: population %d at generation %d, series out of step", i, h.population[i], g)
+		}
+	}
+	h.reset()
+	h.add(Stats{generation: 1}, 0.1, 0.01, 100)
+	h.add(Stats{generation: 2}, 0.1, 0.01, 100)
+	if len(h.generations) != 2 {
+		t.Errorf("%d points after a reset, want every generation again", len(h.generations))
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// defaultCheckpointInterval is the number of generations between two
+// checkpoints; the -checkpoint flag changes it.
+const defaultCheckpointInterval = 100
+
+// maxCheckpoint bounds the decompressed size of a checkpoint read, far
+// above that of the largest grid, so a corrupt or hostile file cannot
+// exhaust memory.
+const maxCheckpoint = 64 << 20
+
+// Checkpoint is a compact copy of a run: its parameters plus one byte per
+// cell (ages never exceed maxCellAge), and one per cell for the fixtures
+// when any were placed.
+type Checkpoint struct {
+	Saved          time.Time `json:"saved"`
+	Seed           int64     `json:"seed"`
+	Generation     int       `json:"generation"`
+	TotalRebirths  int       `json:"total_rebirths"`
+	GridSize       int       `json:"grid_size"`
+	CellSize       int       `json:"cell_size"`
+	GrowthRate     float64   `json:"growth_rate"`
+	MutationChance float64   `json:"mutation_chance"`
+	Speed          int       `json:"speed"`
+	PaletteMode    int       `json:"palette_mode"`
+	Symmetry       string    `json:"symmetry"`
+	Rule           string    `json:"rule,omitempty"` // rule family, empty for the built-in rules
+	Cells          []byte    `json:"cells"`
+	Fixtures       []byte    `json:"fixtures,omitempty"`
+}
+
+// checkpointStore keeps the latest checkpoint and a marker file that exists
+// while the application runs, so a crash can be told from a clean exit.
+type checkpointStore struct {
+	dir string
+}
+
+func openCheckpointStore() (*checkpointStore, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(base, "living-numbers")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &checkpointStore{dir: dir}, nil
+}
+
+func (s *checkpointStore) path() string {
+	return filepath.Join(s.dir, "checkpoint.json.gz")
+}
+
+func (s *checkpointStore) markerPath() string {
+	return filepath.Join(s.dir, "running")
+}
+
+// begin marks the session as running and reports whether the previous
+// session was still marked, i.e. did not exit cleanly.
+func (s *checkpointStore) begin() (crashed bool, err error) {
+	_, err = os.Stat(s.markerPath())
+	crashed = err == nil
+	return crashed, os.WriteFile(s.markerPath(), []byte(time.Now().Format(time.RFC3339)), 0o644)
+}
+
+// end clears the running marker on a clean exit.
+func (s *checkpointStore) end() error {
+	err := os.Remove(s.markerPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// captureCheckpoint copies the simulation and state into a checkpoint. The
+// caller holds state.mu.
+func captureCheckpoint(sim *Simulation, state *SimulationState) Checkpoint {
+	cells := slices.Clone(sim.grid.cells)
+	var fixtures []byte
+	for _, f := range sim.fixtures {
+		fixtures = append(fixtures, byte(f))
+	}
+	return Checkpoint{
+		Saved:          time.Now(),
+		Seed:           sim.seed,
+		Generation:     sim.generation,
+		TotalRebirths:  sim.totalRebirths,
+		GridSize:       sim.gridSize,
+		CellSize:       state.cellSize,
+		GrowthRate:     state.growthRate,
+		MutationChance: state.mutationChance,
+		Speed:          state.speed,
+		PaletteMode:    state.paletteMode,
+		Symmetry:       state.symmetry.String(),
+		Rule:           ruleName(sim.rule),
+		Cells:          cells,
+		Fixtures:       fixtures,
+	}
+}
+
+// save writes cp through a temporary file, so a crash while writing never
+// leaves a truncated checkpoint behind.
+func (s *checkpointStore) save(cp Checkpoint) error {
+	tmp, err := os.CreateTemp(s.dir, "checkpoint-*.tmp")
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(tmp)
+	err = json.NewEncoder(zw).Encode(cp)
+	if err == nil {
+		err = zw.Close()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path())
+}
+
+// load returns the latest checkpoint, or nil if there is none.
+func (s *checkpointStore) load() (*Checkpoint, error) {
+	f, err := os.Open(s.path())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readCheckpoint(f)
+}
+
+// readCheckpoint decodes and checks a checkpoint file.
+func readCheckpoint(r io.Reader) (*Checkpoint, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.NewDecoder(io.LimitReader(zr, maxCheckpoint)).Decode(&cp); err != nil {
+		return nil, err
+	}
+	// Divided rather than squared, which could overflow into a match
+	if cp.GridSize <= 0 || len(cp.Cells)%cp.GridSize != 0 || len(cp.Cells)/cp.GridSize != cp.GridSize {
+		return nil, fmt.Errorf("corrupt checkpoint: %d cells for a %dx%d grid", len(cp.Cells), cp.GridSize, cp.GridSize)
+	}
+	if cp.Fixtures != nil && len(cp.Fixtures) != len(cp.Cells) {
+		return nil, fmt.Errorf("corrupt checkpoint: %d fixtures for a %dx%d grid", len(cp.Fixtures), cp.GridSize, cp.GridSize)
+	}
+	for _, f := range cp.Fixtures {
+		if fixture(f) > blackHole {
+			return nil, fmt.Errorf("corrupt checkpoint: unknown fixture %d", f)
+		}
+	}
+	return &cp, nil
+}
+
+// restore puts the checkpointed grid into sim. The random stream is not
+// part of a checkpoint, so it is reseeded from the seed and generation, and
+// continuous rule families resume at the precision of the cell values.
+func (cp *Checkpoint) restore(sim *Simulation) {
+	sim.resize(cp.GridSize)
+	sim.seed = cp.Seed
+	sim.rng = sim.newRand(cp.Seed + int64(cp.Generation))
+	for i, c := range cp.Cells {
+		sim.grid.cells[i] = min(c, maxCellAge)
+	}
+	for i, f := range cp.Fixtures {
+		if f != byte(noFixture) {
+			sim.placeFixture(i%cp.GridSize, i/cp.GridSize, fixture(f))
+		}
+	}
+	if sim.energy != nil {
+		sim.fillEnergy()
+	}
+	sim.rule = newRuleFamily(cp.Rule)
+	if sim.rule != nil {
+		sim.rule.load(sim)
+	}
+	sim.generation = cp.Generation
+	sim.totalRebirths = cp.TotalRebirths
+	sim.tally.count(sim.grid)
+	sim.stats = sim.tally.stats(sim.generation, sim.gridSize)
+	if r, ok := sim.rule.(statsReporter); ok {
+		r.report(&sim.stats)
+	}
+	sim.updateColonies()
+}
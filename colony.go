@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2/lang"
+)
+
+func newLabelGrid(size int) [][]int {
+	grid := make([][]int, size)
+	for i := range grid {
+		grid[i] = make([]int, size)
+	}
+	return grid
+}
+
+// findColonies labels 8-connected groups of living cells. labels[y][x] gets
+// the 1-based colony id (0 for dead cells) and sizes[id-1] its cell count.
+func findColonies(grid *Grid, labels [][]int) []int {
+	for y := range labels {
+		for x := range labels[y] {
+			labels[y][x] = 0
+		}
+	}
+
+	var sizes []int
+	var stack []image.Point
+	n := grid.size
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if grid.at(x, y) == 0 || labels[y][x] != 0 {
+				continue
+			}
+			id := len(sizes) + 1
+			size := 0
+			labels[y][x] = id
+			stack = append(stack[:0], image.Pt(x, y))
+			for len(stack) > 0 {
+				p := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				size++
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						nx, ny := p.X+dx, p.Y+dy
+						if nx < 0 || ny < 0 || ny >= n || nx >= n {
+							continue
+						}
+						if grid.at(nx, ny) > 0 && labels[ny][nx] == 0 {
+							labels[ny][nx] = id
+							stack = append(stack, image.Pt(nx, ny))
+						}
+					}
+				}
+			}
+			sizes = append(sizes, size)
+		}
+	}
+	return sizes
+}
+
+// colonySizeBuckets groups colony sizes into 1, 2-9, 10-99 and 100+ cells.
+func colonySizeBuckets(sizes []int) [4]int {
+	var b [4]int
+	for _, s := range sizes {
+		switch {
+		case s == 1:
+			b[0]++
+		case s < 10:
+			b[1]++
+		case s < 100:
+			b[2]++
+		default:
+			b[3]++
+		}
+	}
+	return b
+}
+
+func colonySummary(sizes []int) string {
+	largest := 0
+	for _, s := range sizes {
+		if s > largest {
+			largest = s
+		}
+	}
+	b := colonySizeBuckets(sizes)
+	return fmt.Sprintf(lang.L("Colonies: %d (largest %d)\nSizes 1/2-9/10-99/100+: %d/%d/%d/%d"),
+		len(sizes), largest, b[0], b[1], b[2], b[3])
+}
+
+// colonyColor spreads ids around the hue circle using the golden angle so
+// neighbouring ids get clearly different colors.
+func colonyColor(id int) color.RGBA {
+	hue := math.Mod(float64(id)*137.508, 360)
+	return hsvToRGBA(hue, 0.75, 0.95)
+}
+
+func hsvToRGBA(h, s, v float64) color.RGBA {
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return color.RGBA{uint8((r + m) * 255), uint8((g + m) * 255), uint8((b + m) * 255), 255}
+}
+
+// drawColonies renders each colony in its own hue, dead cells with the
+// palette's dead color.
+func drawColonies(labels [][]int, img *image.RGBA, dead color.Color, cellSize int) {
+	for y := range labels {
+		for x := range labels[y] {
+			var c color.Color = dead
+			if id := labels[y][x]; id > 0 {
+				c = colonyColor(id)
+			}
+			for dy := 0; dy < cellSize; dy++ {
+				for dx := 0; dx < cellSize; dx++ {
+					img.Set(x*cellSize+dx, y*cellSize+dy, c)
+				}
+			}
+		}
+	}
+}
+
+// colonyEventMinSize keeps the event log readable: only colonies at least
+// this large produce birth, merge, split and death events.
+const colonyEventMinSize = 20
+
+// colonyTracker gives colonies stable ids across generations by matching
+// each one with the previous colony it overlaps most.
+type colonyTracker struct {
+	nextID     int
+	prevLabels [][]int
+	prevSizes  []int
+	prevStable []int       // stable id of each previous raw id (index id-1)
+	birth      map[int]int // stable id -> generation it appeared
+	stable     []int       // stable id of each current raw id (index id-1)
+}
+
+func newColonyTracker(size int) *colonyTracker {
+	return &colonyTracker{
+		prevLabels: newLabelGrid(size),
+		birth:      make(map[int]int),
+	}
+}
+
+func (t *colonyTracker) newID(generation int) int {
+	t.nextID++
+	t.birth[t.nextID] = generation
+	return t.nextID
+}
+
+// update matches the current colonies against the previous generation and
+// returns human readable descriptions of notable births, merges, splits and
+// deaths.
+func (t *colonyTracker) update(labels [][]int, sizes []int, generation int) []string {
+	type pair struct{ prev, cur int }
+	overlap := make(map[pair]int)
+	for y := range labels {
+		for x := range labels[y] {
+			p, c := t.prevLabels[y][x], labels[y][x]
+			if p > 0 && c > 0 {
+				overlap[pair{p, c}]++
+			}
+		}
+	}
+
+	// Best current match of every previous colony
+	bestCur := make([]int, len(t.prevSizes))
+	bestCurOverlap := make([]int, len(t.prevSizes))
+	// Whether each current colony touches any previous colony
+	touched := make([]bool, len(sizes))
+	for k, n := range overlap {
+		touched[k.cur-1] = true
+		if n > bestCurOverlap[k.prev-1] || (n == bestCurOverlap[k.prev-1] && k.cur < bestCur[k.prev-1]) {
+			bestCur[k.prev-1] = k.cur
+			bestCurOverlap[k.prev-1] = n
+		}
+	}
+
+	// Each current colony inherits the id of the previous colony that chose it
+	// with the largest overlap; the other suitors were merged into it.
+	heir := make([]int, len(sizes))
+	heirOverlap := make([]int, len(sizes))
+	suitors := make([][]int, len(sizes))
+	for p, c := range bestCur {
+		if c == 0 {
+			continue
+		}
+		suitors[c-1] = append(suitors[c-1], p+1)
+		if bestCurOverlap[p] > heirOverlap[c-1] {
+			heir[c-1] = p + 1
+			heirOverlap[c-1] = bestCurOverlap[p]
+		}
+	}
+
+	var events []string
+	t.stable = make([]int, len(sizes))
+	for c := range sizes {
+		big := sizes[c] >= colonyEventMinSize
+		if heir[c] > 0 {
+			id := t.prevStable[heir[c]-1]
+			t.stable[c] = id
+			for _, p := range suitors[c] {
+				if p == heir[c] {
+					continue
+				}
+				absorbed := t.prevStable[p-1]
+				if big {
+					events = append(events, fmt.Sprintf("Colony #%d absorbed #%d", id, absorbed))
+				}
+				delete(t.birth, absorbed)
+			}
+			continue
+		}
+
+		t.stable[c] = t.newID(generation)
+		if !big {
+			continue
+		}
+		if touched[c] {
+			events = append(events, fmt.Sprintf("Colony #%d split off (%d cells)", t.stable[c], sizes[c]))
+		} else {
+			events = append(events, fmt.Sprintf("Colony #%d born (%d cells)", t.stable[c], sizes[c]))
+		}
+	}
+
+	for p, c := range bestCur {
+		if c != 0 {
+			continue
+		}
+		id := t.prevStable[p]
+		if t.prevSizes[p] >= colonyEventMinSize {
+			events = append(events, fmt.Sprintf("Colony #%d died after %d generations", id, generation-t.birth[id]))
+		}
+		delete(t.birth, id)
+	}
+
+	for y := range labels {
+		copy(t.prevLabels[y], labels[y])
+	}
+	t.prevSizes = append(t.prevSizes[:0], sizes...)
+	t.prevStable = append(t.prevStable[:0], t.stable...)
+	return events
+}
+
+// oldest returns the stable id and age of the longest living current colony.
+func (t *colonyTracker) oldest(generation int) (id, age int) {
+	for _, sid := range t.stable {
+		if a := generation - t.birth[sid]; id == 0 || a > age || (a == age && sid < id) {
+			id, age = sid, a
+		}
+	}
+	return id, age
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"image/color"
+	"slices"
+)
+
+// cellColors returns the color of the cell at (x, y).
+type cellColors func(x, y int) [4]uint8
+
+// Colorizer colors the cells of the flat view by one of their properties.
+// colors is called once per frame. Rule families drawing colors of their
+// own keep them under the colorizers marked native.
+type Colorizer struct {
+	name   string
+	native bool
+	colors func(sim *Simulation, palette ColorPalette) cellColors
+}
+
+// colorizers lists the color-by modes offered in the UI, in display order.
+var colorizers = []Colorizer{
+	{name: "Age", native: true, colors: colorByAge},
+	{name: "Neighbour count", colors: colorByNeighbors},
+	{name: "Lineage", colors: colorByLineage},
+	{name: "Time since change", colors: colorByStillness},
+	{name: "Species", native: true, colors: colorBySpecies},
+}
+
+func colorizerNames() []string {
+	names := make([]string, len(colorizers))
+	for i, c := range colorizers {
+		names[i] = c.name
+	}
+	return names
+}
+
+func colorizerByName(name string) Colorizer {
+	if i := slices.IndexFunc(colorizers, func(c Colorizer) bool { return c.name == name }); i >= 0 {
+		return colorizers[i]
+	}
+	return colorizers[0]
+}
+
+// colorByAge is the classic look: the palette's color for the age.
+func colorByAge(sim *Simulation, palette ColorPalette) cellColors {
+	return ageColors(sim.grid, palette)
+}
+
+func ageColors(grid *Grid, palette ColorPalette) cellColors {
+	lut := cellColorTable(palette)
+	return func(x, y int) [4]uint8 {
+		return lut[min(grid.at(x, y), maxCellAge)]
+	}
+}
+
+// colorByNeighbors shades living cells from the young to the old colors
+// as their living neighbours go from none to eight.
+func colorByNeighbors(sim *Simulation, palette ColorPalette) cellColors {
+	stops := []color.Color{palette.young[2], palette.mature[7], palette.old[15]}
+	var lut [9][4]uint8
+	for i := range lut {
+		lut[i] = gradientAt(stops, float64(i)/8)
+	}
+	dead := rgbaBytes(palette.dead)
+	return func(x, y int) [4]uint8 {
+		if sim.grid.at(x, y) == 0 {
+			return dead
+		}
+		return lut[livingNeighbors(sim.grid, x, y)]
+	}
+}
+
+// colorByLineage gives each lineage of the built-in rules its own hue; the
+// rule families, which have no lineages, are colored by age.
+func colorByLineage(sim *Simulation, palette ColorPalette) cellColors {
+	if sim.rule != nil {
+		return colorByAge(sim, palette)
+	}
+	dead := rgbaBytes(palette.dead)
+	return func(x, y int) [4]uint8 {
+		id := sim.lineages.ids[y*sim.gridSize+x]
+		if id == 0 {
+			return dead
+		}
+		c := colonyColor(int(id))
+		return [4]uint8{c.R, c.G, c.B, c.A}
+	}
+}
+
+// colorByStillness colors living cells as if their age were the
+// generations since they last changed: cells that just changed look young,
+// cells frozen for 50 generations or more look oldest.
+func colorByStillness(sim *Simulation, palette ColorPalette) cellColors {
+	lut := cellColorTable(palette)
+	return func(x, y int) [4]uint8 {
+		if sim.grid.at(x, y) == 0 {
+			return lut[0]
+		}
+		still := sim.generation - sim.lastChange[y*sim.gridSize+x]
+		return lut[min(still+1, maxCellAge)]
+	}
+}
+
+// colorBySpecies paints each age band in one flat color, the colors of the
+// legend; rule families with kinds of cells of their own draw those.
+func colorBySpecies(sim *Simulation, palette ColorPalette) cellColors {
+	bands := [4][4]uint8{
+		rgbaBytes(palette.dead),
+		rgbaBytes(palette.young[2]),
+		rgbaBytes(palette.mature[7]),
+		rgbaBytes(palette.old[15]),
+	}
+	return func(x, y int) [4]uint8 {
+		if v := sim.grid.at(x, y); v > 0 {
+			return bands[cellBand(v)]
+		}
+		return bands[0]
+	}
+}
+
+func rgbaBytes(c color.Color) [4]uint8 {
+	r, g, b, a := c.RGBA()
+	return [4]uint8{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+}
+
+// trackChanges records the generation at which each cell last changed, and
+// the age at which dead cells died.
+func (s *Simulation) trackChanges() {
+	for i, c := range s.grid.cells {
+		if val := int(c); s.lastVals[i] != val {
+			s.recordDeath(i, val)
+			s.lastVals[i] = val
+			s.lastChange[i] = s.generation
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxCommands bounds the changes kept for undo.
+const maxCommands = 200
+
+// command is an undoable change of the app's state, already made when it
+// is recorded.
+type command struct {
+	name     string
+	do, undo func()
+	// locked reports whether the change cannot be replayed now, like a
+	// control disabled during a run; nil when it always can.
+	locked func() bool
+	// stale reports whether the change no longer applies, like an edit of
+	// an earlier run's grid; stale commands are dropped as undo and redo
+	// reach them. nil when it always applies.
+	stale func() bool
+}
+
+func (c command) isLocked() bool { return c.locked != nil && c.locked() }
+func (c command) isStale() bool  { return c.stale != nil && c.stale() }
+
+var errNothingToUndo = errors.New("nothing to undo")
+var errNothingToRedo = errors.New("nothing to redo")
+
+// commandStack is the undo and redo history shared by every control, so
+// Ctrl+Z and Ctrl+Shift+Z step through all the user's changes in order.
+// Controls record their changes as they happen, see track; the changes
+// replayed by undo and redo, and the ones made inside quietly, are not
+// recorded again. It is only used on the UI thread.
+type commandStack struct {
+	done, undone []command
+	replaying    bool
+	quiet        int
+	batch        *[]command // changes grouped by the running batch
+}
+
+func newCommandStack() *commandStack {
+	return &commandStack{}
+}
+
+// record adds a change that has just been made, forgetting the undone ones.
+func (s *commandStack) record(c command) {
+	if s.replaying || s.quiet > 0 {
+		return
+	}
+	if s.batch != nil {
+		*s.batch = append(*s.batch, c)
+		return
+	}
+	s.done = append(s.done, c)
+	if len(s.done) > maxCommands {
+		s.done = s.done[len(s.done)-maxCommands:]
+	}
+	s.undone = nil
+}
+
+// quietly runs fn without recording its changes, for the ones the app
+// makes by itself.
+func (s *commandStack) quietly(fn func()) {
+	s.quiet++
+	defer func() { s.quiet-- }()
+	fn()
+}
+
+// group runs fn, recording its changes as a single one undone at once.
+func (s *commandStack) group(name string, fn func()) {
+	if s.batch != nil {
+		fn()
+		return
+	}
+	var cmds []command
+	s.batch = &cmds
+	defer func() {
+		s.batch = nil
+		if len(cmds) == 0 {
+			return
+		}
+		s.record(command{
+			name: name,
+			do: func() {
+				for _, c := range cmds {
+					c.do()
+				}
+			},
+			undo: func() {
+				for i := len(cmds) - 1; i >= 0; i-- {
+					cmds[i].undo()
+				}
+			},
+			locked: func() bool {
+				for _, c := range cmds {
+					if c.isLocked() {
+						return true
+					}
+				}
+				return false
+			},
+		})
+	}()
+	fn()
+}
+
+// undo reverts the last change and returns its name.
+func (s *commandStack) undo() (string, error) {
+	c, err := s.pop(&s.done, errNothingToUndo)
+	if err != nil {
+		return "", err
+	}
+	s.replay(c.undo)
+	s.undone = append(s.undone, c)
+	return c.name, nil
+}
+
+// redo makes the last undone change again and returns its name.
+func (s *commandStack) redo() (string, error) {
+	c, err := s.pop(&s.undone, errNothingToRedo)
+	if err != nil {
+		return "", err
+	}
+	s.replay(c.do)
+	s.done = append(s.done, c)
+	return c.name, nil
+}
+
+// pop takes the last change of stack that still applies, leaving a locked
+// one in place.
+func (s *commandStack) pop(stack *[]command, none error) (command, error) {
+	for len(*stack) > 0 {
+		c := (*stack)[len(*stack)-1]
+		if c.isStale() {
+			*stack = (*stack)[:len(*stack)-1]
+			continue
+		}
+		if c.isLocked() {
+			return command{}, fmt.Errorf("%s cannot change during a run", c.name)
+		}
+		*stack = (*stack)[:len(*stack)-1]
+		return c, nil
+	}
+	return command{}, none
+}
+
+func (s *commandStack) replay(fn func()) {
+	s.replaying = true
+	defer func() { s.replaying = false }()
+	fn()
+}
+
+// track records the changes of a slider, select or check as commands
+// named name. Call it once the control has its callback and initial value.
+// A slider drag is a single change, from where it started to where it
+// ended; changes to a disabled control wait until it is enabled again.
+func (s *commandStack) track(name string, control fyne.Disableable) {
+	change := func(do, undo func()) {
+		s.record(command{name: name, do: do, undo: undo, locked: control.Disabled})
+	}
+	switch w := control.(type) {
+	case *widget.Slider:
+		committed := w.Value
+		onEnded := w.OnChangeEnded
+		w.OnChangeEnded = func(v float64) {
+			if onEnded != nil {
+				onEnded(v)
+			}
+			if v == committed {
+				return
+			}
+			before := committed
+			committed = v
+			change(func() { w.SetValue(v) }, func() { w.SetValue(before) })
+		}
+	case *widget.Select:
+		selected := w.Selected
+		onChanged := w.OnChanged
+		w.OnChanged = func(v string) {
+			if onChanged != nil {
+				onChanged(v)
+			}
+			if v == selected {
+				return
+			}
+			before := selected
+			selected = v
+			change(func() { w.SetSelected(v) }, func() { w.SetSelected(before) })
+		}
+	case *widget.Check:
+		onChanged := w.OnChanged
+		w.OnChanged = func(checked bool) {
+			if onChanged != nil {
+				onChanged(checked)
+			}
+			change(func() { w.SetChecked(checked) }, func() { w.SetChecked(!checked) })
+		}
+	default:
+		panic(fmt.Sprintf("track: unsupported control %T", control))
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestCommandStack(t *testing.T) {
+	s := newCommandStack()
+	value := 0
+	set := func(v int) command {
+		before := value
+		value = v
+		return command{name: "value", do: func() { value = v }, undo: func() { value = before }}
+	}
+	s.record(set(1))
+	s.record(set(2))
+	s.quietly(func() { s.record(set(3)) })
+	value = 2
+
+	if _, err := s.undo(); err != nil || value != 1 {
+		t.Fatalf("undo: value %d, err %v, want 1", value, err)
+	}
+	if _, err := s.redo(); err != nil || value != 2 {
+		t.Fatalf("redo: value %d, err %v, want 2", value, err)
+	}
+	s.undo()
+	s.record(set(5))
+	if _, err := s.redo(); !errors.Is(err, errNothingToRedo) {
+		t.Errorf("redo after a new change: %v, want nothing to redo", err)
+	}
+
+	s.group("both", func() {
+		s.record(set(6))
+		s.record(set(7))
+	})
+	s.undo()
+	if value != 5 {
+		t.Errorf("undoing a group: value %d, want 5", value)
+	}
+
+	locked := true
+	s.record(command{name: "locked", do: func() {}, undo: func() {}, locked: func() bool { return locked }})
+	if _, err := s.undo(); err == nil {
+		t.Error("a locked change was undone")
+	}
+	locked = false
+	s.record(command{name: "stale", do: func() {}, undo: func() { t.Error("a stale change was undone") }, stale: func() bool { return true }})
+	if name, _ := s.undo(); name != "locked" {
+		t.Errorf("undid %q, want the stale change skipped", name)
+	}
+}
+
+func TestTrackControls(t *testing.T) {
+	test.NewTempApp(t)
+	s := newCommandStack()
+	applied := 0.0
+	slider := widget.NewSlider(0, 10)
+	slider.OnChanged = func(v float64) { applied = v }
+	sel := widget.NewSelect([]string{"a", "b"}, nil)
+	sel.SetSelected("a")
+	check := widget.NewCheck("", nil)
+	s.track("slider", slider)
+	s.track("select", sel)
+	s.track("check", check)
+
+	slider.SetValue(3)
+	slider.SetValue(7)
+	sel.SetSelected("b")
+	sel.SetSelected("b") // no change
+	check.SetChecked(true)
+
+	for range 3 {
+		s.undo()
+	}
+	if sel.Selected != "a" || check.Checked || slider.Value != 3 || applied != 3 {
+		t.Errorf("after 3 undos: select %q, check %v, slider %g (applied %g)", sel.Selected, check.Checked, slider.Value, applied)
+	}
+	s.undo()
+	if slider.Value != 0 {
+		t.Errorf("slider %g, want 0", slider.Value)
+	}
+	s.redo()
+	if slider.Value != 3 {
+		t.Errorf("redo: slider %g, want 3", slider.Value)
+	}
+
+	slider.Disable()
+	if _, err := s.undo(); err == nil || slider.Value != 3 {
+		t.Error("a disabled control was changed")
+	}
+}
+
+func TestGridEditUndo(t *testing.T) {
+	sim := newSimulation(20, 1)
+	sim.reset(1)
+	before := slices.Clone(sim.grid.cells)
+
+	e := newGridEdit(sim.gridSize)
+	sim.beginEdit(e)
+	sim.setCell(3, 4, 1)
+	sim.setCell(3, 4, 2)
+	sim.placeFixture(5, 5, fountain)
+	sim.irradiate(10, 10, 3)
+	sim.endEdit()
+	sim.setCell(0, 0, 9) // after the edit, kept by undo
+
+	sim.applyEdit(e, false)
+	if sim.fixtures[5*20+5] != noFixture || sim.radiation[10*20+10] != 0 {
+		t.Error("fixture or radiation left by undo")
+	}
+	for i, want := range before {
+		if i != 0 && sim.grid.cells[i] != want {
+			t.Fatalf("cell %d = %d after undo, want %d", i, sim.grid.cells[i], want)
+		}
+	}
+	if sim.grid.at(0, 0) != 9 {
+		t.Error("undo reverted a change made after the edit")
+	}
+	var recount cellTally
+	recount.count(sim.grid)
+	if sim.tally != recount {
+		t.Error("the tally is off after undo")
+	}
+
+	sim.applyEdit(e, true)
+	if sim.grid.at(3, 4) != 2 || sim.fixtures[5*20+5] != fountain {
+		t.Error("redo did not restore the edit")
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// compareSide is one half of the A/B comparison window. Its simulation is
+// guarded by the window's mutex, see openCompareWindow.
+type compareSide struct {
+	name           string
+	sim            *Simulation
+	img            *image.RGBA
+	canvasImg      *canvas.Image
+	statsLabel     *widget.Label
+	growthSlider   *widget.Slider
+	mutationSlider *widget.Slider
+	finished       bool
+}
+
+func newCompareSide(name string, gridSize int, growthRate, mutationChance float64) *compareSide {
+	side := &compareSide{
+		name: name,
+		sim:  newSimulation(gridSize, 0),
+		img:  image.NewRGBA(image.Rect(0, 0, displaySize, displaySize)),
+	}
+	side.sim.growthRate = growthRate
+	side.sim.mutationChance = mutationChance
+
+	side.canvasImg = canvas.NewImageFromImage(side.img)
+	side.canvasImg.FillMode = canvas.ImageFillOriginal
+	side.canvasImg.SetMinSize(fyne.NewSize(float32(displaySize), float32(displaySize)))
+
+	side.statsLabel = widget.NewLabel(lang.L("Stats: --"))
+	return side
+}
+
+// controls builds the side's view and sliders; the sliders hold mu while
+// they change the simulation.
+func (side *compareSide) controls(mu *sync.Mutex) fyne.CanvasObject {
+	growthLabel := widget.NewLabel(fmt.Sprintf(lang.L("Growth rate: %.2f"), side.sim.growthRate))
+	side.growthSlider = widget.NewSlider(0.05, 0.5)
+	side.growthSlider.Step = 0.01
+	side.growthSlider.Value = side.sim.growthRate
+	side.growthSlider.OnChanged = func(v float64) {
+		mu.Lock()
+		side.sim.growthRate = v
+		mu.Unlock()
+		growthLabel.SetText(fmt.Sprintf(lang.L("Growth rate: %.2f"), v))
+	}
+
+	mutationLabel := widget.NewLabel(fmt.Sprintf(lang.L("Mutation: %.3f"), side.sim.mutationChance))
+	side.mutationSlider = widget.NewSlider(0, 0.1)
+	side.mutationSlider.Step = 0.001
+	side.mutationSlider.Value = side.sim.mutationChance
+	side.mutationSlider.OnChanged = func(v float64) {
+		mu.Lock()
+		side.sim.mutationChance = v
+		mu.Unlock()
+		mutationLabel.SetText(fmt.Sprintf(lang.L("Mutation: %.3f"), v))
+	}
+
+	return container.NewVBox(
+		widget.NewLabel(lang.LocalizeKey("compare.title", "🧪 Simulation {{.Side}}", map[string]any{"Side": side.name})),
+		widget.NewSeparator(),
+		side.canvasImg,
+		growthLabel,
+		side.growthSlider,
+		mutationLabel,
+		side.mutationSlider,
+		side.statsLabel,
+	)
+}
+
+func (side *compareSide) statsText() string {
+	s := side.sim.stats
+	text := fmt.Sprintf(lang.L("Gen %d\nPopulation: %d\nDensity: %.1f%%\nAvg age: %.1f\nEntropy: %.3f"),
+		s.generation, s.population, s.density*100, s.avgAge, s.entropy)
+	if side.finished {
+		text += lang.L("\nGrid filled!")
+	}
+	return text
+}
+
+// openCompareWindow runs two simulations from the same seed but with their
+// own growth and mutation parameters, stepping both in lockstep. The
+// window's goroutine steps them and changes the palette holding mu, which
+// the UI thread holds too while it draws them or starts a comparison.
+func openCompareWindow(a fyne.App, life *lifetime, seed int64, state *SimulationState) {
+	w := a.NewWindow(lang.L("Living Numbers Game - A/B Comparison"))
+
+	state.mu.Lock()
+	cellSize := state.cellSize
+	gridSize := state.gridSize
+	paletteMode := state.paletteMode
+	speed := state.speed
+	mutationChance := state.mutationChance
+	state.mu.Unlock()
+
+	// Guards running, palette, paletteRng and the sides' simulations
+	var mu sync.Mutex
+
+	paletteRng := rand.New(rand.NewSource(seed))
+	palette := generateDynamicPalette(paletteRng, 0, paletteMode)
+
+	sideA := newCompareSide("A", gridSize, 0.1, mutationChance)
+	sideB := newCompareSide("B", gridSize, 0.3, mutationChance)
+	sides := []*compareSide{sideA, sideB}
+
+	seedEntry := widget.NewEntry()
+	seedEntry.SetText(strconv.FormatInt(seed, 10))
+
+	running := false
+
+	// redraw shows both simulations; the caller must not hold mu
+	redraw := func() {
+		texts := make([]string, len(sides))
+		mu.Lock()
+		for i, side := range sides {
+			drawGridDynamic(side.sim.grid, side.img, palette, cellSize, gridSize)
+			texts[i] = side.statsText()
+		}
+		mu.Unlock()
+		for i, side := range sides {
+			side.canvasImg.Refresh()
+			side.statsLabel.SetText(texts[i])
+		}
+	}
+
+	setEditable := func(editable bool) {
+		for _, side := range sides {
+			if editable {
+				side.growthSlider.Enable()
+				side.mutationSlider.Enable()
+			} else {
+				side.growthSlider.Disable()
+				side.mutationSlider.Disable()
+			}
+		}
+		if editable {
+			seedEntry.Enable()
+		} else {
+			seedEntry.Disable()
+		}
+	}
+
+	statusLabel := widget.NewLabel(lang.L("Same seed, different parameters - Press Start to compare"))
+
+	startButton := widget.NewButton(lang.L("▶ Start"), nil)
+	startButton.OnTapped = func() {
+		mu.Lock()
+		wasRunning := running
+		running = false
+		mu.Unlock()
+		if wasRunning {
+			startButton.SetText(lang.L("▶ Start"))
+			setEditable(true)
+			return
+		}
+		s, err := strconv.ParseInt(seedEntry.Text, 10, 64)
+		if err != nil {
+			statusLabel.SetText(lang.LocalizeKey("compare.invalid_seed", "Invalid seed: {{.Seed}}", map[string]any{"Seed": seedEntry.Text}))
+			return
+		}
+		mu.Lock()
+		for _, side := range sides {
+			side.sim.reset(s)
+			side.finished = false
+		}
+		palette = generateDynamicPalette(paletteRng, 0, paletteMode)
+		mu.Unlock()
+		redraw()
+		statusLabel.SetText(fmt.Sprintf(lang.L("Running seed %d"), s))
+		startButton.SetText(lang.L("⏹ Stop"))
+		setEditable(false)
+		mu.Lock()
+		running = true
+		mu.Unlock()
+	}
+
+	newSeedButton := widget.NewButton(lang.L("🎲 New seed"), func() {
+		mu.Lock()
+		s := paletteRng.Int63()
+		mu.Unlock()
+		seedEntry.SetText(strconv.FormatInt(s, 10))
+	})
+
+	top := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel(lang.L("Seed:")), container.NewHBox(newSeedButton, startButton), seedEntry),
+		statusLabel,
+	)
+	w.SetContent(container.NewBorder(top, nil, nil, nil,
+		container.NewGridWithColumns(2, sideA.controls(&mu), sideB.controls(&mu))))
+	ctx, cancel := context.WithCancel(life.ctx)
+	w.SetOnClosed(cancel)
+
+	redraw()
+	w.Show()
+
+	driver := a.Driver()
+	life.spawn(func() {
+		ticker := time.NewTicker(time.Duration(speed) * time.Millisecond)
+		defer ticker.Stop()
+
+		cycle := 0.0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			mu.Lock()
+			if !running {
+				mu.Unlock()
+				continue
+			}
+
+			allFinished := true
+			for _, side := range sides {
+				if side.finished {
+					continue
+				}
+				side.sim.step()
+				if side.sim.isFull() {
+					side.finished = true
+				} else {
+					allFinished = false
+				}
+			}
+			cycle += 0.05
+			palette = generateDynamicPalette(paletteRng, cycle, paletteMode)
+			ended := allFinished
+			if ended {
+				running = false
+			}
+			genA, genB := sideA.sim.generation, sideB.sim.generation
+			mu.Unlock()
+
+			runOnMain(driver, func() {
+				redraw()
+				if ended {
+					startButton.SetText(lang.L("▶ Start"))
+					setEditable(true)
+					statusLabel.SetText(fmt.Sprintf(lang.L("Both grids filled - A: gen %d, B: gen %d"), genA, genB))
+				}
+			})
+		}
+	})
+}
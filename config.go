@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// userConfig holds the startup defaults read from config.toml. Zero values
+// keep the built-in defaults (mutation is a pointer since 0 is a valid
+// setting); command-line flags override the file.
+type userConfig struct {
+	Palette        string                  `toml:"palette,omitempty"`
+	GrowthRate     float64                 `toml:"growth_rate,omitzero"`
+	MutationChance *float64                `toml:"mutation_chance,omitempty"`
+	CellSize       int                     `toml:"cell_size,omitzero"` // sets the grid size, displaySize/cell_size cells
+	Speed          int                     `toml:"speed,omitzero"`
+	Window         windowConfig            `toml:"window"`
+	Effects        map[string]effectConfig `toml:"effects,omitempty"`
+}
+
+type windowConfig struct {
+	Width  float32 `toml:"width,omitzero"`
+	Height float32 `toml:"height,omitzero"`
+}
+
+type effectConfig struct {
+	Enabled   bool    `toml:"enabled"`
+	Intensity float64 `toml:"intensity"`
+	Radius    int     `toml:"radius,omitzero"` // bloom only
+}
+
+// configPath is ~/.config/living-numbers/config.toml on Linux, and the
+// matching user configuration directory elsewhere.
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "living-numbers", "config.toml"), nil
+}
+
+// loadUserConfig reads the config file; a missing file is not an error.
+func loadUserConfig(path string) (userConfig, error) {
+	var cfg userConfig
+	_, err := toml.DecodeFile(path, &cfg)
+	if errors.Is(err, fs.ErrNotExist) {
+		return userConfig{}, nil
+	}
+	return cfg, err
+}
+
+func saveUserConfig(path string, cfg userConfig) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := toml.NewEncoder(f).Encode(cfg); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// effectKey turns an effect name into its config table key, e.g.
+// "CRT curvature" -> "crt_curvature".
+func effectKey(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), " ", "_")
+}
+
+// launchDefaults overrides the built-in launch options with the values set
+// in the file, clamped to the slider ranges.
+func (c userConfig) launchDefaults(opts launchOptions) launchOptions {
+	if c.GrowthRate != 0 {
+		opts.growthRate = clampFloat(c.GrowthRate, 0.05, 0.5)
+	}
+	if c.MutationChance != nil {
+		opts.mutationChance = clampFloat(*c.MutationChance, 0, 0.1)
+	}
+	if c.CellSize != 0 {
+		opts.cellSize = clampInt(c.CellSize, 2, 8)
+	}
+	if c.Speed != 0 {
+		opts.speed = clampInt(c.Speed, 10, 200)
+	}
+	return opts
+}
+
+// applyEffects sets the effect slots listed in the file.
+func (c userConfig) applyEffects(chain EffectChain) {
+	for _, slot := range chain {
+		ec, ok := c.Effects[effectKey(slot.effect.Name())]
+		if !ok {
+			continue
+		}
+		slot.enabled = ec.Enabled
+		slot.intensity = clampFloat(ec.Intensity, 0, slot.max)
+		if bloom, ok := slot.effect.(bloomEffect); ok && ec.Radius > 0 {
+			bloom.settings.radius = clampInt(ec.Radius, 1, 12)
+			slot.effect = bloom
+		}
+	}
+}
+
+// effectsConfig records the current effect settings for the file.
+func effectsConfig(chain EffectChain) map[string]effectConfig {
+	out := make(map[string]effectConfig, len(chain))
+	for _, slot := range chain {
+		ec := effectConfig{Enabled: slot.enabled, Intensity: slot.intensity}
+		if bloom, ok := slot.effect.(bloomEffect); ok {
+			ec.Radius = bloom.settings.radius
+		}
+		out[effectKey(slot.effect.Name())] = ec
+	}
+	return out
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// consoleCommand is a command of the developer console. run gets the words
+// after the command's name and returns what to print.
+type consoleCommand struct {
+	usage string // arguments, e.g. "<x> <y> [radius]"
+	help  string
+	run   func(args []string) (string, error)
+}
+
+// console runs text commands against the app, for scripted control. A line
+// may hold several commands separated by semicolons, run in order until
+// one fails, the start of macros. Commands run on the UI thread, without
+// state.mu held, like the controls they drive.
+type console struct {
+	commands map[string]consoleCommand
+}
+
+func newConsole() *console {
+	c := &console{commands: make(map[string]consoleCommand)}
+	c.register("help", consoleCommand{help: "List the commands", run: func([]string) (string, error) {
+		return c.help(), nil
+	}})
+	return c
+}
+
+func (c *console) register(name string, cmd consoleCommand) {
+	c.commands[name] = cmd
+}
+
+func (c *console) help() string {
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s — %s\n", c.synopsis(name), c.commands[name].help)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// synopsis is the name of a command followed by its arguments.
+func (c *console) synopsis(name string) string {
+	return strings.TrimSpace(name + " " + c.commands[name].usage)
+}
+
+// exec runs the commands of line and returns their output.
+func (c *console) exec(line string) (string, error) {
+	var out []string
+	for _, text := range strings.Split(line, ";") {
+		words := strings.Fields(text)
+		if len(words) == 0 {
+			continue
+		}
+		cmd, ok := c.commands[strings.ToLower(words[0])]
+		if !ok {
+			return strings.Join(out, "\n"), fmt.Errorf("unknown command %q, try help", words[0])
+		}
+		result, err := cmd.run(words[1:])
+		if err != nil {
+			return strings.Join(out, "\n"), fmt.Errorf("%s: %w (usage: %s)", words[0], err, c.synopsis(strings.ToLower(words[0])))
+		}
+		if result != "" {
+			out = append(out, result)
+		}
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+var errConsoleArgs = errors.New("wrong arguments")
+
+// consoleInts parses every argument as an integer.
+func consoleInts(args []string) ([]int, error) {
+	ints := make([]int, len(args))
+	for i, a := range args {
+		n, err := strconv.Atoi(a)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a whole number", a)
+		}
+		ints[i] = n
+	}
+	return ints, nil
+}
+
+// writeFileWith creates the file at path and writes it with write.
+func writeFileWith(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// consoleEntry is the console's command line. Up and Down walk through the
+// commands entered, and typing ` hides the console again.
+type consoleEntry struct {
+	widget.Entry
+	history []string
+	pos     int // in history; len(history) past the last command
+	onHide  func()
+}
+
+func newConsoleEntry() *consoleEntry {
+	e := &consoleEntry{}
+	e.ExtendBaseWidget(e)
+	e.SetPlaceHolder(lang.L("Command, e.g. set growth 0.2 — help lists them"))
+	return e
+}
+
+func (e *consoleEntry) TypedRune(r rune) {
+	if r == '`' {
+		e.onHide()
+		return
+	}
+	e.Entry.TypedRune(r)
+}
+
+func (e *consoleEntry) TypedKey(ev *fyne.KeyEvent) {
+	switch ev.Name {
+	case fyne.KeyUp:
+		if e.pos > 0 {
+			e.pos--
+			e.SetText(e.history[e.pos])
+		}
+	case fyne.KeyDown:
+		if e.pos < len(e.history) {
+			e.pos++
+			e.SetText("")
+			if e.pos < len(e.history) {
+				e.SetText(e.history[e.pos])
+			}
+		}
+	default:
+		e.Entry.TypedKey(ev)
+	}
+}
+
+// remember adds line to the history.
+func (e *consoleEntry) remember(line string) {
+	if len(e.history) == 0 || e.history[len(e.history)-1] != line {
+		e.history = append(e.history, line)
+	}
+	e.pos = len(e.history)
+}
+
+// consolePane is the console below the window's content: the output of the
+// last commands above the command line. It starts hidden.
+type consolePane struct {
+	content *fyne.Container
+	entry   *consoleEntry
+	output  *widget.Label
+	scroll  *container.Scroll
+	canvas  fyne.Canvas
+}
+
+// maxConsoleOutput bounds the output kept, in bytes.
+const maxConsoleOutput = 16 << 10
+
+func newConsolePane(c *console, canvas fyne.Canvas) *consolePane {
+	p := &consolePane{entry: newConsoleEntry(), output: widget.NewLabel(""), canvas: canvas}
+	p.output.TextStyle = fyne.TextStyle{Monospace: true}
+	p.output.Wrapping = fyne.TextWrapWord
+	p.scroll = container.NewVScroll(p.output)
+	p.scroll.SetMinSize(fyne.NewSize(0, 120))
+	p.entry.onHide = p.toggle
+	p.entry.OnSubmitted = func(line string) {
+		line = strings.TrimSpace(line)
+		p.entry.SetText("")
+		if line == "" {
+			return
+		}
+		p.entry.remember(line)
+		out, err := c.exec(line)
+		if err != nil {
+			out = strings.TrimPrefix(out+"\n", "\n") + "⚠ " + err.Error()
+		}
+		p.print("> " + line)
+		if out != "" {
+			p.print(out)
+		}
+	}
+	p.content = container.NewBorder(widget.NewSeparator(), p.entry, nil, nil, p.scroll)
+	p.content.Hide()
+	return p
+}
+
+func (p *consolePane) print(text string) {
+	all := p.output.Text + text + "\n"
+	if len(all) > maxConsoleOutput {
+		all = all[len(all)-maxConsoleOutput:]
+		if i := strings.IndexByte(all, '\n'); i >= 0 {
+			all = all[i+1:]
+		}
+	}
+	p.output.SetText(all)
+	p.scroll.ScrollToBottom()
+}
+
+// toggle shows the console with the command line focused, or hides it.
+func (p *consolePane) toggle() {
+	if p.content.Visible() {
+		p.content.Hide()
+		p.canvas.Unfocus()
+		return
+	}
+	p.content.Show()
+	p.canvas.Focus(p.entry)
+}
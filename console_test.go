@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConsoleExec(t *testing.T) {
+	c := newConsole()
+	total := 0
+	c.register("add", consoleCommand{usage: "<n>", help: "Add n", run: func(args []string) (string, error) {
+		n, err := consoleInts(args)
+		if err != nil {
+			return "", err
+		}
+		if len(n) != 1 {
+			return "", errConsoleArgs
+		}
+		total += n[0]
+		return "added", nil
+	}})
+
+	out, err := c.exec("add 2; ; ADD 3")
+	if err != nil || out != "added\nadded" || total != 5 {
+		t.Fatalf("exec = %q, %v, total %d", out, err, total)
+	}
+
+	out, err = c.exec("add 1; add 1 2; add 10")
+	if !errors.Is(err, errConsoleArgs) || !strings.Contains(err.Error(), "usage: add <n>") {
+		t.Errorf("error = %v, want wrong arguments with the usage", err)
+	}
+	if out != "added" || total != 6 {
+		t.Errorf("exec = %q, total %d: commands ran past the failing one", out, total)
+	}
+
+	if _, err := c.exec("nope"); err == nil {
+		t.Error("unknown command accepted")
+	}
+	if help, _ := c.exec("help"); !strings.Contains(help, "add <n> — Add n") || !strings.Contains(help, "help — ") {
+		t.Errorf("help = %q", help)
+	}
+}
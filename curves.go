@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ageBandNames label the age bands of the curves, indexed by band-1.
+var ageBandNames = []string{"Young", "Mature", "Old"}
+
+// ageCurves shape the built-in rules by age band (young, mature, old): the
+// chance of a cell surviving each generation, and the weight of its age in
+// the birth chance of the empty cells around it.
+type ageCurves struct {
+	survival  [3]float64
+	fertility [3]float64
+}
+
+// defaultAgeCurves leave the rules unchanged.
+var defaultAgeCurves = ageCurves{
+	survival:  [3]float64{1, 1, 1},
+	fertility: [3]float64{1, 1, 1},
+}
+
+// fertileNeighbors is the sum of the neighbour ages of (x, y), each weighted
+// by the fertility of its band and, with a drift, by its drift weight.
+func (c *ageCurves) fertileNeighbors(g *Grid, x, y int, drift *driftWeights) float64 {
+	sum := 0.0
+	for ny := max(y-1, 0); ny <= min(y+1, g.size-1); ny++ {
+		for nx := max(x-1, 0); nx <= min(x+1, g.size-1); nx++ {
+			if val := g.at(nx, ny); val > 0 && (nx != x || ny != y) {
+				w := c.fertility[cellBand(val)-1]
+				if drift != nil {
+					w *= drift[ny-y+1][nx-x+1]
+				}
+				sum += float64(val) * w
+			}
+		}
+	}
+	return sum
+}
+
+// curveEditor edits a curve of values in [0, top], one point per column:
+// tapping or dragging in a column moves its point to the pointer, in steps
+// of a twentieth of top.
+type curveEditor struct {
+	widget.BaseWidget
+	values    []float64
+	labels    []string
+	top       float64
+	onChanged func(i int, v float64)
+}
+
+func newCurveEditor(values []float64, labels []string, top float64, onChanged func(i int, v float64)) *curveEditor {
+	e := &curveEditor{values: values, labels: labels, top: top, onChanged: onChanged}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// plotHeight is the height of the plot area, above the column labels.
+func (e *curveEditor) plotHeight() float32 {
+	return e.Size().Height - theme.TextSize() - theme.Padding()
+}
+
+func (e *curveEditor) set(pos fyne.Position) {
+	size := e.Size()
+	h := e.plotHeight()
+	if size.Width <= 0 || h <= 0 {
+		return
+	}
+	i := min(max(int(pos.X/size.Width*float32(len(e.values))), 0), len(e.values)-1)
+	v := math.Round(float64(1-pos.Y/h)*20) / 20 * e.top
+	v = min(max(v, 0), e.top)
+	if v == e.values[i] {
+		return
+	}
+	e.values[i] = v
+	if e.onChanged != nil {
+		e.onChanged(i, v)
+	}
+	e.Refresh()
+}
+
+func (e *curveEditor) Tapped(ev *fyne.PointEvent) { e.set(ev.Position) }
+func (e *curveEditor) Dragged(ev *fyne.DragEvent) { e.set(ev.Position) }
+func (e *curveEditor) DragEnd()                   {}
+
+func (e *curveEditor) CreateRenderer() fyne.WidgetRenderer {
+	r := &curveEditorRenderer{editor: e, background: canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))}
+	for i := range e.values {
+		r.points = append(r.points, canvas.NewCircle(theme.Color(theme.ColorNamePrimary)))
+		r.texts = append(r.texts, canvas.NewText("", theme.Color(theme.ColorNameForeground)))
+		r.labels = append(r.labels, canvas.NewText(lang.L(e.labels[i]), theme.Color(theme.ColorNameForeground)))
+		if i > 0 {
+			line := canvas.NewLine(theme.Color(theme.ColorNamePrimary))
+			line.StrokeWidth = 2
+			r.lines = append(r.lines, line)
+		}
+	}
+	return r
+}
+
+type curveEditorRenderer struct {
+	editor     *curveEditor
+	background *canvas.Rectangle
+	lines      []*canvas.Line
+	points     []*canvas.Circle
+	texts      []*canvas.Text // value above each point
+	labels     []*canvas.Text // column names
+}
+
+func (r *curveEditorRenderer) Layout(size fyne.Size) {
+	e := r.editor
+	h := e.plotHeight()
+	r.background.Resize(fyne.NewSize(size.Width, h))
+	col := size.Width / float32(len(e.values))
+	const radius = 5
+	var prev fyne.Position
+	for i, v := range e.values {
+		p := fyne.NewPos(col*(float32(i)+0.5), radius+float32(1-v/e.top)*(h-2*radius))
+		r.points[i].Move(p.SubtractXY(radius, radius))
+		r.points[i].Resize(fyne.NewSquareSize(2 * radius))
+		r.texts[i].Text = fmt.Sprintf("%.2f", v)
+		r.texts[i].TextSize = theme.CaptionTextSize()
+		r.texts[i].Refresh()
+		ts := r.texts[i].MinSize()
+		ty := p.Y - radius - ts.Height
+		if ty < 0 {
+			ty = p.Y + radius
+		}
+		r.texts[i].Move(fyne.NewPos(p.X-ts.Width/2, ty))
+		ls := r.labels[i].MinSize()
+		r.labels[i].Move(fyne.NewPos(p.X-ls.Width/2, h+theme.Padding()/2))
+		if i > 0 {
+			r.lines[i-1].Position1 = prev
+			r.lines[i-1].Position2 = p
+		}
+		prev = p
+	}
+}
+
+func (r *curveEditorRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(60*float32(len(r.editor.values)), 100+theme.TextSize()+theme.Padding())
+}
+
+func (r *curveEditorRenderer) Refresh() {
+	r.Layout(r.editor.Size())
+	canvas.Refresh(r.editor)
+}
+
+func (r *curveEditorRenderer) Objects() []fyne.CanvasObject {
+	objs := []fyne.CanvasObject{r.background}
+	for _, l := range r.lines {
+		objs = append(objs, l)
+	}
+	for i := range r.points {
+		objs = append(objs, r.points[i], r.texts[i], r.labels[i])
+	}
+	return objs
+}
+
+func (r *curveEditorRenderer) Destroy() {}
+
+// showAgeCurvesDialog edits the survival and fertility curves; changes
+// apply to a running simulation at once.
+func showAgeCurvesDialog(w fyne.Window, state *SimulationState) {
+	state.mu.Lock()
+	curves := state.ageCurves
+	state.mu.Unlock()
+	survival, fertility := curves.survival[:], curves.fertility[:]
+
+	survivalEditor := newCurveEditor(survival, ageBandNames, 1, func(i int, v float64) {
+		state.mu.Lock()
+		state.ageCurves.survival[i] = v
+		state.mu.Unlock()
+		logParam("survival_"+ageBandNames[i], v)
+	})
+	fertilityEditor := newCurveEditor(fertility, ageBandNames, 1, func(i int, v float64) {
+		state.mu.Lock()
+		state.ageCurves.fertility[i] = v
+		state.mu.Unlock()
+		logParam("fertility_"+ageBandNames[i], v)
+	})
+	reset := widget.NewButton(lang.L("Reset"), func() {
+		state.mu.Lock()
+		state.ageCurves = defaultAgeCurves
+		state.mu.Unlock()
+		copy(survival, defaultAgeCurves.survival[:])
+		copy(fertility, defaultAgeCurves.fertility[:])
+		survivalEditor.Refresh()
+		fertilityEditor.Refresh()
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel(lang.L("Survival: chance of living on each generation")),
+		survivalEditor,
+		widget.NewLabel(lang.L("Fertility: weight of the age in nearby births")),
+		fertilityEditor,
+		container.NewHBox(reset),
+	)
+	d := dialog.NewCustom(lang.L("📉 Age curves"), lang.L("Close"), content, w)
+	d.Resize(fyne.NewSize(420, 420))
+	d.Show()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"image"
+	"image/color"
+)
+
+// deadColorNames lists the colors offered for empty cells. "Theme" follows
+// the theme background and "Transparent" lets whatever is behind the canvas
+// show through, for overlay use.
+var deadColorNames = []string{"Theme", "Black", "White", "Transparent", "Custom…"}
+
+// deadColorByName returns the color of a preset, nil for the theme one.
+func deadColorByName(name string) *color.RGBA {
+	switch name {
+	case "Black":
+		return &color.RGBA{0, 0, 0, 255}
+	case "White":
+		return &color.RGBA{255, 255, 255, 255}
+	case "Transparent":
+		return &color.RGBA{}
+	}
+	return nil
+}
+
+// Age ghosts: a dead cell keeps a faint trace of the color it had when it
+// died, ghostStrength of it at first, fading out over ghostLife generations.
+const (
+	ghostStrength = 0.25
+	ghostLife     = 20
+)
+
+// recordDeath remembers the age of the cells that just died, called from
+// trackChanges before the new values are recorded.
+func (s *Simulation) recordDeath(i, val int) {
+	if val <= 0 && s.lastVals[i] > 0 {
+		s.deathAge[i] = s.lastVals[i]
+	}
+}
+
+// drawGhosts tints the dead cells that died less than ghostLife generations
+// ago with a faint shade of their color at death.
+func drawGhosts(img *image.RGBA, sim *Simulation, palette ColorPalette, cellSize int) {
+	lut := cellColorTable(palette)
+	dead := lut[0]
+	bounds := img.Bounds()
+	n := sim.gridSize
+	for y := range n {
+		for x, c := range sim.grid.row(y) {
+			i := y*n + x
+			since := sim.generation - sim.lastChange[i]
+			if c > 0 || sim.deathAge[i] == 0 || since >= ghostLife {
+				continue
+			}
+			// image.RGBA is premultiplied, so mixing every channel, alpha
+			// included, also works over a transparent background
+			f := ghostStrength * (1 - float64(since)/ghostLife)
+			ghost := lut[min(sim.deathAge[i], maxCellAge)]
+			var col [4]uint8
+			for k := range col {
+				col[k] = uint8(float64(dead[k])*(1-f) + float64(ghost[k])*f)
+			}
+			for dy := 0; dy < cellSize; dy++ {
+				py := bounds.Min.Y + y*cellSize + dy
+				if py >= bounds.Max.Y {
+					break
+				}
+				for dx := 0; dx < cellSize; dx++ {
+					px := bounds.Min.X + x*cellSize + dx
+					if px >= bounds.Max.X {
+						break
+					}
+					off := img.PixOffset(px, py)
+					copy(img.Pix[off:off+4], col[:])
+				}
+			}
+		}
+	}
+}
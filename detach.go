@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// detachablePanel is a titled panel of the main window that can be popped
+// out into a window of its own, e.g. onto a second monitor, and put back by
+// closing that window.
+type detachablePanel struct {
+	app     fyne.App
+	title   string
+	content fyne.CanvasObject
+	body    *fyne.Container // holds content while attached
+	button  *widget.Button
+	win     fyne.Window // nil while attached
+	slot    fyne.CanvasObject
+}
+
+func newDetachablePanel(a fyne.App, title string, content fyne.CanvasObject) *detachablePanel {
+	p := &detachablePanel{app: a, title: title, content: content, body: container.NewStack(content)}
+	p.button = widget.NewButton(lang.L("⧉ Detach"), p.toggle)
+	p.slot = container.NewVBox(
+		container.NewBorder(nil, nil, nil, p.button, widget.NewLabel(title)),
+		widget.NewSeparator(),
+		p.body,
+	)
+	return p
+}
+
+func (p *detachablePanel) toggle() {
+	if p.win != nil {
+		p.win.Close()
+		return
+	}
+	p.body.RemoveAll()
+	p.win = p.app.NewWindow(fmt.Sprintf(lang.L("Living Numbers Game - %s"), p.title))
+	p.win.SetContent(container.NewVScroll(p.content))
+	p.win.SetOnClosed(p.attach)
+	p.win.Resize(fyne.NewSize(320, 360))
+	p.button.SetText(lang.L("↩ Attach"))
+	p.win.Show()
+}
+
+// attach puts the content back into the main window once the panel's own
+// window is closed.
+func (p *detachablePanel) attach() {
+	p.win = nil
+	p.body.Add(p.content)
+	p.button.SetText(lang.L("⧉ Detach"))
+}
+
+// close closes the panel's window, if any, with the main window.
+func (p *detachablePanel) close() {
+	if p.win != nil {
+		p.win.Close()
+	}
+}
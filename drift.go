@@ -0,0 +1,36 @@
+package main
+
+import "math"
+
+// driftDirections are the directions offered by the drift selector,
+// clockwise from up.
+var driftDirections = []string{"↑", "↗", "→", "↘", "↓", "↙", "←", "↖"}
+
+// driftWeights weight the neighbours of an empty cell in its birth chance,
+// indexed by [dy+1][dx+1], so that births lean one way.
+type driftWeights [3][3]float64
+
+// newDriftWeights returns the weights of a drift towards driftDirections[dir]
+// at a strength from 0 to 1, or nil for no drift. A neighbour counts
+// 1 + strength times the cosine between the drift and the way from it to the
+// empty cell: at full strength, cells upwind of a living cell are never born
+// from it and cells downwind twice as often.
+func newDriftWeights(dir int, strength float64) *driftWeights {
+	if strength <= 0 {
+		return nil
+	}
+	angle := float64(dir) * math.Pi / 4
+	wx, wy := math.Sin(angle), -math.Cos(angle)
+	var w driftWeights
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			// The neighbour at (dx, dy) reaches the cell along (-dx, -dy)
+			cos := -(float64(dx)*wx + float64(dy)*wy) / math.Hypot(float64(dx), float64(dy))
+			w[dy+1][dx+1] = 1 + strength*cos
+		}
+	}
+	return &w
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Effect post-processes a rendered frame in place. intensity ranges over
+// the slot's [0, max] scale; 0 leaves the frame untouched.
+type Effect interface {
+	Name() string
+	Apply(img *image.RGBA, palette ColorPalette, intensity float64)
+}
+
+// EffectSlot is one stage of the pipeline with its user settings.
+type EffectSlot struct {
+	effect    Effect
+	enabled   bool
+	intensity float64
+	max       float64
+}
+
+// EffectChain applies its enabled effects in order.
+type EffectChain []*EffectSlot
+
+func (c EffectChain) apply(img *image.RGBA, palette ColorPalette) {
+	for _, s := range c {
+		if s.enabled && s.intensity > 0 {
+			s.effect.Apply(img, palette, s.intensity)
+		}
+	}
+}
+
+func (c EffectChain) find(name string) *EffectSlot {
+	for _, s := range c {
+		if s.effect.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// snapshot copies the slot settings so the chain can be applied without
+// holding the state lock. Effects keep their scratch buffers.
+func (c EffectChain) snapshot() EffectChain {
+	out := make(EffectChain, len(c))
+	for i, s := range c {
+		copied := *s
+		out[i] = &copied
+	}
+	return out
+}
+
+// newEffectChain builds the default pipeline; only bloom starts enabled.
+func newEffectChain(bloom BloomSettings) EffectChain {
+	return EffectChain{
+		{effect: bloomEffect{bloom}, enabled: true, intensity: bloom.intensity, max: 4},
+		{effect: &chromaticEffect{}, intensity: 0.5, max: 1},
+		{effect: scanlineEffect{}, intensity: 0.5, max: 1},
+		{effect: &crtEffect{}, intensity: 0.5, max: 1},
+		{effect: vignetteEffect{}, intensity: 0.5, max: 1},
+	}
+}
+
+// bloomEffect wraps applyBloom; radius and threshold come from settings.
+type bloomEffect struct {
+	settings BloomSettings
+}
+
+func (bloomEffect) Name() string { return "Bloom" }
+
+func (e bloomEffect) Apply(img *image.RGBA, palette ColorPalette, intensity float64) {
+	s := e.settings
+	s.intensity = intensity
+	applyBloom(img, palette, s)
+}
+
+// scanlineEffect darkens every other pixel row like a CRT raster.
+type scanlineEffect struct{}
+
+func (scanlineEffect) Name() string { return "Scanlines" }
+
+func (scanlineEffect) Apply(img *image.RGBA, palette ColorPalette, intensity float64) {
+	b := img.Bounds()
+	keep := 1 - 0.6*intensity
+	for y := b.Min.Y + 1; y < b.Max.Y; y += 2 {
+		row := img.Pix[img.PixOffset(b.Min.X, y) : img.PixOffset(b.Max.X-1, y)+4]
+		for i := 0; i < len(row); i += 4 {
+			row[i] = uint8(float64(row[i]) * keep)
+			row[i+1] = uint8(float64(row[i+1]) * keep)
+			row[i+2] = uint8(float64(row[i+2]) * keep)
+		}
+	}
+}
+
+// chromaticEffect splits the red and blue channels horizontally, more
+// strongly towards the left and right edges.
+type chromaticEffect struct {
+	src []uint8
+}
+
+func (*chromaticEffect) Name() string { return "Chromatic aberration" }
+
+func (e *chromaticEffect) Apply(img *image.RGBA, palette ColorPalette, intensity float64) {
+	b := img.Bounds()
+	w := b.Dx()
+	e.src = append(e.src[:0], img.Pix...)
+	maxShift := 4 * intensity
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := 0; x < w; x++ {
+			shift := int(math.Round(maxShift * (float64(2*x)/float64(w) - 1)))
+			rx := clampInt(x+shift, 0, w-1)
+			bx := clampInt(x-shift, 0, w-1)
+			i := img.PixOffset(b.Min.X+x, y)
+			img.Pix[i] = e.src[img.PixOffset(b.Min.X+rx, y)]
+			img.Pix[i+2] = e.src[img.PixOffset(b.Min.X+bx, y)+2]
+		}
+	}
+}
+
+// crtEffect bends the frame like a curved tube (barrel distortion).
+type crtEffect struct {
+	src []uint8
+}
+
+func (*crtEffect) Name() string { return "CRT curvature" }
+
+func (e *crtEffect) Apply(img *image.RGBA, palette ColorPalette, intensity float64) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	e.src = append(e.src[:0], img.Pix...)
+	k := 0.25 * intensity
+	for y := 0; y < h; y++ {
+		v := 2*float64(y)/float64(h-1) - 1
+		for x := 0; x < w; x++ {
+			u := 2*float64(x)/float64(w-1) - 1
+			f := 1 + k*(u*u+v*v)
+			sx := int(math.Round((u*f + 1) * float64(w-1) / 2))
+			sy := int(math.Round((v*f + 1) * float64(h-1) / 2))
+			i := img.PixOffset(b.Min.X+x, b.Min.Y+y)
+			if sx < 0 || sx >= w || sy < 0 || sy >= h {
+				img.Pix[i], img.Pix[i+1], img.Pix[i+2] = 0, 0, 0
+				continue
+			}
+			j := img.PixOffset(b.Min.X+sx, b.Min.Y+sy)
+			copy(img.Pix[i:i+3], e.src[j:j+3])
+		}
+	}
+}
+
+// vignetteEffect darkens the corners.
+type vignetteEffect struct{}
+
+func (vignetteEffect) Name() string { return "Vignette" }
+
+func (vignetteEffect) Apply(img *image.RGBA, palette ColorPalette, intensity float64) {
+	b := img.Bounds()
+	cx, cy := float64(b.Dx())/2, float64(b.Dy())/2
+	maxD2 := cx*cx + cy*cy
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		dy := float64(y-b.Min.Y) - cy
+		for x := b.Min.X; x < b.Max.X; x++ {
+			dx := float64(x-b.Min.X) - cx
+			keep := 1 - intensity*(dx*dx+dy*dy)/maxD2
+			i := img.PixOffset(x, y)
+			img.Pix[i] = uint8(float64(img.Pix[i]) * keep)
+			img.Pix[i+1] = uint8(float64(img.Pix[i+1]) * keep)
+			img.Pix[i+2] = uint8(float64(img.Pix[i+2]) * keep)
+		}
+	}
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// showEffectsDialog toggles the effects and sets their intensities; bloom
+// also exposes its blur radius.
+func showEffectsDialog(w fyne.Window, state *SimulationState) {
+	rows := container.NewVBox()
+	for _, slot := range state.effects {
+		slot := slot
+		check := widget.NewCheck(lang.L(slot.effect.Name()), func(on bool) {
+			state.mu.Lock()
+			slot.enabled = on
+			state.mu.Unlock()
+			logParam(effectKey(slot.effect.Name()), on)
+		})
+		check.Checked = slot.enabled
+		label := widget.NewLabel(fmt.Sprintf("%.2f", slot.intensity))
+		slider := widget.NewSlider(0, slot.max)
+		slider.Step = slot.max / 40
+		slider.Value = slot.intensity
+		slider.OnChanged = func(v float64) {
+			state.mu.Lock()
+			slot.intensity = v
+			state.mu.Unlock()
+			logParam(effectKey(slot.effect.Name())+"_intensity", v)
+			label.SetText(fmt.Sprintf("%.2f", v))
+		}
+		rows.Add(container.NewBorder(nil, nil, check, label, slider))
+
+		if bloom, ok := slot.effect.(bloomEffect); ok {
+			radiusLabel := widget.NewLabel(fmt.Sprintf("%dpx", bloom.settings.radius))
+			radius := widget.NewSlider(1, 12)
+			radius.Step = 1
+			radius.Value = float64(bloom.settings.radius)
+			radius.OnChanged = func(v float64) {
+				state.mu.Lock()
+				bloom.settings.radius = int(v)
+				slot.effect = bloom
+				state.mu.Unlock()
+				radiusLabel.SetText(fmt.Sprintf("%dpx", int(v)))
+			}
+			rows.Add(container.NewBorder(nil, nil, widget.NewLabel("    "+lang.L("Radius")), radiusLabel, radius))
+		}
+	}
+
+	d := dialog.NewCustom(lang.L("✨ Effects"), lang.L("Close"), rows, w)
+	d.Resize(fyne.NewSize(460, 320))
+	d.Show()
+}
+
+// bloomSettings returns the bloom parameters of a Bloom slot, with the
+// slot's intensity.
+func bloomSettings(slot EffectSlot) BloomSettings {
+	s := defaultBloom
+	if b, ok := slot.effect.(bloomEffect); ok {
+		s = b.settings
+	}
+	s.intensity = slot.intensity
+	return s
+}
@@ -0,0 +1,137 @@
+package main
+
+import "image"
+
+// Metabolism settings, energies being fractions of a cell's full budget.
+const (
+	energyGain  = 0.02 // gathered by every living cell each generation
+	energyBirth = 0.3  // budget of a newborn, drawn from its neighbours
+	energyStart = 0.5  // budget of the cells alive when metabolism starts
+	crowdLimit  = 5    // living neighbours a cell bears without cost
+	crowdDrain  = 0.05 // lost per living neighbour beyond crowdLimit
+)
+
+// setMetabolism turns the energy layer on or off. Cells alive when it is
+// turned on start with energyStart.
+func (s *Simulation) setMetabolism(on bool) {
+	if !on {
+		s.energy = nil
+		return
+	}
+	if s.energy != nil {
+		return
+	}
+	s.energy = make([]float64, s.gridSize*s.gridSize)
+	s.fillEnergy()
+}
+
+// fillEnergy gives every living cell energyStart and the others nothing.
+func (s *Simulation) fillEnergy() {
+	for i, c := range s.grid.cells {
+		s.energy[i] = 0
+		if c > 0 {
+			s.energy[i] = energyStart
+		}
+	}
+}
+
+// metabolize runs the energy layer over the generation just computed from
+// prev: survivors gather energyGain and lose crowdDrain per living neighbour
+// beyond crowdLimit, newborns draw energyBirth from their living neighbours
+// (births they cannot afford fail), and cells out of energy die. It returns
+// the failed births and the cells that starved.
+func (s *Simulation) metabolize(prev *Grid) (failed, starved int) {
+	n := s.gridSize
+	var newborns []image.Point
+	for y := range n {
+		for x, c := range s.grid.row(y) {
+			i := y*n + x
+			switch {
+			case c == 0:
+				s.energy[i] = 0
+			case prev.cells[i] == 0:
+				newborns = append(newborns, image.Pt(x, y))
+			default:
+				crowd := max(livingNeighbors(prev, x, y)-crowdLimit, 0)
+				s.energy[i] = min(s.energy[i]+energyGain-crowdDrain*float64(crowd), 1)
+			}
+		}
+	}
+
+	for _, p := range newborns {
+		available := 0.0
+		s.eachParent(prev, p, func(i int) { available += max(s.energy[i], 0) })
+		if available < energyBirth {
+			s.change(p.Y*n+p.X, 0)
+			failed++
+			continue
+		}
+		share := energyBirth / available
+		s.eachParent(prev, p, func(i int) { s.energy[i] -= max(s.energy[i], 0) * share })
+		s.energy[p.Y*n+p.X] = energyBirth
+	}
+
+	for i, c := range s.grid.cells {
+		if c > 0 && s.energy[i] <= 0 {
+			s.change(i, 0)
+			s.energy[i] = 0
+			starved++
+		}
+	}
+	return failed, starved
+}
+
+// eachParent calls f with the energy index of every neighbour of p that
+// was alive already in prev.
+func (s *Simulation) eachParent(prev *Grid, p image.Point, f func(i int)) {
+	n := s.gridSize
+	for y := max(p.Y-1, 0); y <= min(p.Y+1, n-1); y++ {
+		for x := max(p.X-1, 0); x <= min(p.X+1, n-1); x++ {
+			if (x != p.X || y != p.Y) && prev.at(x, y) > 0 && s.grid.at(x, y) > 0 {
+				f(y*n + x)
+			}
+		}
+	}
+}
+
+// livingNeighbors counts the living neighbours of (x, y).
+func livingNeighbors(g *Grid, x, y int) int {
+	count := 0
+	for ny := max(y-1, 0); ny <= min(y+1, g.size-1); ny++ {
+		for nx := max(x-1, 0); nx <= min(x+1, g.size-1); nx++ {
+			if (nx != x || ny != y) && g.at(nx, ny) > 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// averageEnergy is the mean energy of the living cells.
+func (s *Simulation) averageEnergy() float64 {
+	total, alive := 0.0, 0
+	for i, c := range s.grid.cells {
+		if c > 0 {
+			total += s.energy[i]
+			alive++
+		}
+	}
+	if alive == 0 {
+		return 0
+	}
+	return total / float64(alive)
+}
+
+// energyRenderer draws the energy of every cell through the palette's
+// gradient, or the plain grid while metabolism is off.
+type energyRenderer struct{}
+
+func (energyRenderer) Name() string { return "Energy view" }
+
+func (energyRenderer) Render(sim *Simulation, img *image.RGBA, palette ColorPalette, cellSize int) {
+	if sim.energy == nil {
+		flatRenderer{}.Render(sim, img, palette, cellSize)
+		return
+	}
+	drawLevels(img, sim.energy, sim.gridSize, palette, cellSize)
+}
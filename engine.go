@@ -0,0 +1,392 @@
+package main
+
+import "math/rand"
+
+// Rand is the random source of a simulation: the seeding, the mutation
+// bursts, evolve and the rule families draw from it alone. *rand.Rand
+// implements it; tests substitute a fixed sequence so every draw is known.
+type Rand interface {
+	Float64() float64
+	Intn(n int) int
+	Int63() int64
+	Perm(n int) []int
+}
+
+// seededRand is the random source of a run of seed.
+func seededRand(seed int64) Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// Simulation owns a grid together with the random source driving it, so that
+// several runs can be stepped independently and reproduced from their seed.
+type Simulation struct {
+	grid           *Grid
+	next           *Grid      // back buffer for evolve, swapped with grid
+	sums           summedArea // neighbour sums for evolve
+	tally          cellTally  // of grid, kept up to date by the built-in rules
+	rng            Rand
+	newRand        func(seed int64) Rand // source for a seed, seededRand outside tests
+	seed           int64
+	gridSize       int
+	generation     int
+	growthRate     float64
+	mutationChance float64
+	temperature    float64
+	ageCurves      ageCurves
+	birthCurve     birthCurve
+	seasonPeriod   int // generations per season cycle, 0 for none
+	drift          *driftWeights
+	thresholds     thresholds
+	ruleDrift      bool       // thresholds mutate every generation
+	zones          []uint8    // zone of each cell, row-major; nil for none
+	climates       [4]climate // of each zone
+	movementRate   float64    // chance of a crowded cell moving, see migrate
+	immigration    float64    // chance of an edge cell being settled, see immigrate
+	entryEdge      int        // index in immigrationEdges
+	infection      []int      // generations each cell has been infected, nil without an epidemic
+	infectionRate  float64    // chance of catching the disease per infected neighbour
+	infectionSpan  int        // generations an infected cell survives
+	symmetry       Symmetry
+	rule           Rule // nil runs the built-in living numbers
+	ants           []ant
+	energy         []float64 // metabolism layer, row-major; nil while off
+	nutrients      []float64 // nutrient field, row-major; nil while off
+	nextNutrients  []float64
+	radiation      []float64 // left by supernovas, row-major; nil once decayed
+	fixtures       []fixture // placed by the user, row-major; nil for none
+	stats          Stats
+	reborn         [][]bool // cells reborn during the last generation
+	totalRebirths  int
+	colonyLabels   [][]int // colony id per cell, 0 for dead cells
+	colonySizes    []int   // cell count per colony, indexed by id-1
+	colonies       *colonyTracker
+	lineages       *lineageTracker // of the built-in rules
+	lastVals       []int           // value of each cell when it last changed, row-major
+	lastChange     []int           // generation each cell last changed, row-major
+	deathAge       []int           // age each empty cell died at, row-major; 0 if never alive
+	colonyEvents   []string        // notable colony changes of the last generation
+	ruleEvents     []ruleEvent     // reported by the rule during the last generation
+	edit           *gridEdit       // user edit being recorded, see beginEdit
+}
+
+// clearGrid zeroes every cell of g.
+func clearGrid[T any](g [][]T) {
+	for _, row := range g {
+		clear(row)
+	}
+}
+
+func newBoolGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+	return grid
+}
+
+func newSimulation(gridSize int, seed int64) *Simulation {
+	s := &Simulation{
+		rng:        seededRand(seed),
+		newRand:    seededRand,
+		seed:       seed,
+		ageCurves:  defaultAgeCurves,
+		birthCurve: defaultBirthCurve,
+		climates:   defaultClimates,
+	}
+	s.resize(gridSize)
+	return s
+}
+
+// resize empties the grid and sets its size; the buffers are only
+// reallocated when the size changes.
+func (s *Simulation) resize(gridSize int) {
+	if s.grid != nil && gridSize == s.gridSize {
+		s.grid.clear()
+		clearGrid(s.reborn)
+		clear(s.energy)
+	} else {
+		if s.nutrients != nil {
+			s.nutrients = make([]float64, gridSize*gridSize)
+			s.nextNutrients = make([]float64, gridSize*gridSize)
+		}
+		s.grid = newGrid(gridSize)
+		s.next = newGrid(gridSize)
+		s.reborn = newBoolGrid(gridSize)
+		s.colonyLabels = newLabelGrid(gridSize)
+		if s.energy != nil {
+			s.energy = make([]float64, gridSize*gridSize)
+		}
+	}
+	s.gridSize = gridSize
+	s.fillNutrients()
+	for i := range s.ants {
+		s.ants[i].x %= gridSize
+		s.ants[i].y %= gridSize
+	}
+	s.infection = nil
+	s.radiation = nil
+	s.fixtures = nil
+	s.zones = nil
+	s.colonySizes = nil
+	s.colonies = newColonyTracker(gridSize)
+	s.lineages = newLineageTracker(gridSize)
+	s.lastVals = make([]int, gridSize*gridSize)
+	s.lastChange = make([]int, gridSize*gridSize)
+	s.deathAge = make([]int, gridSize*gridSize)
+	s.colonyEvents = nil
+	s.ruleEvents = nil
+	s.tally = cellTally{}
+	s.thresholds = defaultThresholds
+	s.generation = 0
+	s.totalRebirths = 0
+	s.stats = Stats{}
+}
+
+// reset reseeds the random source and scatters a fresh random population
+// and the ants.
+func (s *Simulation) reset(seed int64) {
+	s.seed = seed
+	s.rng = s.newRand(seed)
+	s.resize(s.gridSize)
+
+	if s.rule != nil {
+		s.rule.seed(s)
+	} else {
+		initCount := 200 + s.rng.Intn(400)
+		for i := 0; i < initCount; i++ {
+			x := s.rng.Intn(s.gridSize)
+			y := s.rng.Intn(s.gridSize)
+			s.grid.set(x, y, s.rng.Intn(10)+1)
+		}
+		symmetrize(s.grid, s.symmetry, nil)
+		s.lineages.update(s.grid, 0)
+	}
+	if s.energy != nil {
+		s.fillEnergy()
+	}
+	ants := len(s.ants)
+	s.ants = s.ants[:0]
+	s.setAnts(ants)
+	s.tally.count(s.grid)
+	s.stats = s.tally.stats(0, s.gridSize)
+	if r, ok := s.rule.(statsReporter); ok {
+		r.report(&s.stats)
+	}
+	s.updateColonies()
+	s.trackChanges()
+}
+
+// seedFrom replaces the grid with values, a loaded grid centered on the grid
+// and cropped to it: the state seeded by reset, or the running state.
+func (s *Simulation) seedFrom(values [][]int) {
+	s.grid.clear()
+	width := 0
+	for _, row := range values {
+		width = max(width, len(row))
+	}
+	offX, offY := (s.gridSize-width)/2, (s.gridSize-len(values))/2
+	for y, row := range values {
+		for x, v := range row {
+			if gx, gy := x+offX, y+offY; gx >= 0 && gx < s.gridSize && gy >= 0 && gy < s.gridSize {
+				s.grid.set(gx, gy, min(max(v, 0), maxCellAge))
+			}
+		}
+	}
+	if s.rule != nil {
+		s.rule.load(s)
+	} else {
+		s.lineages = newLineageTracker(s.gridSize)
+		s.lineages.update(s.grid, s.generation)
+	}
+	if s.energy != nil {
+		s.fillEnergy()
+	}
+	s.tally.count(s.grid)
+	s.stats = s.tally.stats(s.generation, s.gridSize)
+	if r, ok := s.rule.(statsReporter); ok {
+		r.report(&s.stats)
+	}
+	s.updateColonies()
+	s.trackChanges()
+	clear(s.deathAge) // the replaced cells leave no ghosts
+}
+
+// updateColonies relabels the colonies of the current grid.
+func (s *Simulation) updateColonies() {
+	s.colonySizes = findColonies(s.grid, s.colonyLabels)
+	s.colonyEvents = s.colonies.update(s.colonyLabels, s.colonySizes, s.generation)
+	s.stats.colonies = len(s.colonySizes)
+	s.stats.largestColony = 0
+	for _, size := range s.colonySizes {
+		if size > s.stats.largestColony {
+			s.stats.largestColony = size
+		}
+	}
+}
+
+// step advances one generation and reports whether a mutation burst occurred.
+func (s *Simulation) step() bool {
+	s.generation++
+	if s.rule != nil {
+		s.ruleEvents = s.ruleEvents[:0]
+		births := s.rule.step(s)
+		if st, ok := s.rule.(strider); ok {
+			s.generation += st.stride() - 1
+		}
+		s.moveAnts()
+		s.tally.count(s.grid)
+		s.stats = s.tally.stats(s.generation, s.gridSize)
+		s.stats.births = births
+		if r, ok := s.rule.(statsReporter); ok {
+			r.report(&s.stats)
+		}
+		s.updateColonies()
+		s.trackChanges()
+		return false
+	}
+
+	mutated := false
+	if s.rng.Float64() < s.mutationChance {
+		// Genetic mutation
+		for i := 0; i < 5+s.rng.Intn(10); i++ {
+			x := s.rng.Intn(s.gridSize)
+			y := s.rng.Intn(s.gridSize)
+			if s.grid.at(x, y) > 0 {
+				s.change(y*s.gridSize+x, 1+s.rng.Intn(20))
+			}
+		}
+		mutated = true
+	}
+	if s.radiation != nil {
+		s.radiate()
+	}
+	symmetrize(s.grid, s.symmetry, &s.tally)
+
+	if s.ruleDrift {
+		s.thresholds.mutate(s.rng)
+	}
+	wave := s.seasonWave()
+	params := evolveParams{
+		growthRate:  s.growthRate * (1 + seasonGrowth*wave),
+		temperature: s.temperature,
+		curves:      s.ageCurves,
+		birth:       s.birthCurve,
+		nutrients:   s.nutrients,
+		agingShift:  seasonAging * wave,
+		drift:       s.drift,
+		thresholds:  s.thresholds,
+		sums:        &s.sums,
+		tally:       &s.tally,
+	}
+	if s.zones != nil {
+		params.zones = s.zones
+		params.climates = s.zoneClimates()
+	}
+	births, rebirths := evolve(s.grid, s.next, s.rng, params, s.reborn)
+	s.grid, s.next = s.next, s.grid
+	symmetrize(s.grid, s.symmetry, &s.tally)
+	failed, starved := 0, 0
+	if s.energy != nil {
+		failed, starved = s.metabolize(s.next)
+	}
+	if s.nutrients != nil {
+		s.feed()
+	}
+	if s.movementRate > 0 {
+		s.migrate()
+	}
+	if s.immigration > 0 {
+		births += s.immigrate()
+	}
+	if s.fixtures != nil {
+		births += s.runFixtures()
+	}
+	diseaseDeaths, infected := 0, 0
+	if s.infection != nil {
+		diseaseDeaths, infected = s.spread()
+	}
+	s.moveAnts()
+	s.totalRebirths += rebirths
+	s.stats = s.tally.stats(s.generation, s.gridSize)
+	s.stats.births = births - failed
+	s.stats.rebirths = rebirths
+	if s.energy != nil {
+		s.stats.avgEnergy = s.averageEnergy()
+		s.stats.starved = starved
+	}
+	s.stats.infected = infected
+	s.stats.diseaseDeaths = diseaseDeaths
+	s.updateColonies()
+	s.lineages.update(s.grid, s.generation)
+	s.trackChanges()
+	return mutated
+}
+
+// supernova clears the disc of radius around a center, and irradiates it
+// when radiation is on and the built-in rules run.
+func (s *Simulation) supernova(cx, cy, radius int, radiation bool) {
+	for y := 0; y < s.gridSize; y++ {
+		for x := 0; x < s.gridSize; x++ {
+			dx, dy := x-cx, y-cy
+			if dx*dx+dy*dy < radius*radius {
+				s.setCell(x, y, 0)
+			}
+		}
+	}
+	if radiation && s.rule == nil {
+		s.irradiate(cx, cy, radius)
+	}
+}
+
+// randomCell draws a cell from the run's source, e.g. the center of a
+// supernova, so seeded runs repeat it.
+func (s *Simulation) randomCell() (x, y int) {
+	return s.rng.Intn(s.gridSize), s.rng.Intn(s.gridSize)
+}
+
+// setCell changes a cell and, when a symmetry is active, its whole orbit, so
+// interventions keep the grid symmetric.
+func (s *Simulation) setCell(x, y, val int) {
+	for _, p := range s.symmetry.orbit(nil, x, y, s.gridSize) {
+		s.putCell(p.X, p.Y, val)
+	}
+}
+
+// putCell changes a single cell, its energy and the rule's copy of it.
+func (s *Simulation) putCell(x, y, val int) {
+	s.change(y*s.gridSize+x, val)
+	if s.energy != nil {
+		e := &s.energy[y*s.gridSize+x]
+		if val == 0 {
+			*e = 0
+		} else if *e == 0 {
+			*e = energyBirth
+		}
+	}
+	if s.rule != nil {
+		s.rule.set(x, y, val)
+	}
+}
+
+// stamp pastes the living cells of values centered on (cx, cy) through
+// setCell, leaving the cells under its empty ones as they are; cells off
+// the grid are dropped.
+func (s *Simulation) stamp(values [][]int, cx, cy int) {
+	width := 0
+	for _, row := range values {
+		width = max(width, len(row))
+	}
+	for y, row := range values {
+		for x, v := range row {
+			gx, gy := cx-width/2+x, cy-len(values)/2+y
+			if v > 0 && gx >= 0 && gx < s.gridSize && gy >= 0 && gy < s.gridSize {
+				s.setCell(gx, gy, min(v, maxCellAge))
+			}
+		}
+	}
+}
+
+// isFull reports whether every cell of the grid is alive.
+func (s *Simulation) isFull() bool {
+	return s.stats.population >= s.gridSize*s.gridSize
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/maximedotair/living_numbers_game_go/livingnumbers"
+)
+
+// fixedRand is a Rand drawing the same values over and over: f from
+// Float64, and n, reduced into range, from Intn.
+type fixedRand struct {
+	f float64
+	n int
+}
+
+func (r fixedRand) Float64() float64 { return r.f }
+func (r fixedRand) Intn(n int) int   { return r.n % n }
+func (r fixedRand) Int63() int64     { return int64(r.n) }
+
+func (r fixedRand) Perm(n int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	return p
+}
+
+func TestResetDrawsFromInjectedSource(t *testing.T) {
+	sim := newSimulation(20, 1)
+	sim.newRand = func(int64) Rand { return fixedRand{n: 3} }
+	sim.reset(7)
+	// Every cell scattered lands on (3, 3), aged 3+1
+	for y := range sim.gridSize {
+		for x := range sim.gridSize {
+			want := 0
+			if x == 3 && y == 3 {
+				want = 4
+			}
+			if got := sim.grid.at(x, y); got != want {
+				t.Fatalf("cell (%d, %d) = %d, want %d", x, y, got, want)
+			}
+		}
+	}
+	if sim.seed != 7 || sim.stats.population != 1 {
+		t.Errorf("seed %d, population %d; want 7 and 1", sim.seed, sim.stats.population)
+	}
+}
+
+func TestSameSeedSameRun(t *testing.T) {
+	a, b := newSimulation(40, 1), newSimulation(40, 1)
+	for _, s := range []*Simulation{a, b} {
+		s.growthRate, s.mutationChance = 0.3, 0.05
+		s.reset(42)
+		for range 50 {
+			s.step()
+		}
+	}
+	if !slices.Equal(a.grid.cells, b.grid.cells) {
+		t.Error("two runs of the same seed differ after 50 generations")
+	}
+}
+
+// TestEvolveTransitions checks the fate of the middle cell of a 3×3 grid,
+// its neighbours summing to sum, for a fixed random draw.
+func TestEvolveTransitions(t *testing.T) {
+	tests := []struct {
+		name         string
+		val, sum     int
+		draw         float64
+		want         int
+		wantRebirths int
+	}{
+		{"born below the chance", 0, 25, 0.19, 1, 0},
+		{"unborn above the chance", 0, 25, 0.21, 0, 0},
+		{"dies short of survival", 5, 2, 0.5, 0, 0},
+		{"survives", 5, 3, 0.5, 5, 0},
+		{"ages past the ageing threshold", 5, 21, 0.5, 6, 0},
+		{"reborn past the oldest age", 50, 21, 0.5, 1, 1},
+	}
+	params := evolveParams{
+		growthRate: 0.4, // a sum of 25 gives a birth chance of 0.2
+		curves:     defaultAgeCurves,
+		birth:      defaultBirthCurve,
+		thresholds: defaultThresholds,
+	}
+	for _, tt := range tests {
+		g, next := newGrid(3), newGrid(3)
+		g.set(1, 1, tt.val)
+		g.set(0, 0, tt.sum) // the only neighbour
+		_, rebirths := evolve(g, next, fixedRand{f: tt.draw}, params, nil)
+		if got := next.at(1, 1); got != tt.want {
+			t.Errorf("%s: cell = %d, want %d", tt.name, got, tt.want)
+		}
+		if rebirths != tt.wantRebirths {
+			t.Errorf("%s: %d rebirths, want %d", tt.name, rebirths, tt.wantRebirths)
+		}
+	}
+}
+
+// stepRule runs a rule family for gens generations from the living cells
+// of cells, and returns the grid values.
+func stepRule(t *testing.T, rule Rule, size, gens int, cells map[image.Point]int) *Grid {
+	t.Helper()
+	sim := newSimulation(size, 1)
+	sim.rule = rule
+	for p, v := range cells {
+		sim.grid.set(p.X, p.Y, v)
+	}
+	rule.load(sim)
+	for range gens {
+		sim.step()
+	}
+	return sim.grid
+}
+
+// living returns the cells of g in the given band.
+func living(g *Grid, band int) []image.Point {
+	var pts []image.Point
+	for y := range g.size {
+		for x := range g.size {
+			if cellBand(g.at(x, y)) == band {
+				pts = append(pts, image.Pt(x, y))
+			}
+		}
+	}
+	return pts
+}
+
+func alive(g *Grid) []image.Point {
+	var pts []image.Point
+	for _, band := range []int{bandYoung, bandMature, bandOld} {
+		pts = append(pts, living(g, band)...)
+	}
+	slices.SortFunc(pts, func(a, b image.Point) int {
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+		return a.X - b.X
+	})
+	return pts
+}
+
+func TestConwayLifeTransitions(t *testing.T) {
+	horizontal := []image.Point{{1, 2}, {2, 2}, {3, 2}}
+	vertical := []image.Point{{2, 1}, {2, 2}, {2, 3}}
+	blinker := map[image.Point]int{{1, 2}: 1, {2, 2}: 1, {3, 2}: 1}
+	for _, rule := range []Rule{conwayLife{}, &hashLife{}} {
+		if got := alive(stepRule(t, rule, 5, 1, blinker)); !slices.Equal(got, vertical) {
+			t.Errorf("%s: blinker after 1 generation = %v, want %v", rule.Name(), got, vertical)
+		}
+		if got := alive(stepRule(t, rule, 5, 2, blinker)); !slices.Equal(got, horizontal) {
+			t.Errorf("%s: blinker after 2 generations = %v, want %v", rule.Name(), got, horizontal)
+		}
+	}
+
+	block := []image.Point{{1, 1}, {2, 1}, {1, 2}, {2, 2}}
+	g := stepRule(t, conwayLife{}, 4, 3, map[image.Point]int{{1, 1}: 1, {2, 1}: 1, {1, 2}: 1, {2, 2}: 1})
+	if got := alive(g); !slices.Equal(got, block) {
+		t.Errorf("block after 3 generations = %v, want %v", got, block)
+	}
+	if got := g.at(1, 1); got != 4 {
+		t.Errorf("block cell aged %d, want 4", got)
+	}
+}
+
+func TestBriansBrainTransitions(t *testing.T) {
+	// Two firing cells light the cells next to both, then die
+	g := stepRule(t, briansBrain{}, 4, 1, map[image.Point]int{{1, 1}: youngState, {1, 2}: youngState})
+	if got, want := living(g, bandYoung), []image.Point{{0, 1}, {2, 1}, {0, 2}, {2, 2}}; !slices.Equal(got, want) {
+		t.Errorf("firing = %v, want %v", got, want)
+	}
+	if got, want := living(g, bandMature), []image.Point{{1, 1}, {1, 2}}; !slices.Equal(got, want) {
+		t.Errorf("dying = %v, want %v", got, want)
+	}
+	g = stepRule(t, briansBrain{}, 4, 1, map[image.Point]int{{1, 1}: matureState})
+	if got := alive(g); len(got) != 0 {
+		t.Errorf("a dying cell left %v", got)
+	}
+}
+
+func TestWireworldTransitions(t *testing.T) {
+	// An electron running right along a wire
+	wire := map[image.Point]int{{0, 1}: matureState, {1, 1}: youngState, {2, 1}: oldState, {3, 1}: oldState}
+	g := stepRule(t, wireworld{}, 4, 1, wire)
+	want := []int{oldState, matureState, youngState, oldState}
+	for x, w := range want {
+		if got := g.at(x, 1); cellBand(got) != cellBand(w) {
+			t.Errorf("cell %d = %d, want band of %d", x, got, w)
+		}
+	}
+	if got := alive(g); len(got) != 4 {
+		t.Errorf("the wire changed shape: %v", got)
+	}
+}
+
+func TestMutationBurstDrawsFromSource(t *testing.T) {
+	for _, tt := range []struct {
+		draw float64
+		want bool
+	}{{0.01, true}, {0.5, false}} {
+		sim := newSimulation(10, 1)
+		sim.growthRate, sim.mutationChance = 0.2, 0.1
+		sim.newRand = func(int64) Rand { return fixedRand{f: tt.draw} }
+		sim.reset(1)
+		if got := sim.step(); got != tt.want {
+			t.Errorf("draw %v against a chance of 0.1: mutated = %v, want %v", tt.draw, got, tt.want)
+		}
+	}
+}
+
+func TestSupernovaLandsOnDrawnCell(t *testing.T) {
+	sim := newSimulation(30, 1)
+	sim.newRand = func(int64) Rand { return fixedRand{n: 12} }
+	sim.reset(1)
+	for y := range sim.gridSize {
+		for x := range sim.gridSize {
+			sim.grid.set(x, y, 1)
+		}
+	}
+	cx, cy := sim.randomCell()
+	if cx != 12 || cy != 12 {
+		t.Fatalf("center (%d, %d), want (12, 12)", cx, cy)
+	}
+	sim.supernova(cx, cy, 5, false)
+	for y := range sim.gridSize {
+		for x := range sim.gridSize {
+			dx, dy := x-12, y-12
+			want := 1
+			if dx*dx+dy*dy < 25 {
+				want = 0
+			}
+			if got := sim.grid.at(x, y); got != want {
+				t.Fatalf("cell (%d, %d) = %d, want %d", x, y, got, want)
+			}
+		}
+	}
+}
+
+// TestLibraryMatchesEngine runs the library and the app's engine side by
+// side: with the app's features off they must go through the same
+// generations.
+func TestLibraryMatchesEngine(t *testing.T) {
+	for _, temperature := range []float64{0, 1} {
+		rules := livingnumbers.DefaultRules()
+		rules.GrowthRate, rules.MutationChance, rules.Temperature = 0.3, 0.05, temperature
+		lib := livingnumbers.New(40, 7, rules)
+		sim := newSimulation(40, 7)
+		sim.growthRate, sim.mutationChance, sim.temperature = 0.3, 0.05, temperature
+		sim.reset(7)
+		for gen := range 200 {
+			if gen > 0 {
+				if got, want := lib.Step(), sim.step(); got != want {
+					t.Fatalf("temperature %v, generation %d: mutated = %v, want %v", temperature, gen, got, want)
+				}
+			}
+			for i, c := range sim.grid.cells {
+				if got := lib.Grid().At(i%40, i/40); got != int(c) {
+					t.Fatalf("temperature %v, generation %d: cell %d = %d, want %d", temperature, gen, i, got, c)
+				}
+			}
+			if st := lib.Stats(); st.Population != sim.stats.population || st.Births != sim.stats.births || st.Entropy != sim.stats.entropy {
+				t.Fatalf("temperature %v, generation %d: stats %+v differ from %+v", temperature, gen, st, sim.stats)
+			}
+		}
+	}
+}
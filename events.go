@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+const defaultEventCapacity = 5000
+
+// EventHistory is the single source of logged events. The newest capacity
+// events stay in memory; older ones are spilled to the session store in
+// batches so long runs keep their full history without growing unbounded.
+type EventHistory struct {
+	mu       sync.Mutex
+	capacity int
+	events   []Event
+	store    *sessionStore
+	spilled  int
+	dropped  int // spilled events lost because no store was available
+}
+
+func newEventHistory(capacity int, store *sessionStore) *EventHistory {
+	if capacity < 1 {
+		capacity = defaultEventCapacity
+	}
+	return &EventHistory{capacity: capacity, store: store}
+}
+
+func (h *EventHistory) add(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events = append(h.events, e)
+	if len(h.events) <= h.capacity {
+		return
+	}
+
+	// Spill a quarter of the capacity at once to keep file writes rare.
+	n := len(h.events) - h.capacity + h.capacity/4
+	if n > len(h.events) {
+		n = len(h.events)
+	}
+	if h.store == nil || h.store.appendEvents(h.events[:n]) != nil {
+		h.dropped += n
+	} else {
+		h.spilled += n
+	}
+	h.events = append(h.events[:0], h.events[n:]...)
+}
+
+// flush spills every in-memory event to the session store, so the session
+// directory holds the complete history once the application exits.
+func (h *EventHistory) flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.store == nil || len(h.events) == 0 {
+		return nil
+	}
+	if err := h.store.appendEvents(h.events); err != nil {
+		return err
+	}
+	h.spilled += len(h.events)
+	h.events = h.events[:0]
+	return nil
+}
+
+// recent returns up to n most recent events, newest first.
+func (h *EventHistory) recent(n int) []Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if n > len(h.events) {
+		n = len(h.events)
+	}
+	out := make([]Event, n)
+	for i := 0; i < n; i++ {
+		out[i] = h.events[len(h.events)-1-i]
+	}
+	return out
+}
+
+// all returns the complete history, oldest first, including spilled events.
+func (h *EventHistory) all() ([]Event, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var out []Event
+	if h.store != nil && h.spilled > 0 {
+		stored, err := h.store.readEvents()
+		if err != nil {
+			return nil, err
+		}
+		out = stored
+	}
+	return append(out, h.events...), nil
+}
+
+// total is the number of events ever logged, in memory or not.
+func (h *EventHistory) total() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.events) + h.spilled + h.dropped
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("[Gen %d] %s: %s", e.generation, e.eventType, e.message)
+}
+
+// writeText exports the complete history, one event per line.
+func (h *EventHistory) writeText(w io.Writer) error {
+	events, err := h.all()
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		if _, err := fmt.Fprintln(w, e.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
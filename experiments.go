@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// SweepConfig describes a headless parameter sweep over growth rate and
+// mutation chance.
+type SweepConfig struct {
+	growthMin     float64
+	growthMax     float64
+	growthSteps   int
+	mutationMin   float64
+	mutationMax   float64
+	mutationSteps int
+	runs          int
+	generations   int
+	gridSize      int
+	seed          int64
+}
+
+// SweepResult aggregates the runs of one parameter combination.
+type SweepResult struct {
+	growthRate     float64
+	mutationChance float64
+	runs           int
+	filled         int
+	avgGensToFill  float64
+	peakEntropy    float64
+	finalDensity   float64
+}
+
+// sweepValues returns steps evenly spaced values from min to max inclusive.
+func sweepValues(min, max float64, steps int) []float64 {
+	if steps <= 1 {
+		return []float64{min}
+	}
+	values := make([]float64, steps)
+	for i := range values {
+		values[i] = min + (max-min)*float64(i)/float64(steps-1)
+	}
+	return values
+}
+
+// runSweepCombination runs every seed of one combination. Run i always uses
+// seed cfg.seed+i so that all combinations start from the same grids.
+func runSweepCombination(cfg SweepConfig, growthRate, mutationChance float64) SweepResult {
+	res := SweepResult{growthRate: growthRate, mutationChance: mutationChance, runs: cfg.runs}
+	totalGens := 0
+	for run := 0; run < cfg.runs; run++ {
+		sim := newSimulation(cfg.gridSize, 0)
+		sim.growthRate = growthRate
+		sim.mutationChance = mutationChance
+		sim.reset(cfg.seed + int64(run))
+
+		peak := sim.stats.entropy
+		for sim.generation < cfg.generations {
+			sim.step()
+			if sim.stats.entropy > peak {
+				peak = sim.stats.entropy
+			}
+			if sim.isFull() {
+				res.filled++
+				totalGens += sim.generation
+				break
+			}
+		}
+		res.peakEntropy += peak
+		res.finalDensity += sim.stats.density
+	}
+	if cfg.runs > 0 {
+		res.peakEntropy /= float64(cfg.runs)
+		res.finalDensity /= float64(cfg.runs)
+	}
+	if res.filled > 0 {
+		res.avgGensToFill = float64(totalGens) / float64(res.filled)
+	}
+	return res
+}
+
+// runSweep executes the whole sweep, calling progress after each combination.
+// It stops early, returning the combinations done so far, when ctx is done.
+func runSweep(ctx context.Context, cfg SweepConfig, progress func(done, total int)) []SweepResult {
+	growths := sweepValues(cfg.growthMin, cfg.growthMax, cfg.growthSteps)
+	mutations := sweepValues(cfg.mutationMin, cfg.mutationMax, cfg.mutationSteps)
+	total := len(growths) * len(mutations)
+
+	results := make([]SweepResult, 0, total)
+	for _, g := range growths {
+		for _, m := range mutations {
+			if ctx.Err() != nil {
+				return results
+			}
+			results = append(results, runSweepCombination(cfg, g, m))
+			if progress != nil {
+				progress(len(results), total)
+			}
+		}
+	}
+	return results
+}
+
+var sweepColumns = []string{"Growth", "Mutation", "Filled", "Gens to fill", "Peak entropy", "Final density"}
+
+func sweepCell(r SweepResult, col int) string {
+	switch col {
+	case 0:
+		return fmt.Sprintf("%.3f", r.growthRate)
+	case 1:
+		return fmt.Sprintf("%.4f", r.mutationChance)
+	case 2:
+		return fmt.Sprintf("%d/%d", r.filled, r.runs)
+	case 3:
+		if r.filled == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f", r.avgGensToFill)
+	case 4:
+		return fmt.Sprintf("%.3f", r.peakEntropy)
+	default:
+		return fmt.Sprintf("%.1f%%", r.finalDensity*100)
+	}
+}
+
+// newExperimentsTab builds the parameter sweep form and its results table.
+func newExperimentsTab(driver fyne.Driver, life *lifetime, seed int64, state *SimulationState) fyne.CanvasObject {
+	newEntry := func(text string) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetText(text)
+		return e
+	}
+	growthMin := newEntry("0.05")
+	growthMax := newEntry("0.30")
+	growthSteps := newEntry("6")
+	mutationMin := newEntry("0")
+	mutationMax := newEntry("0.05")
+	mutationSteps := newEntry("3")
+	runs := newEntry("3")
+	generations := newEntry("2000")
+	seedEntry := newEntry(strconv.FormatInt(seed, 10))
+
+	form := widget.NewForm(
+		widget.NewFormItem(lang.L("Growth from"), growthMin),
+		widget.NewFormItem(lang.L("Growth to"), growthMax),
+		widget.NewFormItem(lang.L("Growth steps"), growthSteps),
+		widget.NewFormItem(lang.L("Mutation from"), mutationMin),
+		widget.NewFormItem(lang.L("Mutation to"), mutationMax),
+		widget.NewFormItem(lang.L("Mutation steps"), mutationSteps),
+		widget.NewFormItem(lang.L("Runs each"), runs),
+		widget.NewFormItem(lang.L("Max generations"), generations),
+		widget.NewFormItem(lang.L("Base seed"), seedEntry),
+	)
+
+	var results []SweepResult
+	table := widget.NewTable(
+		func() (int, int) { return len(results) + 1, len(sweepColumns) },
+		func() fyne.CanvasObject { return widget.NewLabel(lang.L("Final density")) },
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			if id.Row == 0 {
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				label.SetText(lang.L(sweepColumns[id.Col]))
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			label.SetText(sweepCell(results[id.Row-1], id.Col))
+		},
+	)
+
+	progress := widget.NewProgressBar()
+	statusLabel := widget.NewLabel(lang.L("Define ranges and press Run sweep"))
+
+	var runButton *widget.Button
+	runButton = widget.NewButton(lang.L("▶ Run sweep"), func() {
+		var invalid []string
+		parseF := func(e *widget.Entry, name string) float64 {
+			v, err := strconv.ParseFloat(e.Text, 64)
+			if err != nil {
+				invalid = append(invalid, lang.L(name))
+			}
+			return v
+		}
+		parseI := func(e *widget.Entry, name string) int {
+			v, err := strconv.Atoi(e.Text)
+			if err != nil || v <= 0 {
+				invalid = append(invalid, lang.L(name))
+			}
+			return v
+		}
+
+		cfg := SweepConfig{
+			growthMin:     parseF(growthMin, "growth from"),
+			growthMax:     parseF(growthMax, "growth to"),
+			growthSteps:   parseI(growthSteps, "growth steps"),
+			mutationMin:   parseF(mutationMin, "mutation from"),
+			mutationMax:   parseF(mutationMax, "mutation to"),
+			mutationSteps: parseI(mutationSteps, "mutation steps"),
+			runs:          parseI(runs, "runs"),
+			generations:   parseI(generations, "max generations"),
+		}
+		s, err := strconv.ParseInt(seedEntry.Text, 10, 64)
+		if err != nil {
+			invalid = append(invalid, lang.L("seed"))
+		}
+		if len(invalid) > 0 {
+			statusLabel.SetText(lang.LocalizeKey("experiments.invalid", "Invalid value for: {{.Fields}}", map[string]any{"Fields": strings.Join(invalid, ", ")}))
+			return
+		}
+		cfg.seed = s
+		cfg.gridSize = state.gridSize
+
+		runButton.Disable()
+		results = nil
+		table.Refresh()
+		progress.SetValue(0)
+		statusLabel.SetText(fmt.Sprintf(lang.L("Running on %dx%d grid..."), cfg.gridSize, cfg.gridSize))
+
+		life.spawn(func() {
+			res := runSweep(life.ctx, cfg, func(done, total int) {
+				runOnMain(driver, func() {
+					progress.SetValue(float64(done) / float64(total))
+				})
+			})
+			runOnMain(driver, func() {
+				results = res
+				table.Refresh()
+				statusLabel.SetText(fmt.Sprintf(lang.L("Sweep complete: %d combinations x %d runs"), len(res), cfg.runs))
+				runButton.Enable()
+			})
+		})
+	})
+
+	for col := range sweepColumns {
+		table.SetColumnWidth(col, 110)
+	}
+
+	top := container.NewVBox(
+		widget.NewLabel(lang.L("🧪 Parameter sweep")),
+		widget.NewSeparator(),
+		form,
+		runButton,
+		progress,
+		statusLabel,
+	)
+	return container.NewBorder(top, nil, nil, nil, table)
+}
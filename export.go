@@ -0,0 +1,30 @@
+//go:build !js
+
+package main
+
+import (
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// saveFile asks for a destination with the file dialog, suggesting name,
+// and writes the export there. The browser build downloads it instead.
+func saveFile(w fyne.Window, name, mimeType string, write func(io.Writer) error) {
+	d := dialog.NewFileSave(func(wc fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if wc == nil {
+			return
+		}
+		defer wc.Close()
+		if err := write(wc); err != nil {
+			dialog.ShowError(err, w)
+		}
+	}, w)
+	d.SetFileName(name)
+	d.Show()
+}
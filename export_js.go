@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"syscall/js"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// saveFile hands the export to the browser as a download named name: a
+// page cannot write to the local file system, so there is no file dialog.
+func saveFile(w fyne.Window, name, mimeType string, write func(io.Writer) error) {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+	data := js.Global().Get("Uint8Array").New(buf.Len())
+	js.CopyBytesToJS(data, buf.Bytes())
+	blob := js.Global().Get("Blob").New([]any{data}, map[string]any{"type": mimeType})
+	urls := js.Global().Get("URL")
+	url := urls.Call("createObjectURL", blob)
+
+	doc := js.Global().Get("document")
+	link := doc.Call("createElement", "a")
+	link.Set("href", url)
+	link.Set("download", name)
+	doc.Get("body").Call("appendChild", link)
+	link.Call("click")
+	link.Call("remove")
+
+	// The download starts asynchronously, so the URL outlives the click
+	time.AfterFunc(time.Minute, func() { urls.Call("revokeObjectURL", url) })
+}
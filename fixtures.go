@@ -0,0 +1,127 @@
+package main
+
+import (
+	"image"
+	"image/color"
+)
+
+// fixture is a permanent feature placed on a cell of the grid by the user.
+// Fixture cells stay empty themselves.
+type fixture byte
+
+const (
+	noFixture fixture = iota
+	fountain          // seeds its empty neighbours every fountainPeriod generations
+	blackHole         // kills its living neighbours every generation
+)
+
+// fountainPeriod is the number of generations between two fountain spurts.
+const fountainPeriod = 10
+
+// placeTools are the tools of the placement selector, indexed by the tool
+// constants below.
+var placeTools = []string{"Paint cells", "⛲ Fountain", "🕳 Black hole", "Erase fixtures", "💥 Supernova", "🗺 Zone brush"}
+
+// Placement tools; the fixture tools share the value of their fixture.
+const (
+	paintTool     = 0
+	eraseTool     = 3
+	supernovaTool = 4
+	zoneTool      = 5
+)
+
+// Fixture colors, drawn over any palette.
+var (
+	fountainColor  = color.RGBA{80, 200, 255, 255}
+	blackHoleColor = color.RGBA{0, 0, 0, 255}
+	blackHoleRim   = color.RGBA{150, 60, 200, 255}
+)
+
+// placeFixture puts f on (x, y), emptying the cell, or removes the fixture
+// there for noFixture.
+func (s *Simulation) placeFixture(x, y int, f fixture) {
+	if s.fixtures == nil {
+		if f == noFixture {
+			return
+		}
+		s.fixtures = make([]fixture, s.gridSize*s.gridSize)
+	}
+	if s.edit != nil {
+		s.edit.fixtures.note(y*s.gridSize+x, s.fixtures[y*s.gridSize+x], f)
+	}
+	s.fixtures[y*s.gridSize+x] = f
+	if f != noFixture {
+		s.change(y*s.gridSize+x, 0)
+		if s.energy != nil {
+			s.energy[y*s.gridSize+x] = 0
+		}
+	}
+}
+
+// runFixtures applies the fixtures to the generation just computed:
+// fixture cells are kept empty, black holes kill their living neighbours and,
+// every fountainPeriod generations, fountains fill their empty neighbours
+// with newborns. It returns the newborns.
+func (s *Simulation) runFixtures() (spawned int) {
+	n := s.gridSize
+	spurt := s.generation%fountainPeriod == 0
+	for i, f := range s.fixtures {
+		if f == noFixture {
+			continue
+		}
+		x, y := i%n, i/n
+		s.change(i, 0)
+		for ny := max(y-1, 0); ny <= min(y+1, n-1); ny++ {
+			for nx := max(x-1, 0); nx <= min(x+1, n-1); nx++ {
+				j := ny*n + nx
+				if s.fixtures[j] != noFixture {
+					continue
+				}
+				c := s.grid.cells[j]
+				switch {
+				case f == blackHole && c > 0:
+					s.change(j, 0)
+					if s.energy != nil {
+						s.energy[j] = 0
+					}
+				case f == fountain && spurt && c == 0:
+					s.change(j, 1)
+					if s.energy != nil {
+						s.energy[j] = energyBirth
+					}
+					spawned++
+				}
+			}
+		}
+	}
+	return spawned
+}
+
+// drawFixtures paints the fountains and black holes, black holes with a rim
+// when cells are large enough.
+func drawFixtures(img *image.RGBA, fixtures []fixture, gridSize, cellSize int) {
+	bounds := img.Bounds()
+	for i, f := range fixtures {
+		if f == noFixture {
+			continue
+		}
+		x, y := i%gridSize, i/gridSize
+		for dy := 0; dy < cellSize; dy++ {
+			for dx := 0; dx < cellSize; dx++ {
+				p := image.Pt(bounds.Min.X+x*cellSize+dx, bounds.Min.Y+y*cellSize+dy)
+				if !p.In(bounds) {
+					continue
+				}
+				edge := dx == 0 || dy == 0 || dx == cellSize-1 || dy == cellSize-1
+				switch {
+				case f == fountain:
+					img.SetRGBA(p.X, p.Y, fountainColor)
+				case edge && cellSize >= 4:
+					img.SetRGBA(p.X, p.Y, blackHoleRim)
+				default:
+					img.SetRGBA(p.X, p.Y, blackHoleColor)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// launchOptions are the initial parameters given on the command line, so
+// scripts and desktop shortcuts can open a configured, running simulation.
+type launchOptions struct {
+	growthRate     float64
+	mutationChance float64
+	cellSize       int
+	speed          int
+	fps            int   // frames drawn per second at most
+	seed           int64 // 0 picks a random seed
+	autostart      bool
+	checkpoint     int // generations between checkpoints, 0 disables them
+	eventCapacity  int // events kept in memory before spilling to the session store
+	logLevel       slog.Level
+	logFormat      string // "text" or "json"
+	logFile        string // "" logs to stderr
+	statsOut       string // JSON Lines statistics, "-" for stdout; "" for none
+	osc            string // host:port of an OSC receiver, "" for none
+	batch          int    // runs of a headless batch, 0 opens the window
+	batchGens      int    // generation limit of each batch run
+	batchOut       string // summary CSV of the batch, "-" for stdout
+	manifest       string // experiment manifest run without a window, "" for none
+}
+
+// defaultLaunchOptions are the built-in initial parameters.
+var defaultLaunchOptions = launchOptions{
+	growthRate:     0.05,
+	mutationChance: 0.01,
+	cellSize:       5,
+	speed:          50,
+	fps:            30,
+	checkpoint:     defaultCheckpointInterval,
+	eventCapacity:  defaultEventCapacity,
+	logLevel:       slog.LevelWarn,
+	logFormat:      "text",
+	batchGens:      2000,
+	batchOut:       "-",
+}
+
+// parseFlags parses args (without the program name), with defaults as the
+// values of unset flags. Values outside the ranges of the matching sliders
+// are rejected.
+func parseFlags(args []string, defaults launchOptions, output io.Writer) (launchOptions, error) {
+	opts := defaults
+	fs := flag.NewFlagSet("living-numbers", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Float64Var(&opts.growthRate, "growth", defaults.growthRate, "growth rate, 0.05-0.5")
+	fs.Float64Var(&opts.mutationChance, "mutation", defaults.mutationChance, "mutation chance per generation, 0-0.1")
+	fs.IntVar(&opts.cellSize, "cellsize", defaults.cellSize, "cell size in pixels, 2-8")
+	fs.IntVar(&opts.speed, "speed", defaults.speed, "milliseconds per generation, 10-200")
+	fs.IntVar(&opts.fps, "fps", defaults.fps, "frames drawn per second at most, 1-60; faster generations are not drawn")
+	fs.Int64Var(&opts.seed, "seed", 0, "seed of the first run (0 for a random one)")
+	fs.BoolVar(&opts.autostart, "autostart", false, "start the simulation on launch")
+	fs.IntVar(&opts.checkpoint, "checkpoint", defaults.checkpoint, "generations between crash-recovery checkpoints, 0 to disable")
+	fs.IntVar(&opts.eventCapacity, "event-capacity", defaults.eventCapacity, "events kept in memory; older ones spill to the session directory")
+	fs.TextVar(&opts.logLevel, "log-level", defaults.logLevel, "minimum log level: debug, info, warn or error")
+	fs.StringVar(&opts.logFormat, "log-format", defaults.logFormat, "log record format: text or json")
+	fs.StringVar(&opts.logFile, "log-file", defaults.logFile, "append logs to this file instead of stderr")
+	fs.StringVar(&opts.statsOut, "stats-out", defaults.statsOut, "write one JSON object of statistics and events per generation to this file, or - for stdout")
+	fs.StringVar(&opts.osc, "osc", defaults.osc, "send statistics and events as OSC messages over UDP to this host:port")
+	fs.IntVar(&opts.batch, "batch", 0, "run this many seeded simulations without a window, from -seed on, and summarize them")
+	fs.IntVar(&opts.batchGens, "batch-gens", defaults.batchGens, "generation limit of each -batch run")
+	fs.StringVar(&opts.batchOut, "batch-out", defaults.batchOut, "append the -batch summary as a CSV row to this file, or - for stdout")
+	fs.StringVar(&opts.manifest, "run-manifest", "", "run the experiment described by this YAML or JSON manifest without a window")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	switch {
+	case opts.growthRate < 0.05 || opts.growthRate > 0.5:
+		return opts, fmt.Errorf("-growth must be between 0.05 and 0.5, got %g", opts.growthRate)
+	case opts.mutationChance < 0 || opts.mutationChance > 0.1:
+		return opts, fmt.Errorf("-mutation must be between 0 and 0.1, got %g", opts.mutationChance)
+	case opts.cellSize < 2 || opts.cellSize > 8:
+		return opts, fmt.Errorf("-cellsize must be between 2 and 8, got %d", opts.cellSize)
+	case opts.speed < 10 || opts.speed > 200:
+		return opts, fmt.Errorf("-speed must be between 10 and 200, got %d", opts.speed)
+	case opts.fps < 1 || opts.fps > 60:
+		return opts, fmt.Errorf("-fps must be between 1 and 60, got %d", opts.fps)
+	case opts.checkpoint < 0:
+		return opts, fmt.Errorf("-checkpoint must not be negative, got %d", opts.checkpoint)
+	case opts.eventCapacity < 1:
+		return opts, fmt.Errorf("-event-capacity must be positive, got %d", opts.eventCapacity)
+	case opts.batch < 0:
+		return opts, fmt.Errorf("-batch must not be negative, got %d", opts.batch)
+	case opts.batchGens < 1:
+		return opts, fmt.Errorf("-batch-gens must be positive, got %d", opts.batchGens)
+	case opts.batch > 0 && opts.manifest != "":
+		return opts, fmt.Errorf("-batch and -run-manifest cannot be combined")
+	case opts.logFormat != "text" && opts.logFormat != "json":
+		return opts, fmt.Errorf("-log-format must be text or json, got %q", opts.logFormat)
+	}
+	return opts, nil
+}
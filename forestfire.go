@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"image"
+)
+
+// forestFire is the Drossel-Schwabl forest-fire model with an ash stage:
+// trees (young) grow on empty cells, lightning ignites trees, fire (mature)
+// spreads to the four neighbours of each burning tree and leaves ash (old),
+// which clears at the next generation.
+type forestFire struct {
+	growth    float64 // chance of a tree growing on an empty cell
+	lightning float64 // chance of lightning striking a tree
+	burnt     int     // trees burnt by the current outbreak
+	start     int     // generation of the current outbreak's first strike
+}
+
+func (*forestFire) Name() string { return "Forest fire" }
+
+func (f *forestFire) tune(name string, value float64) {
+	switch name {
+	case "Tree growth":
+		f.growth = value
+	case "Lightning":
+		f.lightning = value
+	}
+}
+
+// seed plants a forest covering about half of the grid.
+func (*forestFire) seed(sim *Simulation) {
+	var pts []image.Point
+	for y := range sim.gridSize {
+		for x := range sim.gridSize {
+			if sim.rng.Intn(2) == 0 {
+				pts = setOrbit(sim, pts, x, y, youngState)
+			}
+		}
+	}
+}
+
+func (*forestFire) load(*Simulation) {}
+
+// step advances the forest, logging an outbreak when lightning strikes a
+// forest that is not burning, and its toll once the last fire is out.
+func (f *forestFire) step(sim *Simulation) (births int) {
+	g := sim.grid
+	burning, struck := 0, 0
+	for y := range g.size {
+		next := sim.next.row(y)
+		for x, c := range g.row(y) {
+			val := 0
+			switch cellBand(int(c)) {
+			case bandDead:
+				if sim.rng.Float64() < f.growth {
+					val = youngState
+					births++
+				}
+			case bandYoung:
+				val = youngState
+				if burningNeighbor(g, x, y) {
+					val = matureState
+				} else if sim.rng.Float64() < f.lightning {
+					val = matureState
+					struck++
+				}
+				if val == matureState {
+					burning++
+				}
+			case bandMature:
+				val = oldState
+			}
+			next[x] = uint8(val)
+		}
+	}
+	sim.grid, sim.next = sim.next, sim.grid
+
+	if struck > 0 && f.burnt == 0 {
+		f.start = sim.generation
+		sim.ruleEvents = append(sim.ruleEvents, ruleEvent{"FIRE", fmt.Sprintf("Lightning started %d fire(s)", struck)})
+	}
+	f.burnt += burning
+	if burning == 0 && f.burnt > 0 {
+		sim.ruleEvents = append(sim.ruleEvents, ruleEvent{"FIRE", fmt.Sprintf("Fire out after %d generations, %d trees burnt", sim.generation-f.start, f.burnt)})
+		f.burnt = 0
+	}
+	return births
+}
+
+// burningNeighbor reports whether one of the four neighbours of (x, y) is
+// on fire.
+func burningNeighbor(g *Grid, x, y int) bool {
+	for _, d := range [4]image.Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+		nx, ny := x+d.X, y+d.Y
+		if ny >= 0 && ny < g.size && nx >= 0 && nx < g.size && cellBand(g.at(nx, ny)) == bandMature {
+			return true
+		}
+	}
+	return false
+}
+
+func (*forestFire) set(x, y, val int) {}
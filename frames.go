@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"image"
+
+	"fyne.io/fyne/v2/canvas"
+)
+
+// frameBuffers double-buffers the grid display. The simulation goroutine
+// renders into an offscreen buffer from acquire, and the main thread swaps
+// it in with present, so the canvas never shows a half-drawn frame.
+type frameBuffers struct {
+	free chan *image.RGBA
+}
+
+func newFrameBuffers(bounds image.Rectangle) *frameBuffers {
+	f := &frameBuffers{free: make(chan *image.RGBA, 1)}
+	f.free <- image.NewRGBA(bounds)
+	return f
+}
+
+// acquire returns the offscreen buffer, waiting until the main thread has
+// released it. It returns nil if ctx is done first.
+func (f *frameBuffers) acquire(ctx context.Context) *image.RGBA {
+	select {
+	case frame := <-f.free:
+		return frame
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// present shows frame on display and recycles the previous front buffer as
+// the next offscreen one. It must run on the main thread.
+func (f *frameBuffers) present(display *canvas.Image, frame *image.RGBA) *image.RGBA {
+	if old, ok := display.Image.(*image.RGBA); ok && old != frame && old.Bounds() == frame.Bounds() {
+		f.free <- old
+	} else {
+		f.free <- image.NewRGBA(frame.Bounds())
+	}
+	display.Image = frame
+	display.Refresh()
+	return frame
+}
+
+// release hands back a buffer from acquire that was not presented.
+func (f *frameBuffers) release(frame *image.RGBA) {
+	f.free <- frame
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"image"
+	"image/png"
+	"slices"
+	"testing"
+)
+
+// The fuzz targets throw arbitrary input at the readers of files and text
+// the user may get from anywhere, and load what they accept into a small
+// simulation: neither may panic, and what they return stays within their
+// bounds. `go test` runs the seeds; `go test -fuzz FuzzReadCheckpoint`
+// explores further.
+
+const fuzzGridSize = 16
+
+const glider = "#N Glider\nx = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n"
+
+// loadValues seeds and stamps values into a fresh simulation.
+func loadValues(t *testing.T, values [][]int) {
+	t.Helper()
+	sim := newSimulation(fuzzGridSize, 1)
+	sim.seedFrom(values)
+	sim.stamp(values, fuzzGridSize/2, fuzzGridSize/2)
+	for _, c := range sim.grid.cells {
+		if c > maxCellAge {
+			t.Fatalf("loaded a cell aged %d", c)
+		}
+	}
+}
+
+// patternCells counts the cells of values, empty rows as one.
+func patternCells(values [][]int) int {
+	n := 0
+	for _, row := range values {
+		n += max(len(row), 1)
+	}
+	return n
+}
+
+func FuzzParsePattern(f *testing.F) {
+	for _, seed := range []string{
+		glider,
+		"!Name: Glider\n.O.\n..O\nOOO\n",
+		"x = 2, y = 2\n2pA$pBpC!",
+		"x = 0\n65536o!",
+		"x = 0\n99999999$!",
+		"",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		values, err := parsePattern(text)
+		if err != nil {
+			return
+		}
+		if n := patternCells(values); n > maxPatternCells+maxRLERun {
+			t.Fatalf("read %d cells, above the bound of %d", n, maxPatternCells)
+		}
+		loadValues(t, values)
+	})
+}
+
+func FuzzParseGridCSV(f *testing.F) {
+	f.Add([]byte("0,1,2\n3,4,5\n"))
+	f.Add([]byte("-1,999999999999\n\"7\"\n"))
+	f.Add([]byte("1,\"2\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		values, err := parseGridCSV(data)
+		if err != nil {
+			return
+		}
+		loadValues(t, values)
+	})
+}
+
+// fuzzCheckpoint returns the file of a checkpoint of a fresh run.
+func fuzzCheckpoint(f *testing.F) []byte {
+	sim := newSimulation(fuzzGridSize, 1)
+	sim.reset(1)
+	sim.placeFixture(3, 3, fountain)
+	cp := Checkpoint{
+		Seed:     1,
+		GridSize: sim.gridSize,
+		Cells:    sim.grid.cells,
+		Rule:     "Wireworld",
+	}
+	for _, fx := range sim.fixtures {
+		cp.Fixtures = append(cp.Fixtures, byte(fx))
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(cp); err != nil {
+		f.Fatal(err)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func FuzzReadCheckpoint(f *testing.F) {
+	valid := fuzzCheckpoint(f)
+	f.Add(valid)
+	f.Add(valid[:len(valid)/2])
+	f.Add([]byte{0x1f, 0x8b})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		cp, err := readCheckpoint(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		if cp.GridSize*cp.GridSize != len(cp.Cells) {
+			t.Fatalf("accepted %d cells for a %dx%d grid", len(cp.Cells), cp.GridSize, cp.GridSize)
+		}
+		sim := newSimulation(fuzzGridSize, 1)
+		cp.restore(sim)
+		sim.step()
+	})
+}
+
+func FuzzDecodeGridFile(f *testing.F) {
+	var img bytes.Buffer
+	png.Encode(&img, image.NewGray(image.Rect(0, 0, 4, 3)))
+	f.Add("glider.rle", []byte(glider))
+	f.Add("glider.cells", []byte(".O.\n..O\nOOO\n"))
+	f.Add("grid.csv", []byte("0,1\n2,3\n"))
+	f.Add("grid.png", img.Bytes())
+	f.Add("checkpoint.json.gz", fuzzCheckpoint(f))
+	f.Add("", []byte("\x89PNG\r\n\x1a\n"))
+	f.Fuzz(func(t *testing.T, name string, data []byte) {
+		values, err := decodeGridFile(name, data, fuzzGridSize)
+		if err != nil {
+			return
+		}
+		loadValues(t, values)
+	})
+}
+
+func FuzzParseSimCode(f *testing.F) {
+	f.Add(simCode{Seed: 1, Rule: "Forest fire", RuleParams: []float64{0.5, 1}}.String())
+	f.Add(simCode{Seed: 2, Climates: [][3]float64{{1, 2, 3}}}.String())
+	f.Add(simCode{Seed: 4, CellSize: 2, GridCells: 1000}.String())
+	f.Add(simCode{Seed: 5, GridCells: 123}.String())
+	f.Add(simCode{Seed: 3, Catastrophe: "Epidemic", CatastropheMin: 300, CatastropheMax: 100, InfectionRate: 7}.String())
+	f.Add(simCodePrefix + "AAAA")
+	f.Fuzz(func(t *testing.T, text string) {
+		c, err := parseSimCode(text)
+		if err != nil {
+			return
+		}
+		if len(c.RuleParams) != 0 && len(c.RuleParams) != len(ruleFamilyParams(c.Rule)) {
+			t.Fatalf("accepted %d parameters for %s", len(c.RuleParams), c.Rule)
+		}
+		if c.PaletteMode < 0 || c.PaletteMode >= len(paletteModeNames) || c.Ants > maxAnts || c.CatastropheMax < c.CatastropheMin {
+			t.Fatalf("accepted out-of-range values: %+v", c)
+		}
+		if !slices.Contains(largeGridSizes, c.GridCells) {
+			t.Fatalf("accepted grid size %d", c.GridCells)
+		}
+		again, err := parseSimCode(c.String())
+		if err != nil || again.Seed != c.Seed || again.GridCells != c.GridCells || again.CellSize != c.CellSize {
+			t.Fatalf("round trip of %+v gave %+v, %v", c, again, err)
+		}
+		state := &SimulationState{runModel: runModel{ruleParams: defaultRuleParams()}}
+		c.applyHidden(state)
+	})
+}
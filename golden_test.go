@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Golden runs pin the dynamics of the rules: each runs goldenGenerations
+// generations from a fixed seed, and the hash of its grid at every
+// generation is compared with the one in testdata/golden.txt. A refactor
+// that changes a single cell of a single generation changes the hash, even
+// in a run that dies out. When a change of the dynamics is
+// intended, `go test -run Golden -update` rewrites the file, to be reviewed
+// and committed with the change.
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata/golden.txt from the current engine")
+
+const (
+	goldenFile        = "testdata/golden.txt"
+	goldenGridSize    = 60
+	goldenGenerations = 200
+)
+
+// goldenRun is a simulation configured by setup before it is seeded.
+type goldenRun struct {
+	name  string
+	seed  int64
+	setup func(*Simulation)
+}
+
+func goldenRuns() []goldenRun {
+	runs := []goldenRun{
+		{"living-numbers", 1, func(*Simulation) {}},
+		{"living-numbers-seed-2", 2, func(*Simulation) {}},
+		{"temperature", 1, func(s *Simulation) { s.temperature = 1 }},
+		{"rule-drift", 1, func(s *Simulation) { s.setRuleDrift(true) }},
+		{"symmetry", 1, func(s *Simulation) { s.symmetry = SymmetryMirror4 }},
+		{"metabolism-nutrients", 1, func(s *Simulation) {
+			s.setMetabolism(true)
+			s.setNutrients(true)
+		}},
+		{"zones-ants", 1, func(s *Simulation) {
+			s.setZones(1)
+			s.setAnts(3)
+		}},
+		{"migration-immigration", 1, func(s *Simulation) {
+			s.movementRate = 0.2
+			s.immigration = 0.05
+		}},
+		{"hashlife-jump-6", 1, func(s *Simulation) {
+			s.rule = newRuleFamily("Conway's Life (HashLife)")
+			tuneRule(s.rule, []float64{6})
+		}},
+	}
+	for _, f := range ruleFamilies[1:] {
+		if _, plugin := f.newRule().(*pluginRule); plugin {
+			continue // tested in their own packages, e.g. plugins/highlife
+		}
+		name := f.name
+		runs = append(runs, goldenRun{name, 1, func(s *Simulation) { s.rule = newRuleFamily(name) }})
+	}
+	return runs
+}
+
+// goldenHash runs r and returns the hash of its successive grids.
+func goldenHash(r goldenRun) string {
+	sim := newSimulation(goldenGridSize, r.seed)
+	sim.growthRate, sim.mutationChance = 0.2, 0.01
+	r.setup(sim)
+	sim.reset(r.seed)
+	h := sha256.New()
+	h.Write(sim.grid.cells)
+	for range goldenGenerations {
+		sim.step()
+		h.Write(sim.grid.cells)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)[:8])
+}
+
+func readGolden(t *testing.T) map[string]string {
+	t.Helper()
+	f, err := os.Open(goldenFile)
+	if err != nil {
+		t.Fatalf("reading golden hashes: %v (run with -update to create them)", err)
+	}
+	defer f.Close()
+	golden := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		name, hash, ok := strings.Cut(sc.Text(), "\t")
+		if ok && !strings.HasPrefix(name, "#") {
+			golden[name] = hash
+		}
+	}
+	return golden
+}
+
+func TestGoldenRuns(t *testing.T) {
+	runs := goldenRuns()
+	if *updateGolden {
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %d generations on a %dx%d grid; regenerate with go test -run Golden -update\n", goldenGenerations, goldenGridSize, goldenGridSize)
+		for _, r := range runs {
+			fmt.Fprintf(&b, "%s\t%s\n", r.name, goldenHash(r))
+		}
+		if err := os.MkdirAll(filepath.Dir(goldenFile), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenFile, []byte(b.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	golden := readGolden(t)
+	for _, r := range runs {
+		t.Run(r.name, func(t *testing.T) {
+			want, ok := golden[r.name]
+			if !ok {
+				t.Fatalf("no golden hash (run with -update to add it)")
+			}
+			if got := goldenHash(r); got != want {
+				t.Errorf("hash of %d generations = %s, want %s", goldenGenerations, got, want)
+			}
+		})
+	}
+}
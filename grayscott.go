@@ -0,0 +1,134 @@
+package main
+
+import "image"
+
+// Gray-Scott diffusion rates and the integration steps run per generation,
+// so patterns form at a watchable pace.
+const (
+	grayScottDiffU    = 1.0
+	grayScottDiffV    = 0.5
+	grayScottSubsteps = 8
+	// grayScottShown is the concentration of V drawn as the oldest color.
+	grayScottShown = 0.4
+)
+
+// grayScott is the Gray-Scott reaction-diffusion model on a wrap-around
+// grid: chemical U is fed in at the feed rate, V is removed at the feed plus
+// kill rate, and U + 2V -> 3V where they meet. Feed and kill pick among
+// spots, stripes, mazes and dividing cells. The grid shows V.
+type grayScott struct {
+	size       int
+	u, v       []float64 // row-major concentrations
+	nu, nv     []float64
+	shown      []float64 // v over grayScottShown, clamped to 1
+	feed, kill float64
+}
+
+func (*grayScott) Name() string { return "Gray-Scott" }
+
+func (g *grayScott) tune(name string, value float64) {
+	switch name {
+	case "Feed":
+		g.feed = value
+	case "Kill":
+		g.kill = value
+	}
+}
+
+func (g *grayScott) resize(n int) {
+	if g.u == nil || g.size != n {
+		g.size = n
+		g.u, g.v = make([]float64, n*n), make([]float64, n*n)
+		g.nu, g.nv = make([]float64, n*n), make([]float64, n*n)
+		g.shown = make([]float64, n*n)
+	}
+	for i := range g.u {
+		g.u[i], g.v[i] = 1, 0
+	}
+}
+
+// seed drops a few square patches of V, mirrored by the symmetry, with a
+// little noise so they do not grow as squares.
+func (g *grayScott) seed(sim *Simulation) {
+	n := sim.gridSize
+	g.resize(n)
+	side := max(n/10, 2)
+	var pts []image.Point
+	for range 3 + sim.rng.Intn(5) {
+		x0, y0 := sim.rng.Intn(n), sim.rng.Intn(n)
+		for dy := 0; dy < side; dy++ {
+			for dx := 0; dx < side; dx++ {
+				v := 0.25 + 0.02*(sim.rng.Float64()-0.5)
+				pts = sim.symmetry.orbit(pts[:0], (x0+dx)%n, (y0+dy)%n, n)
+				for _, p := range pts {
+					g.u[p.Y*n+p.X], g.v[p.Y*n+p.X] = 0.5, v
+				}
+			}
+		}
+	}
+	g.publish(sim)
+}
+
+func (g *grayScott) load(sim *Simulation) {
+	g.resize(sim.gridSize)
+	for i, c := range sim.grid.cells {
+		if c > 0 {
+			v := float64(c) / maxCellAge * grayScottShown
+			g.u[i], g.v[i] = 1-2*v, v
+		}
+	}
+}
+
+func (g *grayScott) step(sim *Simulation) (births int) {
+	n := g.size
+	for range grayScottSubsteps {
+		for y := 0; y < n; y++ {
+			up, down := ((y+n-1)%n)*n, ((y+1)%n)*n
+			row := y * n
+			for x := 0; x < n; x++ {
+				left, right := (x+n-1)%n, (x+1)%n
+				lap := func(c []float64) float64 {
+					return 0.2*(c[up+x]+c[down+x]+c[row+left]+c[row+right]) +
+						0.05*(c[up+left]+c[up+right]+c[down+left]+c[down+right]) - c[row+x]
+				}
+				i := row + x
+				u, v := g.u[i], g.v[i]
+				uvv := u * v * v
+				g.nu[i] = min(max(u+grayScottDiffU*lap(g.u)-uvv+g.feed*(1-u), 0), 1)
+				g.nv[i] = min(max(v+grayScottDiffV*lap(g.v)+uvv-(g.feed+g.kill)*v, 0), 1)
+			}
+		}
+		g.u, g.nu = g.nu, g.u
+		g.v, g.nv = g.nv, g.v
+	}
+	return g.publish(sim)
+}
+
+// set turns a painted cell into a drop of V, and a cleared one back into
+// plain U.
+func (g *grayScott) set(x, y, val int) {
+	i := y*g.size + x
+	if val > 0 {
+		g.u[i], g.v[i] = 0.5, 0.25
+	} else {
+		g.u[i], g.v[i] = 1, 0
+	}
+}
+
+// publish writes V to sim.grid, grayScottShown being maxCellAge, and
+// returns how many dead cells came alive.
+func (g *grayScott) publish(sim *Simulation) (births int) {
+	for i, c := range sim.grid.cells {
+		g.shown[i] = min(g.v[i]/grayScottShown, 1)
+		val := uint8(g.shown[i] * maxCellAge)
+		if c == 0 && val > 0 {
+			births++
+		}
+		sim.grid.cells[i] = val
+	}
+	return births
+}
+
+func (g *grayScott) draw(img *image.RGBA, palette ColorPalette, cellSize int) {
+	drawLevels(img, g.shown, g.size, palette, cellSize)
+}
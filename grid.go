@@ -0,0 +1,33 @@
+package main
+
+// Grid is a square grid of cell ages, stored row-major in a single byte
+// slice: ages never exceed maxCellAge, the rows of a neighbourhood sit next
+// to each other in memory, and whole grids copy and compare at once.
+type Grid struct {
+	size  int
+	cells []uint8
+}
+
+func newGrid(size int) *Grid {
+	return &Grid{size: size, cells: make([]uint8, size*size)}
+}
+
+// at returns the age of the cell at (x, y).
+func (g *Grid) at(x, y int) int {
+	return int(g.cells[y*g.size+x])
+}
+
+// set stores the age of the cell at (x, y).
+func (g *Grid) set(x, y, val int) {
+	g.cells[y*g.size+x] = uint8(val)
+}
+
+// row returns row y, sharing the storage of g.
+func (g *Grid) row(y int) []uint8 {
+	return g.cells[y*g.size : (y+1)*g.size]
+}
+
+// clear empties every cell.
+func (g *Grid) clear() {
+	clear(g.cells)
+}
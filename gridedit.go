@@ -0,0 +1,106 @@
+package main
+
+// layerEdit is the value of each changed cell of a layer, row-major, before
+// and after an edit.
+type layerEdit[T comparable] map[int][2]T
+
+// note records that cell i went from old to val, keeping the value it had
+// before the edit when it changes again.
+func (e layerEdit[T]) note(i int, old, val T) {
+	if c, ok := e[i]; ok {
+		old = c[0]
+	}
+	e[i] = [2]T{old, val}
+}
+
+// apply puts back the values before the edit, or after it, into a layer of
+// n cells, allocating a nil layer once a value other than the zero one is
+// needed.
+func (e layerEdit[T]) apply(layer *[]T, n int, after bool) {
+	var zero T
+	for i, c := range e {
+		v := c[0]
+		if after {
+			v = c[1]
+		}
+		if *layer == nil {
+			if v == zero {
+				continue
+			}
+			*layer = make([]T, n)
+		}
+		(*layer)[i] = v
+	}
+}
+
+// gridEdit is what a user action changed on the grid: cells, fixtures,
+// zones, radiation and infections. It is recorded by the Simulation
+// between beginEdit and endEdit, and undone or redone without touching the
+// cells it did not change, so it can be undone while the run goes on.
+type gridEdit struct {
+	size      int
+	cells     layerEdit[uint8]
+	fixtures  layerEdit[fixture]
+	zones     layerEdit[uint8]
+	radiation layerEdit[float64]
+	infection layerEdit[int]
+}
+
+func newGridEdit(size int) *gridEdit {
+	return &gridEdit{
+		size:      size,
+		cells:     layerEdit[uint8]{},
+		fixtures:  layerEdit[fixture]{},
+		zones:     layerEdit[uint8]{},
+		radiation: layerEdit[float64]{},
+		infection: layerEdit[int]{},
+	}
+}
+
+func (e *gridEdit) empty() bool {
+	return len(e.cells)+len(e.fixtures)+len(e.zones)+len(e.radiation)+len(e.infection) == 0
+}
+
+// beginEdit records the changes the user makes from now on into e, until
+// endEdit.
+func (s *Simulation) beginEdit(e *gridEdit) {
+	s.edit = e
+}
+
+func (s *Simulation) endEdit() {
+	s.edit = nil
+}
+
+// noteCells records into the edit in progress the cells of the grid that
+// differ from before, a copy of the grid's cells, for changes made
+// wholesale like loading a grid.
+func (s *Simulation) noteCells(before []uint8) {
+	if s.edit == nil {
+		return
+	}
+	for i, c := range s.grid.cells {
+		if c != before[i] {
+			s.edit.cells.note(i, before[i], c)
+		}
+	}
+}
+
+// applyEdit undoes e, or redoes it when after is set. It does nothing to a
+// grid of another size.
+func (s *Simulation) applyEdit(e *gridEdit, after bool) {
+	n := s.gridSize
+	if e.size != n {
+		return
+	}
+	for i, c := range e.cells {
+		v := c[0]
+		if after {
+			v = c[1]
+		}
+		s.putCell(i%n, i/n, int(v))
+	}
+	e.fixtures.apply(&s.fixtures, n*n, after)
+	e.zones.apply(&s.zones, n*n, after)
+	e.radiation.apply(&s.radiation, n*n, after)
+	e.infection.apply(&s.infection, n*n, after)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// asciiBands are the characters of the ASCII export, one per palette band:
+// dead, young, mature and old.
+var asciiBands = [...]byte{'.', 'o', 'O', '@'}
+
+// gridValues copies the values of grid, so it can be exported after
+// state.mu is released.
+func gridValues(grid *Grid) [][]int {
+	values := make([][]int, grid.size)
+	for y := range values {
+		values[y] = make([]int, grid.size)
+		for x, c := range grid.row(y) {
+			values[y][x] = int(c)
+		}
+	}
+	return values
+}
+
+// writeGridCSV writes the value of every cell, one record per grid row.
+func writeGridCSV(w io.Writer, values [][]int) error {
+	out := csv.NewWriter(w)
+	record := make([]string, 0, len(values))
+	for _, row := range values {
+		record = record[:0]
+		for _, v := range row {
+			record = append(record, strconv.Itoa(v))
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// writeGridASCII draws the grid with one character per cell, see asciiBands.
+func writeGridASCII(w io.Writer, values [][]int) error {
+	out := bufio.NewWriter(w)
+	for _, row := range values {
+		for _, v := range row {
+			out.WriteByte(asciiBands[cellBand(v)])
+		}
+		out.WriteByte('\n')
+	}
+	return out.Flush()
+}
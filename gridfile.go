@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// gridFileKind tells the format of a grid file dropped on the window by its
+// extension, or failing that by its content: "checkpoint", "image", "rle",
+// "cells" or "csv", empty when unknown.
+func gridFileKind(name string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".gz":
+		return "checkpoint"
+	case ".png", ".jpg", ".jpeg":
+		return "image"
+	case ".rle":
+		return "rle"
+	case ".cells":
+		return "cells"
+	case ".csv":
+		return "csv"
+	}
+	text := string(bytes.TrimSpace(data[:min(len(data), 512)]))
+	switch {
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		return "checkpoint"
+	case bytes.HasPrefix(data, []byte("\x89PNG")), bytes.HasPrefix(data, []byte{0xff, 0xd8}):
+		return "image"
+	case strings.HasPrefix(text, "#"), strings.HasPrefix(text, "x"):
+		return "rle"
+	case strings.HasPrefix(text, "!"), strings.HasPrefix(text, "."), strings.HasPrefix(text, "O"):
+		return "cells"
+	case strings.HasPrefix(text, "-") || len(text) > 0 && text[0] >= '0' && text[0] <= '9':
+		return "csv"
+	}
+	return ""
+}
+
+// decodeGridFile reads the grid values of a saved state (a checkpoint, or a
+// CSV from 📄 Grid CSV), a Life pattern or an image, scaled to a size × size
+// grid by its brightness.
+func decodeGridFile(name string, data []byte, size int) ([][]int, error) {
+	switch gridFileKind(name, data) {
+	case "checkpoint":
+		cp, err := readCheckpoint(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		values := make([][]int, cp.GridSize)
+		for y := range values {
+			values[y] = make([]int, cp.GridSize)
+			for x := range values[y] {
+				values[y][x] = int(cp.Cells[y*cp.GridSize+x])
+			}
+		}
+		return values, nil
+	case "image":
+		return loadImageGrid(bytes.NewReader(data), size, "Brightness", false)
+	case "rle":
+		return parseRLE(string(data))
+	case "cells":
+		return parseCells(string(data))
+	case "csv":
+		return parseGridCSV(data)
+	}
+	return nil, errors.New("unknown file type: expected a checkpoint, RLE or .cells pattern, CSV grid or PNG/JPEG image")
+}
+
+// parsePattern reads Life pattern text, as exchanged on the clipboard: RLE,
+// or else plaintext .cells.
+func parsePattern(text string) ([][]int, error) {
+	text = strings.TrimSpace(text)
+	if gridFileKind("", []byte(text)) == "rle" {
+		return parseRLE(text)
+	}
+	return parseCells(text)
+}
+
+// parseCells reads a plaintext .cells pattern: ! starts a comment line, .
+// is an empty cell and O or * a live one, a newborn of age 1.
+func parseCells(text string) ([][]int, error) {
+	var values [][]int
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r \t")
+		if strings.HasPrefix(line, "!") {
+			continue
+		}
+		row := make([]int, 0, len(line))
+		for _, r := range line {
+			switch r {
+			case '.':
+				row = append(row, 0)
+			case 'O', 'o', '*':
+				row = append(row, 1)
+			default:
+				return nil, fmt.Errorf("invalid pattern character %q", r)
+			}
+		}
+		values = append(values, row)
+	}
+	for len(values) > 0 && len(values[len(values)-1]) == 0 {
+		values = values[:len(values)-1] // trailing blank lines
+	}
+	if len(values) == 0 {
+		return nil, errors.New("empty pattern")
+	}
+	return values, nil
+}
+
+// parseGridCSV reads the grids written by writeGridCSV.
+func parseGridCSV(data []byte) ([][]int, error) {
+	in := csv.NewReader(bytes.NewReader(data))
+	in.FieldsPerRecord = -1
+	records, err := in.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]int, len(records))
+	for y, record := range records {
+		values[y] = make([]int, len(record))
+		for x, field := range record {
+			if values[y][x], err = strconv.Atoi(strings.TrimSpace(field)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return values, nil
+}
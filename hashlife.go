@@ -0,0 +1,325 @@
+package main
+
+// HashLife (Gosper's algorithm) for Conway's Life: the plane is a quadtree
+// of interned nodes, so a square that appears twice, anywhere and at any
+// time, is a single node, and its future is computed once. Advancing a
+// node of 2^level cells by up to 2^(level-2) generations is memoized, so
+// sparse and repetitive patterns jump thousands of generations at the cost
+// of a few.
+
+// hlMaxNodes bounds the node table; past it the universe is rebuilt with
+// only the nodes of the current pattern, dropping the memoized results.
+const hlMaxNodes = 1 << 20
+
+// hlNode is a square of 2^level cells: a single cell at level 0, four
+// quadrants above.
+type hlNode struct {
+	nw, ne, sw, se *hlNode
+	level          int
+	pop            int
+}
+
+// lifeUniverse is an unbounded Life plane whose root node is centered on
+// the origin, so it covers [-2^(level-1), 2^(level-1)) on both axes.
+type lifeUniverse struct {
+	nodes   map[[4]*hlNode]*hlNode
+	results []map[*hlNode]*hlNode // by log2 of the generations advanced
+	empties []*hlNode             // the empty node of each level
+	alive   *hlNode
+	root    *hlNode
+}
+
+func newLifeUniverse() *lifeUniverse {
+	u := &lifeUniverse{
+		nodes:   make(map[[4]*hlNode]*hlNode),
+		empties: []*hlNode{{}},
+		alive:   &hlNode{pop: 1},
+	}
+	u.root = u.empty(3)
+	return u
+}
+
+// join returns the node made of four quadrants of the same level.
+func (u *lifeUniverse) join(nw, ne, sw, se *hlNode) *hlNode {
+	key := [4]*hlNode{nw, ne, sw, se}
+	if n, ok := u.nodes[key]; ok {
+		return n
+	}
+	n := &hlNode{nw: nw, ne: ne, sw: sw, se: se, level: nw.level + 1, pop: nw.pop + ne.pop + sw.pop + se.pop}
+	u.nodes[key] = n
+	return n
+}
+
+func (u *lifeUniverse) empty(level int) *hlNode {
+	for len(u.empties) <= level {
+		e := u.empties[len(u.empties)-1]
+		u.empties = append(u.empties, u.join(e, e, e, e))
+	}
+	return u.empties[level]
+}
+
+// center returns the middle half of n, a level lower.
+func (u *lifeUniverse) center(n *hlNode) *hlNode {
+	return u.join(n.nw.se, n.ne.sw, n.sw.ne, n.se.nw)
+}
+
+// expand doubles the root around its center.
+func (u *lifeUniverse) expand() {
+	r, e := u.root, u.empty(u.root.level-1)
+	u.root = u.join(
+		u.join(e, e, e, r.nw),
+		u.join(e, e, r.ne, e),
+		u.join(e, r.sw, e, e),
+		u.join(r.se, e, e, e),
+	)
+}
+
+// half is the distance from the center of the root to its edges.
+func (u *lifeUniverse) half() int {
+	return 1 << (u.root.level - 1)
+}
+
+// set makes the cell at (x, y) alive or dead, growing the root to reach it.
+func (u *lifeUniverse) set(x, y int, alive bool) {
+	for x < -u.half() || x >= u.half() || y < -u.half() || y >= u.half() {
+		u.expand()
+	}
+	u.root = u.setIn(u.root, x+u.half(), y+u.half(), alive)
+}
+
+// setIn returns n with the cell at (x, y), relative to its corner, changed.
+func (u *lifeUniverse) setIn(n *hlNode, x, y int, alive bool) *hlNode {
+	if n.level == 0 {
+		if alive {
+			return u.alive
+		}
+		return u.empty(0)
+	}
+	h := 1 << (n.level - 1)
+	nw, ne, sw, se := n.nw, n.ne, n.sw, n.se
+	switch {
+	case x < h && y < h:
+		nw = u.setIn(nw, x, y, alive)
+	case y < h:
+		ne = u.setIn(ne, x-h, y, alive)
+	case x < h:
+		sw = u.setIn(sw, x, y-h, alive)
+	default:
+		se = u.setIn(se, x-h, y-h, alive)
+	}
+	return u.join(nw, ne, sw, se)
+}
+
+// each calls f for every living cell inside [x0, x1) × [y0, y1).
+func (u *lifeUniverse) each(x0, y0, x1, y1 int, f func(x, y int)) {
+	var walk func(n *hlNode, x, y int)
+	walk = func(n *hlNode, x, y int) {
+		size := 1 << n.level
+		if n.pop == 0 || x >= x1 || y >= y1 || x+size <= x0 || y+size <= y0 {
+			return
+		}
+		if n.level == 0 {
+			f(x, y)
+			return
+		}
+		h := size / 2
+		walk(n.nw, x, y)
+		walk(n.ne, x+h, y)
+		walk(n.sw, x, y+h)
+		walk(n.se, x+h, y+h)
+	}
+	walk(u.root, -u.half(), -u.half())
+}
+
+// life4 advances the middle 2×2 cells of a 4×4 node by one generation.
+func (u *lifeUniverse) life4(n *hlNode) *hlNode {
+	var cells [4][4]int
+	for y := range 4 {
+		for x := range 4 {
+			q := n.nw
+			switch {
+			case x >= 2 && y >= 2:
+				q = n.se
+			case y >= 2:
+				q = n.sw
+			case x >= 2:
+				q = n.ne
+			}
+			leaf := q.nw
+			switch {
+			case x%2 == 1 && y%2 == 1:
+				leaf = q.se
+			case y%2 == 1:
+				leaf = q.sw
+			case x%2 == 1:
+				leaf = q.ne
+			}
+			cells[y][x] = leaf.pop
+		}
+	}
+	next := func(x, y int) *hlNode {
+		sum := 0
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				sum += cells[y+dy][x+dx]
+			}
+		}
+		sum -= cells[y][x]
+		if sum == 3 || sum == 2 && cells[y][x] == 1 {
+			return u.alive
+		}
+		return u.empty(0)
+	}
+	return u.join(next(1, 1), next(2, 1), next(1, 2), next(2, 2))
+}
+
+// next returns the middle half of n advanced by 2^step generations, step
+// being at most n.level-2.
+func (u *lifeUniverse) next(n *hlNode, step int) *hlNode {
+	if n.pop == 0 {
+		return u.empty(n.level - 1)
+	}
+	if n.level == 2 {
+		return u.life4(n)
+	}
+	for len(u.results) <= step {
+		u.results = append(u.results, make(map[*hlNode]*hlNode))
+	}
+	if r, ok := u.results[step][n]; ok {
+		return r
+	}
+
+	// The nine overlapping squares of half the size
+	n00, n02, n20, n22 := n.nw, n.ne, n.sw, n.se
+	n01 := u.join(n.nw.ne, n.ne.nw, n.nw.se, n.ne.sw)
+	n10 := u.join(n.nw.sw, n.nw.se, n.sw.nw, n.sw.ne)
+	n11 := u.center(n)
+	n12 := u.join(n.ne.sw, n.ne.se, n.se.nw, n.se.ne)
+	n21 := u.join(n.sw.ne, n.se.nw, n.sw.se, n.se.sw)
+	nine := [9]*hlNode{n00, n01, n02, n10, n11, n12, n20, n21, n22}
+
+	// At full speed each square advances half the way before they are
+	// combined into four and advance the rest; slower steps only advance
+	// the four.
+	inner := step
+	for i, m := range nine {
+		if step == n.level-2 {
+			inner = step - 1
+			nine[i] = u.next(m, inner)
+		} else {
+			nine[i] = u.center(m)
+		}
+	}
+	r := u.join(
+		u.next(u.join(nine[0], nine[1], nine[3], nine[4]), inner),
+		u.next(u.join(nine[1], nine[2], nine[4], nine[5]), inner),
+		u.next(u.join(nine[3], nine[4], nine[6], nine[7]), inner),
+		u.next(u.join(nine[4], nine[5], nine[7], nine[8]), inner),
+	)
+	u.results[step][n] = r
+	return r
+}
+
+// advance runs the universe for generations, one jump per bit.
+func (u *lifeUniverse) advance(generations int) {
+	for step := 0; generations>>step > 0; step++ {
+		if generations>>step&1 == 0 {
+			continue
+		}
+		// Pad the pattern so nothing can reach the part of the root the
+		// jump drops
+		for u.root.level < step+3 || u.center(u.center(u.root)).pop != u.root.pop {
+			u.expand()
+		}
+		u.root = u.next(u.root, step)
+	}
+	if len(u.nodes) > hlMaxNodes {
+		u.compact()
+	}
+}
+
+// compact rebuilds the tables with only the nodes of the current root.
+func (u *lifeUniverse) compact() {
+	old := u.root
+	u.nodes = make(map[[4]*hlNode]*hlNode)
+	u.results = nil
+	u.empties = u.empties[:1]
+	copied := make(map[*hlNode]*hlNode)
+	var rebuild func(n *hlNode) *hlNode
+	rebuild = func(n *hlNode) *hlNode {
+		if n.level == 0 {
+			return n
+		}
+		if c, ok := copied[n]; ok {
+			return c
+		}
+		c := u.join(rebuild(n.nw), rebuild(n.ne), rebuild(n.sw), rebuild(n.se))
+		copied[n] = c
+		return c
+	}
+	u.root = rebuild(old)
+}
+
+// hashLife is Conway's Life run by a lifeUniverse, advancing 2^jump
+// generations per step. Unlike conwayLife the plane is unbounded: the grid
+// is a window onto its middle, and patterns leaving it keep evolving.
+type hashLife struct {
+	universe *lifeUniverse
+	size     int
+	jump     int
+}
+
+func (h *hashLife) Name() string { return "Conway's Life (HashLife)" }
+
+func (h *hashLife) tune(name string, value float64) {
+	if name == "Jump (2^n generations)" {
+		h.jump = int(value)
+	}
+}
+
+// stride is the number of generations of a step.
+func (h *hashLife) stride() int { return 1 << h.jump }
+
+func (h *hashLife) seed(sim *Simulation) {
+	conwayLife{}.seed(sim)
+	h.load(sim)
+}
+
+// load puts the living cells of sim.grid on an empty plane, the grid
+// centered on the origin.
+func (h *hashLife) load(sim *Simulation) {
+	h.universe = newLifeUniverse()
+	h.size = sim.gridSize
+	for y := range h.size {
+		for x, c := range sim.grid.row(y) {
+			if c > 0 {
+				h.set(x, y, int(c))
+			}
+		}
+	}
+}
+
+// step advances the plane and mirrors the window into sim.grid, a
+// survivor aging by the generations jumped.
+func (h *hashLife) step(sim *Simulation) (births int) {
+	h.universe.advance(h.stride())
+	sim.next.clear()
+	o := h.size / 2
+	h.universe.each(-o, -o, h.size-o, h.size-o, func(x, y int) {
+		x, y = x+o, y+o
+		age := 1
+		if prev := sim.grid.at(x, y); prev > 0 {
+			age = min(prev+h.stride(), maxCellAge)
+		} else {
+			births++
+		}
+		sim.next.set(x, y, age)
+	})
+	sim.grid, sim.next = sim.next, sim.grid
+	return births
+}
+
+func (h *hashLife) set(x, y, val int) {
+	o := h.size / 2
+	h.universe.set(x-o, y-o, val > 0)
+}
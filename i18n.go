@@ -0,0 +1,52 @@
+package main
+
+import (
+	"embed"
+	"log/slog"
+
+	"fyne.io/fyne/v2/lang"
+)
+
+// translations holds one JSON catalog per language, keyed by the English
+// text. Fyne picks the catalog matching the system locale and falls back to
+// the English key when a string is missing. Messages with values are whole
+// templates under an ID, translated with lang.LocalizeKey, so translators
+// can move the values around.
+//
+//go:embed translations
+var translations embed.FS
+
+// paletteNames are the palette options in display order, and
+// paletteModeNames maps state.paletteMode to its option.
+var (
+	paletteNames     = []string{"Original", "Rainbow", "Ocean", "Fire", "Image", "👁 Deuteranopia", "👁 Protanopia", "👁 Tritanopia", "👁 Monochrome + patterns"}
+	paletteModeNames = []string{"Rainbow", "Ocean", "Fire", "Original", "Image", "👁 Deuteranopia", "👁 Protanopia", "👁 Tritanopia", "👁 Monochrome + patterns"}
+)
+
+// loadTranslations registers the catalogs; it must run before the first
+// lang.L call.
+func loadTranslations() {
+	if err := lang.AddTranslationsFS(translations, "translations"); err != nil {
+		slog.Warn("cannot load translations", "err", err)
+	}
+}
+
+// localized translates option names for a Select.
+func localized(names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = lang.L(n)
+	}
+	return out
+}
+
+// unlocalized maps a translated option back to its name in names, so the
+// settings and saved files keep the English names.
+func unlocalized(names []string, shown string) string {
+	for _, n := range names {
+		if lang.L(n) == shown {
+			return n
+		}
+	}
+	return shown
+}
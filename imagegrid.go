@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// imageChannels are the image values an imported grid can be read from.
+var imageChannels = []string{"Brightness", "Red", "Green", "Blue", "Alpha"}
+
+// imageToGrid scales img down to a size × size grid and maps the chosen
+// channel of each cell, averaged over its pixels, to an age: full
+// intensity is the oldest age and cells darker than age 1 stay empty.
+// invert reads dark as old, for dark drawings on a light background.
+func imageToGrid(img image.Image, size int, channel string, invert bool) [][]int {
+	b := img.Bounds()
+	level := func(x, y int) float64 {
+		r, g, bl, a := img.At(x, y).RGBA()
+		var v uint32
+		switch channel {
+		case "Red":
+			v = r
+		case "Green":
+			v = g
+		case "Blue":
+			v = bl
+		case "Alpha":
+			v = a
+		default:
+			v = (299*r + 587*g + 114*bl) / 1000
+		}
+		return float64(v) / 0xffff
+	}
+
+	values := make([][]int, size)
+	for gy := range values {
+		values[gy] = make([]int, size)
+		// The pixels under the cell, at least one for images smaller than the grid
+		y0 := b.Min.Y + gy*b.Dy()/size
+		y1 := max(b.Min.Y+(gy+1)*b.Dy()/size, y0+1)
+		for gx := range values[gy] {
+			x0 := b.Min.X + gx*b.Dx()/size
+			x1 := max(b.Min.X+(gx+1)*b.Dx()/size, x0+1)
+			sum := 0.0
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					sum += level(x, y)
+				}
+			}
+			mean := sum / float64((y1-y0)*(x1-x0))
+			if invert {
+				mean = 1 - mean
+			}
+			values[gy][gx] = int(mean * maxCellAge)
+		}
+	}
+	return values
+}
+
+// maxImagePixels bounds the images read. Their size is checked from the
+// header before decoding, so a file claiming huge dimensions cannot exhaust
+// memory.
+const maxImagePixels = 1 << 26
+
+// decodeImage decodes a PNG or JPEG image of at most maxImagePixels.
+func decodeImage(r io.Reader) (image.Image, error) {
+	var head bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &head))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, fmt.Errorf("unsupported image size %d×%d", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(io.MultiReader(&head, r))
+	return img, err
+}
+
+// loadImageGrid decodes a PNG or JPEG image into grid values.
+func loadImageGrid(r io.Reader, size int, channel string, invert bool) ([][]int, error) {
+	img, err := decodeImage(r)
+	if err != nil {
+		return nil, err
+	}
+	return imageToGrid(img, size, channel, invert), nil
+}
+
+// showImageGridDialog asks how to read an image, then for a PNG or JPEG
+// file, and calls onLoaded with its grid values.
+func showImageGridDialog(w fyne.Window, size int, onLoaded func(values [][]int)) {
+	channelSelect := widget.NewSelect(localized(imageChannels), nil)
+	channelSelect.SetSelected(lang.L("Brightness"))
+	invertCheck := widget.NewCheck(lang.L("Dark is old"), nil)
+	form := container.NewVBox(
+		widget.NewLabel(lang.L("Each cell takes an age from the image under it:\nthe brighter, the older. The next run starts from it.")),
+		container.NewBorder(nil, nil, widget.NewLabel(lang.L("Channel")), nil, channelSelect),
+		invertCheck,
+	)
+	dialog.ShowCustomConfirm(lang.L("🖼 Image as grid"), lang.L("Choose image…"), lang.L("Cancel"), form, func(ok bool) {
+		if !ok {
+			return
+		}
+		channel := unlocalized(imageChannels, channelSelect.Selected)
+		d := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if rc == nil {
+				return
+			}
+			defer rc.Close()
+			values, err := loadImageGrid(rc, size, channel, invertCheck.Checked)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			onLoaded(values)
+		}, w)
+		d.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg"}))
+		d.Show()
+	}, w)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"io"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+// imagePaletteMode is the palette mode built from an imported image.
+const imagePaletteMode = 4
+
+// imagePalette holds the young, mature and old base colors of the Image
+// palette, nil until an image is imported. Palettes are generated on several
+// goroutines, hence the atomic pointer.
+var imagePalette atomic.Pointer[[3]color.RGBA]
+
+// Color extraction settings: the image is sampled on a grid of at most
+// paletteSamples² pixels, and the samples grouped into paletteClusters
+// colors of which the three largest are kept.
+const (
+	paletteSamples  = 64
+	paletteClusters = 6
+	paletteRounds   = 10
+)
+
+// dominantColors returns the three most common colors of img by k-means
+// clustering, from the lightest to the darkest: the young, mature and old
+// base colors.
+func dominantColors(img image.Image) [3]color.RGBA {
+	b := img.Bounds()
+	var samples [][3]float64
+	for sy := 0; sy < min(paletteSamples, b.Dy()); sy++ {
+		for sx := 0; sx < min(paletteSamples, b.Dx()); sx++ {
+			x := b.Min.X + sx*b.Dx()/min(paletteSamples, b.Dx())
+			y := b.Min.Y + sy*b.Dy()/min(paletteSamples, b.Dy())
+			r, g, bl, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue // transparent background
+			}
+			samples = append(samples, [3]float64{float64(r >> 8), float64(g >> 8), float64(bl >> 8)})
+		}
+	}
+	if len(samples) == 0 {
+		return [3]color.RGBA{{255, 255, 255, 255}, {128, 128, 128, 255}, {40, 40, 40, 255}}
+	}
+
+	// Seeds spread over the samples sorted by luma, so runs are repeatable
+	luma := func(c [3]float64) float64 { return 0.299*c[0] + 0.587*c[1] + 0.114*c[2] }
+	sorted := slices.Clone(samples)
+	slices.SortFunc(sorted, func(a, b [3]float64) int { return cmp.Compare(luma(a), luma(b)) })
+	centers := make([][3]float64, paletteClusters)
+	for i := range centers {
+		centers[i] = sorted[(2*i+1)*len(sorted)/(2*paletteClusters)]
+	}
+	counts := make([]int, paletteClusters)
+	for range paletteRounds {
+		var sums [paletteClusters][3]float64
+		clear(counts)
+		for _, s := range samples {
+			best, bestDist := 0, -1.0
+			for i, c := range centers {
+				d := (s[0]-c[0])*(s[0]-c[0]) + (s[1]-c[1])*(s[1]-c[1]) + (s[2]-c[2])*(s[2]-c[2])
+				if bestDist < 0 || d < bestDist {
+					best, bestDist = i, d
+				}
+			}
+			counts[best]++
+			for k := range 3 {
+				sums[best][k] += s[k]
+			}
+		}
+		for i := range centers {
+			if counts[i] > 0 {
+				for k := range 3 {
+					centers[i][k] = sums[i][k] / float64(counts[i])
+				}
+			}
+		}
+	}
+
+	order := make([]int, paletteClusters)
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(counts[b], counts[a]) })
+	top := order[:3]
+	slices.SortFunc(top, func(a, b int) int { return cmp.Compare(luma(centers[b]), luma(centers[a])) })
+	var out [3]color.RGBA
+	for i, c := range top {
+		out[i] = color.RGBA{uint8(centers[c][0]), uint8(centers[c][1]), uint8(centers[c][2]), 255}
+	}
+	return out
+}
+
+// loadImagePalette decodes a PNG or JPEG image and extracts its colors.
+func loadImagePalette(r io.Reader) ([3]color.RGBA, error) {
+	img, err := decodeImage(r)
+	if err != nil {
+		return [3]color.RGBA{}, err
+	}
+	return dominantColors(img), nil
+}
+
+// formatPaletteColors and parsePaletteColors store the imported colors as
+// "#rrggbb,#rrggbb,#rrggbb" in the preferences.
+func formatPaletteColors(bases [3]color.RGBA) string {
+	hex := make([]string, len(bases))
+	for i, c := range bases {
+		hex[i] = fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return strings.Join(hex, ",")
+}
+
+func parsePaletteColors(text string) (*[3]color.RGBA, bool) {
+	parts := strings.Split(text, ",")
+	if len(parts) != 3 {
+		return nil, false
+	}
+	var bases [3]color.RGBA
+	for i, p := range parts {
+		if _, err := fmt.Sscanf(p, "#%02x%02x%02x", &bases[i].R, &bases[i].G, &bases[i].B); err != nil {
+			return nil, false
+		}
+		bases[i].A = 255
+	}
+	return &bases, true
+}
+
+// showImportPaletteDialog asks for a PNG or JPEG image, extracts its colors
+// into the Image palette and calls onLoaded with them.
+func showImportPaletteDialog(w fyne.Window, onLoaded func(bases [3]color.RGBA)) {
+	d := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if rc == nil {
+			return
+		}
+		defer rc.Close()
+		bases, err := loadImagePalette(rc)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		imagePalette.Store(&bases)
+		onLoaded(bases)
+	}, w)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg"}))
+	d.Show()
+}
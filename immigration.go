@@ -0,0 +1,32 @@
+package main
+
+// immigrationEdges are the edges offered by the immigration selector.
+var immigrationEdges = []string{"Top", "Right", "Bottom", "Left"}
+
+// immigrate runs the immigration phase: every empty cell along the chosen
+// edge turns into a newborn with probability immigration, so a dying
+// grid is reseeded from outside and colonies flow through it. It returns the
+// number of immigrants.
+func (s *Simulation) immigrate() int {
+	n := s.gridSize
+	arrived := 0
+	for i := range n {
+		var x, y int
+		switch s.entryEdge {
+		case 0:
+			x, y = i, 0
+		case 1:
+			x, y = n-1, i
+		case 2:
+			x, y = i, n-1
+		default:
+			x, y = 0, i
+		}
+		if s.grid.at(x, y) > 0 || s.rng.Float64() >= s.immigration {
+			continue
+		}
+		s.setCell(x, y, 1)
+		arrived++
+	}
+	return arrived
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Defaults of the epidemic controls.
+const (
+	defaultInfectionRate = 0.2
+	defaultInfectionSpan = 10
+)
+
+// infectedColor marks infected cells over any palette.
+var infectedColor = color.RGBA{140, 255, 40, 255}
+
+// infect makes a random living cell patient zero of an epidemic and returns
+// it, or false when no cell is alive.
+func (s *Simulation) infect() (image.Point, bool) {
+	if s.infection == nil {
+		s.infection = make([]int, s.gridSize*s.gridSize)
+	}
+	var alive []image.Point
+	for y := range s.gridSize {
+		for x, c := range s.grid.row(y) {
+			if c > 0 && s.infection[y*s.gridSize+x] == 0 {
+				alive = append(alive, image.Pt(x, y))
+			}
+		}
+	}
+	if len(alive) == 0 {
+		return image.Point{}, false
+	}
+	p := alive[s.rng.Intn(len(alive))]
+	if s.edit != nil {
+		s.edit.infection.note(p.Y*s.gridSize+p.X, 0, 1)
+	}
+	s.infection[p.Y*s.gridSize+p.X] = 1
+	return p, true
+}
+
+// spread runs the epidemic for one generation: infections of dead cells are
+// cleared, infected cells die after infectionSpan generations, and every
+// healthy living cell catches the disease from each infected neighbour with
+// probability infectionRate. It returns the cells the disease killed and the
+// ones still infected; the epidemic ends once none are.
+func (s *Simulation) spread() (killed, infected int) {
+	n := s.gridSize
+	var caught []int
+	for y := range n {
+		row := s.grid.row(y)
+		for x, c := range row {
+			i := y*n + x
+			switch {
+			case c == 0:
+				s.infection[i] = 0
+			case s.infection[i] > 0:
+				s.infection[i]++
+				if s.infection[i] > s.infectionSpan {
+					s.change(i, 0)
+					s.infection[i] = 0
+					if s.energy != nil {
+						s.energy[i] = 0
+					}
+					killed++
+				}
+			default:
+				if k := s.infectedNeighbors(x, y); k > 0 && s.rng.Float64() < 1-math.Pow(1-s.infectionRate, float64(k)) {
+					caught = append(caught, i)
+				}
+			}
+		}
+	}
+	// New cases only become contagious next generation
+	for _, i := range caught {
+		s.infection[i] = 1
+	}
+	for _, v := range s.infection {
+		if v > 0 {
+			infected++
+		}
+	}
+	if infected == 0 {
+		s.infection = nil
+	}
+	return killed, infected
+}
+
+// infectedNeighbors counts the infected neighbours of (x, y).
+func (s *Simulation) infectedNeighbors(x, y int) int {
+	n := s.gridSize
+	count := 0
+	for ny := max(y-1, 0); ny <= min(y+1, n-1); ny++ {
+		for nx := max(x-1, 0); nx <= min(x+1, n-1); nx++ {
+			if (nx != x || ny != y) && s.infection[ny*n+nx] > 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// drawInfected paints the infected cells in infectedColor.
+func drawInfected(img *image.RGBA, infection []int, gridSize, cellSize int) {
+	bounds := img.Bounds()
+	for i, v := range infection {
+		if v == 0 {
+			continue
+		}
+		x, y := i%gridSize, i/gridSize
+		for dy := 0; dy < cellSize; dy++ {
+			py := bounds.Min.Y + y*cellSize + dy
+			if py >= bounds.Max.Y {
+				break
+			}
+			for dx := 0; dx < cellSize; dx++ {
+				px := bounds.Min.X + x*cellSize + dx
+				if px >= bounds.Max.X {
+					break
+				}
+				img.SetRGBA(px, py, infectedColor)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+)
+
+// responsiveLayout places the grid display and the control panel either
+// stacked (panel below) or side by side (panel on the right), whichever
+// leaves the larger square for the grid. Objects are the display and a
+// scroll container holding the panel.
+type responsiveLayout struct {
+	wide bool
+}
+
+func (l *responsiveLayout) panelSize(objects []fyne.CanvasObject) fyne.Size {
+	if scroll, ok := objects[1].(*container.Scroll); ok && scroll.Content != nil {
+		return scroll.Content.MinSize()
+	}
+	return objects[1].MinSize()
+}
+
+func (l *responsiveLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	display, panel := objects[0], objects[1]
+	p := l.panelSize(objects)
+
+	stacked := fyne.Min(size.Width, size.Height-p.Height)
+	side := fyne.Min(size.Width-p.Width, size.Height)
+	l.wide = side > stacked
+
+	if l.wide {
+		display.Move(fyne.NewPos(0, 0))
+		display.Resize(fyne.NewSize(size.Width-p.Width, size.Height))
+		panel.Move(fyne.NewPos(size.Width-p.Width, 0))
+		panel.Resize(fyne.NewSize(p.Width, size.Height))
+		return
+	}
+	panelHeight := fyne.Min(p.Height, size.Height-display.MinSize().Height)
+	display.Move(fyne.NewPos(0, 0))
+	display.Resize(fyne.NewSize(size.Width, size.Height-panelHeight))
+	panel.Move(fyne.NewPos(0, size.Height-panelHeight))
+	panel.Resize(fyne.NewSize(size.Width, panelHeight))
+}
+
+// MinSize follows the current arrangement so a wide window can be made
+// shorter than the stacked layout would allow; the panel scrolls then.
+func (l *responsiveLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	d := objects[0].MinSize()
+	p := l.panelSize(objects)
+	if l.wide {
+		return fyne.NewSize(d.Width+p.Width, d.Height)
+	}
+	return fyne.NewSize(fyne.Max(d.Width, p.Width), d.Height+objects[1].MinSize().Height)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Lenia parameters: a single smooth ring kernel and a Gaussian growth bump
+// around leniaMu, the classic setting of the Orbium glider.
+const (
+	leniaMu    = 0.15
+	leniaSigma = 0.015
+	leniaDT    = 0.1
+)
+
+// lenia is a continuous-state automaton in the style of Lenia and
+// SmoothLife: each cell holds a level in [0, 1], moved at each generation by
+// a smooth growth function of the convolution of its surroundings with a
+// ring kernel. The grid wraps around.
+type lenia struct {
+	size        int
+	radius      int
+	level, next []float64 // row-major levels
+	taps        []leniaTap
+	wrap        []int // wrap[i+radius] is i modulo size
+}
+
+type leniaTap struct {
+	dx, dy int
+	weight float64
+}
+
+// leniaRadius scales the kernel with the grid, so patterns keep a similar
+// size relative to small grids.
+func leniaRadius(gridSize int) int {
+	return clampInt(gridSize/8, 4, 13)
+}
+
+// leniaKernel samples the ring exp(4 - 1/(r(1-r))), r being the distance
+// over the radius, normalized to a total weight of 1.
+func leniaKernel(radius int) []leniaTap {
+	var taps []leniaTap
+	total := 0.0
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			r := math.Hypot(float64(dx), float64(dy)) / float64(radius)
+			if r <= 0 || r >= 1 {
+				continue
+			}
+			w := math.Exp(4 - 1/(r*(1-r)))
+			taps = append(taps, leniaTap{dx, dy, w})
+			total += w
+		}
+	}
+	for i := range taps {
+		taps[i].weight /= total
+	}
+	return taps
+}
+
+// leniaGrowth maps a neighbourhood potential to a growth rate in [-1, 1].
+func leniaGrowth(u float64) float64 {
+	d := (u - leniaMu) / leniaSigma
+	return 2*math.Exp(-d*d/2) - 1
+}
+
+func (*lenia) Name() string { return "Lenia" }
+
+func (l *lenia) resize(n int) {
+	if l.level != nil && l.size == n {
+		clear(l.level)
+		return
+	}
+	l.size = n
+	l.radius = leniaRadius(n)
+	l.level = make([]float64, n*n)
+	l.next = make([]float64, n*n)
+	l.taps = leniaKernel(l.radius)
+	l.wrap = make([]int, n+2*l.radius)
+	for i := range l.wrap {
+		l.wrap[i] = ((i-l.radius)%n + n) % n
+	}
+}
+
+// seed drops a few square patches of random levels, about the size of the
+// kernel, mirrored by the symmetry.
+func (l *lenia) seed(sim *Simulation) {
+	n := sim.gridSize
+	l.resize(n)
+	side := min(2*l.radius, n)
+	var pts []image.Point
+	for range 2 + sim.rng.Intn(4) {
+		x0, y0 := sim.rng.Intn(n), sim.rng.Intn(n)
+		for dy := 0; dy < side; dy++ {
+			for dx := 0; dx < side; dx++ {
+				v := sim.rng.Float64()
+				pts = sim.symmetry.orbit(pts[:0], (x0+dx)%n, (y0+dy)%n, n)
+				for _, p := range pts {
+					l.level[p.Y*n+p.X] = v
+				}
+			}
+		}
+	}
+	l.publish(sim)
+}
+
+func (l *lenia) load(sim *Simulation) {
+	l.resize(sim.gridSize)
+	for i, c := range sim.grid.cells {
+		l.level[i] = float64(c) / maxCellAge
+	}
+}
+
+func (l *lenia) step(sim *Simulation) (births int) {
+	n := l.size
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			u := 0.0
+			for _, t := range l.taps {
+				u += t.weight * l.level[l.wrap[y+t.dy+l.radius]*n+l.wrap[x+t.dx+l.radius]]
+			}
+			a := l.level[y*n+x] + leniaDT*leniaGrowth(u)
+			l.next[y*n+x] = min(max(a, 0), 1)
+		}
+	}
+	l.level, l.next = l.next, l.level
+	return l.publish(sim)
+}
+
+func (l *lenia) set(x, y, val int) {
+	l.level[y*l.size+x] = float64(val) / maxCellAge
+}
+
+// publish writes the levels to sim.grid, a level of 1 being maxCellAge, and
+// returns how many dead cells came alive.
+func (l *lenia) publish(sim *Simulation) (births int) {
+	for i, c := range sim.grid.cells {
+		val := uint8(l.level[i] * maxCellAge)
+		if c == 0 && val > 0 {
+			births++
+		}
+		sim.grid.cells[i] = val
+	}
+	return births
+}
+
+// draw renders the levels through the palette's gradient.
+func (l *lenia) draw(img *image.RGBA, palette ColorPalette, cellSize int) {
+	drawLevels(img, l.level, l.size, palette, cellSize)
+}
+
+// drawLevels renders a row-major grid of levels in [0, 1] through a
+// continuous gradient running from the dead color through the palette's
+// young, mature and old colors.
+func drawLevels(img *image.RGBA, level []float64, size int, palette ColorPalette, cellSize int) {
+	stops := []color.Color{palette.dead, palette.young[0], palette.mature[7], palette.old[10], palette.old[29]}
+	var lut [256][4]uint8
+	for i := range lut {
+		lut[i] = gradientAt(stops, float64(i)/255)
+	}
+	bounds := img.Bounds()
+	width := min(size*cellSize, bounds.Dx())
+	height := min(size*cellSize, bounds.Dy())
+	for py := 0; py < height; py++ {
+		row := level[py/cellSize*size:]
+		off := img.PixOffset(bounds.Min.X, bounds.Min.Y+py)
+		for px := 0; px < width; px++ {
+			c := lut[int(row[px/cellSize]*255)]
+			copy(img.Pix[off+px*4:off+px*4+4], c[:])
+		}
+	}
+}
+
+// gradientAt interpolates linearly between evenly spaced color stops, t
+// running from 0 to 1.
+func gradientAt(stops []color.Color, t float64) [4]uint8 {
+	pos := t * float64(len(stops)-1)
+	i := min(int(pos), len(stops)-2)
+	f := pos - float64(i)
+	r0, g0, b0, _ := stops[i].RGBA()
+	r1, g1, b1, _ := stops[i+1].RGBA()
+	mix := func(a, b uint32) uint8 {
+		return uint8(float64(a>>8)*(1-f) + float64(b>>8)*f)
+	}
+	return [4]uint8{mix(r0, r1), mix(g0, g1), mix(b0, b1), 255}
+}
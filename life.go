@@ -0,0 +1,50 @@
+package main
+
+import "image"
+
+// conwayLife is Conway's Game of Life, B3/S23: a dead cell with exactly
+// three living neighbours is born, a living cell with two or three
+// survives. Cells past the edges count as dead. Grid values are the ages
+// of the living cells, so the palettes and views keep working.
+type conwayLife struct{}
+
+func (conwayLife) Name() string { return "Conway's Life" }
+
+// seed fills the middle square, half the grid wide, with a random soup, a
+// third of it alive.
+func (conwayLife) seed(sim *Simulation) {
+	n := sim.gridSize
+	side := max(n/2, 1)
+	x0 := (n - side) / 2
+	var pts []image.Point
+	for dy := 0; dy < side; dy++ {
+		for dx := 0; dx < side; dx++ {
+			if sim.rng.Intn(3) == 0 {
+				pts = setOrbit(sim, pts, x0+dx, x0+dy, 1)
+			}
+		}
+	}
+}
+
+func (conwayLife) load(*Simulation) {}
+
+func (conwayLife) step(sim *Simulation) (births int) {
+	for y := range sim.gridSize {
+		next := sim.next.row(y)
+		for x, c := range sim.grid.row(y) {
+			val := 0
+			switch n := livingNeighbors(sim.grid, x, y); {
+			case c > 0 && (n == 2 || n == 3):
+				val = min(int(c)+1, maxCellAge)
+			case c == 0 && n == 3:
+				val = 1
+				births++
+			}
+			next[x] = uint8(val)
+		}
+	}
+	sim.grid, sim.next = sim.next, sim.grid
+	return births
+}
+
+func (conwayLife) set(x, y, val int) {}
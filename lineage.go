@@ -0,0 +1,109 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2/lang"
+)
+
+// topLineages is the number of lineages listed in the statistics.
+const topLineages = 3
+
+// lineageTracker follows the dynasties of the built-in rules: every newborn
+// inherits the lineage of its dominant parent, the oldest living neighbour
+// that already had one, and a cell with no such neighbour (the first cells,
+// immigrants, painted cells) founds a lineage of its own.
+type lineageTracker struct {
+	ids     []int32 // lineage of each cell, row-major; 0 for empty cells
+	next    []int32
+	nextID  int32
+	founded map[int32]int // generation each living lineage appeared
+	sizes   map[int32]int // living cells of each living lineage
+	peak    map[int32]int // largest size each living lineage reached
+}
+
+func newLineageTracker(size int) *lineageTracker {
+	return &lineageTracker{
+		ids:     make([]int32, size*size),
+		next:    make([]int32, size*size),
+		founded: make(map[int32]int),
+		sizes:   make(map[int32]int),
+		peak:    make(map[int32]int),
+	}
+}
+
+// update assigns the lineages of grid, the generation just computed, and
+// forgets the lineages that died out.
+func (t *lineageTracker) update(grid *Grid, generation int) {
+	n := grid.size
+	clear(t.sizes)
+	for y := range n {
+		for x, c := range grid.row(y) {
+			i := y*n + x
+			id := int32(0)
+			switch {
+			case c == 0:
+			case t.ids[i] != 0:
+				id = t.ids[i]
+			default:
+				oldest := 0
+				for ny := max(y-1, 0); ny <= min(y+1, n-1); ny++ {
+					for nx := max(x-1, 0); nx <= min(x+1, n-1); nx++ {
+						if parent := t.ids[ny*n+nx]; parent != 0 && grid.at(nx, ny) > oldest {
+							id, oldest = parent, grid.at(nx, ny)
+						}
+					}
+				}
+				if id == 0 {
+					t.nextID++
+					id = t.nextID
+					t.founded[id] = generation
+				}
+			}
+			t.next[i] = id
+			if id != 0 {
+				t.sizes[id]++
+			}
+		}
+	}
+	t.ids, t.next = t.next, t.ids
+	for id := range t.founded {
+		size, alive := t.sizes[id]
+		if !alive {
+			delete(t.founded, id)
+			delete(t.peak, id)
+			continue
+		}
+		t.peak[id] = max(t.peak[id], size)
+	}
+}
+
+// top returns the living lineages by decreasing size, at most n of them.
+func (t *lineageTracker) top(n int) []int32 {
+	ids := make([]int32, 0, len(t.sizes))
+	for id := range t.sizes {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b int32) int {
+		return cmp.Or(cmp.Compare(t.sizes[b], t.sizes[a]), cmp.Compare(a, b))
+	})
+	return ids[:min(n, len(ids))]
+}
+
+// summary is the lineage lines of the statistics: the living lineages, and
+// the largest ones with their size, peak and age.
+func (t *lineageTracker) summary(generation int) string {
+	var top []string
+	for _, id := range t.top(topLineages) {
+		top = append(top, fmt.Sprintf(lang.L("#%d: %d cells (peak %d, %d gens)"),
+			id, t.sizes[id], t.peak[id], generation-t.founded[id]))
+	}
+	text := fmt.Sprintf(lang.L("Lineages: %d"), len(t.sizes))
+	if len(top) > 0 {
+		text += "\n" + strings.Join(top, "\n")
+	}
+	return text
+}
@@ -0,0 +1,19 @@
+// Package livingnumbers runs the living numbers automaton without the app:
+// a square grid of cells aged 0 (dead) to MaxAge, where empty cells are
+// born with a chance that grows with the ages around them, crowded cells
+// age, isolated ones die, and the oldest are reborn at age 1.
+//
+// An Engine steps a Grid under Rules and reports the Stats of each
+// generation; a Palette draws a grid with the colors of the app's original
+// palette. Given the same seed and rules, an Engine goes through the same
+// generations as the app with its extra features off (symmetry, curves,
+// zones, metabolism and the other rule families are app-only), so runs
+// found in the app can be studied programmatically, and the other way
+// round:
+//
+//	e := livingnumbers.New(60, 42, livingnumbers.DefaultRules())
+//	for e.Stats().Population > 0 && e.Generation() < 1000 {
+//		e.Step()
+//	}
+//	fmt.Println(e.Stats().AvgAge)
+package livingnumbers
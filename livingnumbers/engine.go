@@ -0,0 +1,134 @@
+package livingnumbers
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Engine runs the automaton on a grid. Its random source is seeded, so a
+// run is reproduced from its seed, size and rules. An Engine is not safe
+// for concurrent use.
+type Engine struct {
+	// Rules apply from the next step on; they may change between steps.
+	Rules Rules
+
+	grid, next *Grid
+	rng        *rand.Rand
+	generation int
+	stats      Stats
+}
+
+// New returns an engine on a size × size grid seeded as the app seeds a
+// run, see Reset.
+func New(size int, seed int64, rules Rules) *Engine {
+	e := &Engine{Rules: rules, grid: NewGrid(size), next: NewGrid(size)}
+	e.Reset(seed)
+	return e
+}
+
+// Reset reseeds the random source and starts over at generation 0 from 200
+// to 599 cells aged 1 to 10 scattered at random.
+func (e *Engine) Reset(seed int64) {
+	e.rng = rand.New(rand.NewSource(seed))
+	e.grid.Clear()
+	e.generation = 0
+	n := e.grid.size
+	count := 200 + e.rng.Intn(400)
+	for range count {
+		x := e.rng.Intn(n)
+		y := e.rng.Intn(n)
+		e.grid.Set(x, y, e.rng.Intn(10)+1)
+	}
+	e.stats = measure(e.grid, 0)
+}
+
+// Load replaces the grid with a copy of g, resizing the engine to it, and
+// starts over at generation 0. The random source carries on.
+func (e *Engine) Load(g *Grid) {
+	e.grid, e.next = g.Clone(), NewGrid(g.size)
+	e.generation = 0
+	e.stats = measure(e.grid, 0)
+}
+
+// Grid returns the current grid. It is the engine's own, changed by the
+// next step; Clone it to keep it.
+func (e *Engine) Grid() *Grid { return e.grid }
+
+// Generation returns the number of steps since the last Reset or Load.
+func (e *Engine) Generation() int { return e.generation }
+
+// Stats returns the statistics of the current generation.
+func (e *Engine) Stats() Stats { return e.stats }
+
+// Step advances one generation and reports whether a mutation burst
+// occurred.
+func (e *Engine) Step() (mutated bool) {
+	e.generation++
+	n := e.grid.size
+	if e.rng.Float64() < e.Rules.MutationChance {
+		for i := 0; i < 5+e.rng.Intn(10); i++ {
+			x := e.rng.Intn(n)
+			y := e.rng.Intn(n)
+			if e.grid.At(x, y) > 0 {
+				e.grid.Set(x, y, 1+e.rng.Intn(20))
+			}
+		}
+		mutated = true
+	}
+	births, rebirths := e.evolve()
+	e.grid, e.next = e.next, e.grid
+	e.stats = measure(e.grid, e.generation)
+	e.stats.Births = births
+	e.stats.Rebirths = rebirths
+	return mutated
+}
+
+// Run steps until the population dies out or generations steps have run,
+// and returns the last statistics.
+func (e *Engine) Run(generations int) Stats {
+	for range generations {
+		if e.stats.Population == 0 {
+			break
+		}
+		e.Step()
+	}
+	return e.stats
+}
+
+// evolve writes the generation following e.grid into e.next.
+func (e *Engine) evolve() (births, rebirths int) {
+	r := e.Rules
+	g := e.grid
+	for y := range g.size {
+		for x := range g.size {
+			val := g.At(x, y)
+			sum := g.neighbors(x, y)
+			chance := r.GrowthRate * (float64(sum) / 50)
+			if val == 0 && e.rng.Float64() < chance {
+				val = 1
+				births++
+			} else if val > 0 {
+				if e.exceeds(r.Survival-float64(sum), r.Temperature) {
+					val = 0
+				} else if e.exceeds(float64(sum)-r.Aging, r.Temperature) {
+					val++
+					if val > MaxAge {
+						val = 1
+						rebirths++
+					}
+				}
+			}
+			e.next.cells[y*g.size+x] = uint8(val)
+		}
+	}
+	return births, rebirths
+}
+
+// exceeds decides a threshold for a neighbour sum lying margin beyond it:
+// the plain comparison at temperature 0, a logistic chance above.
+func (e *Engine) exceeds(margin, temperature float64) bool {
+	if temperature <= 0 {
+		return margin > 0
+	}
+	return e.rng.Float64() < 1/(1+math.Exp(-margin/temperature))
+}
@@ -0,0 +1,26 @@
+package livingnumbers_test
+
+import (
+	"fmt"
+	"image/png"
+	"io"
+
+	"github.com/maximedotair/living_numbers_game_go/livingnumbers"
+)
+
+func Example() {
+	e := livingnumbers.New(60, 42, livingnumbers.DefaultRules())
+	s := e.Run(50)
+	fmt.Printf("generation %d: %d cells, average age %.1f\n", s.Generation, s.Population, s.AvgAge)
+	// Output: generation 50: 788 cells, average age 13.2
+}
+
+func ExamplePalette_Image() {
+	e := livingnumbers.New(60, 1, livingnumbers.DefaultRules())
+	e.Run(100)
+	p := livingnumbers.DefaultPalette()
+	img := p.Image(e.Grid(), 8)
+	fmt.Println(img.Bounds().Dx(), "×", img.Bounds().Dy())
+	png.Encode(io.Discard, img) // or to a file
+	// Output: 480 × 480
+}
@@ -0,0 +1,54 @@
+package livingnumbers
+
+// MaxAge is the oldest a cell gets: ageing past it, a cell is reborn at 1.
+const MaxAge = 50
+
+// Grid is a square grid of cell ages, 0 for an empty cell. The zero value
+// is an empty grid of size 0; use NewGrid.
+type Grid struct {
+	size  int
+	cells []uint8 // row-major
+}
+
+// NewGrid returns an empty size × size grid.
+func NewGrid(size int) *Grid {
+	return &Grid{size: size, cells: make([]uint8, size*size)}
+}
+
+// Size returns the side of the grid, in cells.
+func (g *Grid) Size() int { return g.size }
+
+// At returns the age of the cell at (x, y), 0 if it is empty.
+func (g *Grid) At(x, y int) int {
+	return int(g.cells[y*g.size+x])
+}
+
+// Set sets the age of the cell at (x, y), clamped to 0-MaxAge.
+func (g *Grid) Set(x, y, age int) {
+	g.cells[y*g.size+x] = uint8(min(max(age, 0), MaxAge))
+}
+
+// Clone returns a copy of g.
+func (g *Grid) Clone() *Grid {
+	c := NewGrid(g.size)
+	copy(c.cells, g.cells)
+	return c
+}
+
+// Clear empties every cell.
+func (g *Grid) Clear() {
+	clear(g.cells)
+}
+
+// neighbors returns the sum of the ages of the eight neighbours of (x, y),
+// cells past the edges counting as empty.
+func (g *Grid) neighbors(x, y int) int {
+	sum := 0
+	for ny := max(y-1, 0); ny <= min(y+1, g.size-1); ny++ {
+		row := g.cells[ny*g.size : (ny+1)*g.size]
+		for nx := max(x-1, 0); nx <= min(x+1, g.size-1); nx++ {
+			sum += int(row[nx])
+		}
+	}
+	return sum - g.At(x, y)
+}
@@ -0,0 +1,72 @@
+package livingnumbers
+
+import (
+	"image"
+	"image/color"
+)
+
+// Palette is the color of each age, index 0 being empty cells.
+type Palette [MaxAge + 1]color.RGBA
+
+// NewPalette shades the three age bands from their base colors, as the app
+// does without its random variations: young cells (ages 1-4) brighten with
+// age, mature ones (5-19) shift towards red and blue, old ones (20-50)
+// darken.
+func NewPalette(dead, young, mature, old color.RGBA) Palette {
+	var p Palette
+	p[0] = dead
+	scale := func(v uint8, f float32) uint8 { return uint8(float32(v) * f) }
+	for age := 1; age < 5; age++ {
+		f := 0.5 + float32(age-1)*0.1
+		p[age] = color.RGBA{scale(young.R, f), scale(young.G, f), scale(young.B, f), 255}
+	}
+	for age := 5; age < 20; age++ {
+		f := float32(age-5) / 15
+		p[age] = color.RGBA{scale(mature.R, 0.7+f*0.3), scale(mature.G, 1-f*0.5), scale(mature.B, 0.5+f*0.5), 255}
+	}
+	for age := 20; age <= MaxAge; age++ {
+		f := 1 - float32(min(age-20, 29))/30*0.6
+		p[age] = color.RGBA{scale(old.R, f), scale(old.G, f), scale(old.B, f), 255}
+	}
+	return p
+}
+
+// DefaultPalette returns the app's original palette: black, green, yellow
+// and red.
+func DefaultPalette() Palette {
+	return NewPalette(
+		color.RGBA{0, 0, 0, 255},
+		color.RGBA{0, 200, 0, 255},
+		color.RGBA{200, 200, 0, 255},
+		color.RGBA{255, 0, 0, 255},
+	)
+}
+
+// Color returns the color of a cell of the given age.
+func (p *Palette) Color(age int) color.RGBA {
+	return p[min(max(age, 0), MaxAge)]
+}
+
+// Draw paints g into img, cellSize pixels a cell from its top-left corner,
+// clipped to img.
+func (p *Palette) Draw(img *image.RGBA, g *Grid, cellSize int) {
+	b := img.Bounds()
+	for y := range g.size {
+		for x := range g.size {
+			c := p.Color(g.At(x, y))
+			cell := image.Rect(x*cellSize, y*cellSize, (x+1)*cellSize, (y+1)*cellSize).Add(b.Min).Intersect(b)
+			for py := cell.Min.Y; py < cell.Max.Y; py++ {
+				for px := cell.Min.X; px < cell.Max.X; px++ {
+					img.SetRGBA(px, py, c)
+				}
+			}
+		}
+	}
+}
+
+// Image returns g drawn cellSize pixels a cell.
+func (p *Palette) Image(g *Grid, cellSize int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, g.size*cellSize, g.size*cellSize))
+	p.Draw(img, g, cellSize)
+	return img
+}
@@ -0,0 +1,73 @@
+package livingnumbers
+
+import (
+	"image/color"
+	"sync"
+)
+
+// Rand is the random source handed to rulesets: the app's, so runs stay
+// reproducible from their seed. *rand.Rand implements it.
+type Rand interface {
+	Float64() float64
+	Intn(n int) int
+}
+
+// Ruleset is a rule family added to the app by RegisterRuleset. Its grids
+// hold ages from 0 to MaxAge, drawn with the palette and counted in the
+// statistics like any other.
+type Ruleset interface {
+	// Name is shown in the Rule family selector; it must be unique.
+	Name() string
+	// Seed fills the empty grid g with an initial state.
+	Seed(g *Grid, rng Rand)
+	// Step writes the generation following cur into next, a grid of the
+	// same size, and returns the number of births.
+	Step(cur, next *Grid, rng Rand) (births int)
+}
+
+// Colorizer is a view mode added to the app by RegisterColorizer.
+type Colorizer interface {
+	// Name is shown in the Color by selector; it must be unique.
+	Name() string
+	// Colors is called once a frame and returns the color of each cell of
+	// g, p being the app's current palette.
+	Colors(g *Grid, p *Palette) func(x, y int) color.RGBA
+}
+
+var registry struct {
+	sync.Mutex
+	rulesets   []func() Ruleset
+	colorizers []Colorizer
+}
+
+// RegisterRuleset adds a rule family to the app. newRuleset returns a fresh
+// ruleset for each run. Call it from the init function of a package the
+// app imports, see the README.
+func RegisterRuleset(newRuleset func() Ruleset) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.rulesets = append(registry.rulesets, newRuleset)
+}
+
+// RegisterColorizer adds a view mode to the app. Call it from the init
+// function of a package the app imports.
+func RegisterColorizer(c Colorizer) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.colorizers = append(registry.colorizers, c)
+}
+
+// Rulesets returns the constructors of the registered rulesets, in
+// registration order.
+func Rulesets() []func() Ruleset {
+	registry.Lock()
+	defer registry.Unlock()
+	return append([]func() Ruleset(nil), registry.rulesets...)
+}
+
+// Colorizers returns the registered colorizers, in registration order.
+func Colorizers() []Colorizer {
+	registry.Lock()
+	defer registry.Unlock()
+	return append([]Colorizer(nil), registry.colorizers...)
+}
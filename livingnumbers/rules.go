@@ -0,0 +1,33 @@
+package livingnumbers
+
+// Rules are the parameters of the automaton. Each generation, with
+// neighbours meaning the sum of the ages of the eight cells around:
+//
+//   - an empty cell is born, at age 1, with chance GrowthRate × neighbours/50;
+//   - a living cell dies when its neighbours sum below Survival;
+//   - a surviving cell ages by one when they sum above Aging, past MaxAge
+//     being reborn at 1;
+//   - before all this, with chance MutationChance, a burst of 5 to 14 random
+//     cells gives the living ones among them a random age of 1 to 20.
+//
+// At a Temperature above 0 the two thresholds get fuzzy: a cell whose
+// neighbours sum m beyond a threshold (negative when short of it) crosses
+// it with chance 1/(1+exp(-m/Temperature)).
+type Rules struct {
+	GrowthRate     float64 // the app's range is 0.05-0.5
+	MutationChance float64 // per generation, the app's range is 0-0.1
+	Temperature    float64 // 0 for sharp thresholds
+	Survival       float64
+	Aging          float64
+}
+
+// DefaultRules returns the app's defaults: survival from a neighbour sum of
+// 3, ageing from 21.
+func DefaultRules() Rules {
+	return Rules{
+		GrowthRate:     0.05,
+		MutationChance: 0.01,
+		Survival:       2.5,
+		Aging:          20.5,
+	}
+}
@@ -0,0 +1,36 @@
+package livingnumbers
+
+import "math"
+
+// Stats are the figures of a generation.
+type Stats struct {
+	Generation   int
+	Population   int     // living cells
+	Density      float64 // living cells over all cells
+	AvgAge       float64 // of the living cells
+	Entropy      float64 // binary entropy of the density, in bits
+	Births       int     // empty cells born this generation
+	Rebirths     int     // cells reborn at 1 past MaxAge this generation
+	AgeHistogram [MaxAge]int
+}
+
+// measure returns the statistics of g at generation.
+func measure(g *Grid, generation int) Stats {
+	s := Stats{Generation: generation}
+	total := 0
+	for _, c := range g.cells {
+		if c > 0 {
+			s.Population++
+			total += int(c)
+			s.AgeHistogram[c-1]++
+		}
+	}
+	s.Density = float64(s.Population) / float64(g.size*g.size)
+	if s.Population > 0 {
+		s.AvgAge = float64(total) / float64(s.Population)
+	}
+	if p := s.Density; p > 0 && p < 1 {
+		s.Entropy = -p*math.Log2(p) - (1-p)*math.Log2(1-p)
+	}
+	return s
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// perfLogInterval is the number of generations between two performance
+// log records.
+const perfLogInterval = 100
+
+// setupLogging installs the default slog logger described by opts: text or
+// JSON records at opts.logLevel and above, appended to opts.logFile or
+// written to stderr. The returned closer flushes the log file.
+func setupLogging(opts launchOptions) (io.Closer, error) {
+	var out io.WriteCloser = nopCloser{os.Stderr}
+	if opts.logFile != "" {
+		f, err := os.OpenFile(opts.logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		out = f
+	}
+	handlerOpts := &slog.HandlerOptions{Level: opts.logLevel}
+	var handler slog.Handler
+	switch opts.logFormat {
+	case "json":
+		handler = slog.NewJSONHandler(out, handlerOpts)
+	case "text":
+		handler = slog.NewTextHandler(out, handlerOpts)
+	default:
+		out.Close()
+		return nil, fmt.Errorf("unknown log format %q", opts.logFormat)
+	}
+	slog.SetDefault(slog.New(handler))
+	return out, nil
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// logEvent records a simulation event; disasters and trigger hits are
+// warnings so they stand out at the default level.
+func logEvent(e Event) {
+	level := slog.LevelInfo
+	switch e.eventType {
+	case "SUPERNOVA", "INFECTION", "TRIGGER":
+		level = slog.LevelWarn
+	}
+	slog.Log(context.Background(), level, e.message, "event", e.eventType, "generation", e.generation)
+}
+
+// logParam records a user parameter change.
+func logParam(name string, value any) {
+	slog.Debug("parameter changed", "param", name, "value", value)
+}
+
+// logPerf records the performance counters of a run.
+func logPerf(generation int, m *perfMeter) {
+	slog.Info("performance",
+		"generation", generation,
+		"gens_per_sec", m.gensPerSec,
+		"frame_ms", m.frame.Seconds()*1000,
+		"evolve_ms", m.step.Seconds()*1000,
+		"render_ms", m.render.Seconds()*1000,
+		"effects_ms", m.effects.Seconds()*1000)
+}
@@ -1,11 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"image"
 	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
 	"math"
 	"math/rand"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -13,11 +24,15 @@ import (
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
 )
 
 const (
-	displaySize = 300 // Fixed display size in pixels
+	displaySize       = 300 // Fixed display size in pixels
+	defaultNovaRadius = 17  // Supernova radius in cells
 )
 
 var (
@@ -25,25 +40,41 @@ var (
 	currentGridSize = displaySize / currentCellSize
 )
 
-type Cell struct {
-	val int
-}
-
 type ColorPalette struct {
 	dead   color.Color
 	young  [5]color.Color
 	mature [15]color.Color
 	old    [30]color.Color
 	cycle  float64 // For palette animation
+
+	// Bloom look of the palette: glow strength and the tint of the glow
+	bloomIntensity float64
+	glow           color.RGBA
+
+	// Age bands told apart by texture as well, see drawBandPatterns
+	patterned bool
+
+	// Color of each cell value, resolved once per palette by fillColors
+	colors [maxCellAge + 1]color.RGBA
 }
 
 type Stats struct {
-	generation   int
-	population   int
-	density      float64
-	avgAge       float64
-	entropy      float64
-	ageHistogram [50]int
+	generation    int
+	population    int
+	density       float64
+	avgAge        float64
+	entropy       float64
+	births        int // empty cells that came alive this generation
+	rebirths      int // cells that wrapped from age 50 back to 1 this generation
+	colonies      int
+	largestColony int
+	ageHistogram  [50]int
+	avgEnergy     float64 // of the living cells, while metabolism is on
+	starved       int     // cells that ran out of energy this generation
+	prey          int     // Wa-Tor fish
+	predators     int     // Wa-Tor sharks
+	infected      int     // cells carrying the disease
+	diseaseDeaths int     // infected cells that died this generation
 }
 
 type Event struct {
@@ -52,18 +83,30 @@ type Event struct {
 	message    string
 }
 
+// SimulationState is shared by the UI thread and the simulation goroutine:
+// the run's model, owned by the engine, and the display settings, both
+// reached by the UI through the view-model, see viewModel. mu guards every
+// field except events and bus, which have their own locks. The goroutine
+// holds mu while it steps and renders a generation; the UI holds it
+// whenever it writes a field, and when it reads one the goroutine writes
+// (stats, growthRate and mutationChance under automation, isPaused,
+// isStarted, scenario, triggers).
+//
+// Safe to change mid-run: growthRate, mutationChance (unless automated),
+// temperature, ageCurves, birthCurve, metabolism, nutrients, seasonPeriod,
+// drift, ruleDrift, zoneLayout, climates, movementRate, immigration,
+// entryEdge, infectionRate, infectionSpan, radiation, novaRadius, speed,
+// speedMode, symmetry, gridLines, deadGhosts, effects, automation, triggers,
+// catastrophes, paletteSpeed, paletteFrozen and isPaused.
+// The controls for paletteMode, cellSize, gridSize, ruleFamily and scenario are
+// disabled during a run, so those only change while stopped.
 type SimulationState struct {
-	growthRate     float64
-	mutationChance float64
-	paletteMode    int
-	bloomEffect    bool
-	events         []Event
-	stats          Stats
-	isPaused       bool
-	isStarted      bool
-	cellSize       int
-	gridSize       int
-	speed          int // ms between each generation
+	mu sync.Mutex
+	runModel
+	displaySettings
+
+	events *EventHistory
+	bus    eventBus // what runs report to the frontends
 }
 
 type mainThreadRunner interface {
@@ -74,6 +117,8 @@ type mainThreadCaller interface {
 	CallOnMainThread(func())
 }
 
+// runOnMain runs fn on the UI thread and waits for it. Never call it with
+// state.mu held: fn may need the lock.
 func runOnMain(d fyne.Driver, fn func()) {
 	switch drv := d.(type) {
 	case mainThreadRunner:
@@ -81,7 +126,7 @@ func runOnMain(d fyne.Driver, fn func()) {
 	case mainThreadCaller:
 		drv.CallOnMainThread(fn)
 	default:
-		fn()
+		fyne.DoAndWait(fn)
 	}
 }
 
@@ -89,7 +134,7 @@ func randomColor(rng *rand.Rand, baseR, baseG, baseB uint8, variance uint8) colo
 	r := int(baseR) + rng.Intn(int(variance)*2) - int(variance)
 	g := int(baseG) + rng.Intn(int(variance)*2) - int(variance)
 	b := int(baseB) + rng.Intn(int(variance)*2) - int(variance)
-	
+
 	clamp := func(v int) uint8 {
 		if v < 0 {
 			return 0
@@ -99,20 +144,36 @@ func randomColor(rng *rand.Rand, baseR, baseG, baseB uint8, variance uint8) colo
 		}
 		return uint8(v)
 	}
-	
+
 	return color.RGBA{clamp(r), clamp(g), clamp(b), 255}
 }
 
-
 func generateDynamicPalette(rng *rand.Rand, cycle float64, mode int) ColorPalette {
 	var p ColorPalette
 	p.cycle = cycle
-	
+
 	p.dead = color.RGBA{0, 0, 0, 255}
-	
+
 	// Different palette modes
 	var youngBase, matureBase, oldBase struct{ r, g, b uint8 }
-	
+	imported := imagePalette.Load()
+	if mode == imagePaletteMode && imported == nil {
+		mode = 3 // nothing imported yet
+	}
+
+	// Neutral white glow unless the palette asks for a warmer or cooler one
+	p.bloomIntensity = 0.3
+	p.glow = color.RGBA{255, 255, 255, 255}
+
+	// Colorblind-safe modes keep fixed colors
+	if bases, ok := safeBases[mode]; ok {
+		fillSafeRamps(&p, bases)
+		p.patterned = mode == monochromeMode
+		p.adaptToCanvas()
+		p.fillColors()
+		return p
+	}
+
 	switch mode {
 	case 0: // Rainbow Mode
 		youngBase = struct{ r, g, b uint8 }{
@@ -130,20 +191,32 @@ func generateDynamicPalette(rng *rand.Rand, cycle float64, mode int) ColorPalett
 			uint8(100 + 100*math.Cos(cycle)),
 			uint8(150 + 105*math.Sin(cycle+math.Pi)),
 		}
+		p.bloomIntensity = 0.35
 	case 1: // Ocean Mode
 		youngBase = struct{ r, g, b uint8 }{0, uint8(150 + 50*math.Sin(cycle)), uint8(200 + 55*math.Cos(cycle))}
 		matureBase = struct{ r, g, b uint8 }{0, uint8(180 + 75*math.Sin(cycle)), uint8(150 + 50*math.Cos(cycle))}
 		oldBase = struct{ r, g, b uint8 }{uint8(50 + 50*math.Sin(cycle)), uint8(100 + 100*math.Cos(cycle)), 200}
+		p.bloomIntensity = 0.4
+		p.glow = color.RGBA{120, 230, 255, 255}
 	case 2: // Fire Mode
 		youngBase = struct{ r, g, b uint8 }{uint8(200 + 55*math.Sin(cycle)), uint8(100 + 50*math.Cos(cycle)), 0}
 		matureBase = struct{ r, g, b uint8 }{uint8(255 - 55*math.Cos(cycle)), uint8(150 + 50*math.Sin(cycle)), 0}
 		oldBase = struct{ r, g, b uint8 }{255, uint8(50 + 100*math.Sin(cycle)), uint8(50 + 100*math.Cos(cycle))}
+		p.bloomIntensity = 0.5
+		p.glow = color.RGBA{255, 170, 60, 255}
+	case imagePaletteMode: // Colors extracted from an image
+		youngBase = struct{ r, g, b uint8 }{imported[0].R, imported[0].G, imported[0].B}
+		matureBase = struct{ r, g, b uint8 }{imported[1].R, imported[1].G, imported[1].B}
+		oldBase = struct{ r, g, b uint8 }{imported[2].R, imported[2].G, imported[2].B}
+		// Glow in the lightest dominant color, halfway to white so it still shines
+		p.bloomIntensity = 0.35
+		p.glow = color.RGBA{255 - (255-imported[0].R)/2, 255 - (255-imported[0].G)/2, 255 - (255-imported[0].B)/2, 255}
 	default: // Original mode
 		youngBase = struct{ r, g, b uint8 }{0, 200, 0}
 		matureBase = struct{ r, g, b uint8 }{200, 200, 0}
 		oldBase = struct{ r, g, b uint8 }{255, 0, 0}
 	}
-	
+
 	for i := range p.young {
 		intensity := float32(0.5 + float32(i)*0.1)
 		r := uint8(float32(youngBase.r) * intensity)
@@ -151,7 +224,7 @@ func generateDynamicPalette(rng *rand.Rand, cycle float64, mode int) ColorPalett
 		b := uint8(float32(youngBase.b) * intensity)
 		p.young[i] = randomColor(rng, r, g, b, 30)
 	}
-	
+
 	for i := range p.mature {
 		factor := float32(i) / float32(len(p.mature))
 		r := uint8(float32(matureBase.r) * (0.7 + factor*0.3))
@@ -159,7 +232,7 @@ func generateDynamicPalette(rng *rand.Rand, cycle float64, mode int) ColorPalett
 		b := uint8(float32(matureBase.b) * (0.5 + factor*0.5))
 		p.mature[i] = randomColor(rng, r, g, b, 25)
 	}
-	
+
 	for i := range p.old {
 		factor := 1.0 - float32(i)/float32(len(p.old))*0.6
 		r := uint8(float32(oldBase.r) * factor)
@@ -167,53 +240,18 @@ func generateDynamicPalette(rng *rand.Rand, cycle float64, mode int) ColorPalett
 		b := uint8(float32(oldBase.b) * factor)
 		p.old[i] = randomColor(rng, r, g, b, 20)
 	}
-	
+
+	// Follow the app theme: dead color and light-background variants
+	p.adaptToCanvas()
+	p.fillColors()
+
 	return p
 }
 
-func calculateStats(grid [][]Cell, generation int, gridSize int) Stats {
-	var s Stats
-	s.generation = generation
-	totalCells := 0
-	totalAge := 0
-	
-	// Initialize age histogram
-	for i := range s.ageHistogram {
-		s.ageHistogram[i] = 0
-	}
-	
-	for y := range grid {
-		for x := range grid[y] {
-			val := grid[y][x].val
-			if val > 0 {
-				totalCells++
-				totalAge += val
-				idx := val - 1
-				if idx >= len(s.ageHistogram) {
-					idx = len(s.ageHistogram) - 1
-				}
-				s.ageHistogram[idx]++
-			}
-		}
-	}
-	
-	s.population = totalCells
-	s.density = float64(totalCells) / float64(gridSize*gridSize)
-	
-	if totalCells > 0 {
-		s.avgAge = float64(totalAge) / float64(totalCells)
-	}
-	
-	// Entropy calculation
-	totalSize := float64(gridSize * gridSize)
-	if s.population > 0 {
-		p := float64(s.population) / totalSize
-		if p > 0 && p < 1 {
-			s.entropy = -p*math.Log2(p) - (1-p)*math.Log2(1-p)
-		}
-	}
-	
-	return s
+// runningStatus is the status line of a running simulation.
+func runningStatus(s Stats, totalCells int) string {
+	return fmt.Sprintf(lang.L("Gen %d - Pop %d/%d (%.1f%%) - Avg age: %.1f - Entropy: %.3f"),
+		s.generation, s.population, totalCells, s.density*100, s.avgAge, s.entropy)
 }
 
 func addEvent(state *SimulationState, eventType, message string) {
@@ -222,157 +260,537 @@ func addEvent(state *SimulationState, eventType, message string) {
 		eventType:  eventType,
 		message:    message,
 	}
-	state.events = append(state.events, event)
-	if len(state.events) > 10 {
-		state.events = state.events[1:]
+	state.events.add(event)
+	state.bus.eventLogged.publish(event)
+}
+
+// BloomSettings controls the glow: pixels brighter than threshold (luma,
+// 0-255) are blurred with a Gaussian of the given radius and added back,
+// scaled by intensity times the palette's own bloom strength.
+type BloomSettings struct {
+	radius    int
+	intensity float64
+	threshold int
+}
+
+var defaultBloom = BloomSettings{radius: 4, intensity: 1.5, threshold: 96}
+
+// gaussianKernel returns normalized weights for offsets -radius..radius.
+func gaussianKernel(radius int) []float32 {
+	sigma := float64(radius) / 2
+	if sigma < 0.5 {
+		sigma = 0.5
+	}
+	k := make([]float32, 2*radius+1)
+	var sum float32
+	for i := range k {
+		d := float64(i - radius)
+		k[i] = float32(math.Exp(-d * d / (2 * sigma * sigma)))
+		sum += k[i]
+	}
+	for i := range k {
+		k[i] /= sum
+	}
+	return k
+}
+
+// bloomBuffers recycles the float planes of applyBloom between frames.
+var bloomBuffers sync.Pool
+
+// getBloomBuffer returns a zeroed plane of n floats.
+func getBloomBuffer(n int) []float32 {
+	if b, ok := bloomBuffers.Get().(*[]float32); ok && cap(*b) >= n {
+		buf := (*b)[:n]
+		clear(buf)
+		return buf
 	}
+	return make([]float32, n)
+}
+
+func putBloomBuffer(b []float32) {
+	bloomBuffers.Put(&b)
 }
 
-func applyBloom(img *image.RGBA, intensity float64) {
+// applyBloom adds a glow around bright cells with a two-pass separable
+// Gaussian blur on img.Pix. The glow is scaled per channel by the palette's
+// tint, so palettes can bloom warm or cool; empty cells never bloom.
+func applyBloom(img *image.RGBA, palette ColorPalette, s BloomSettings) {
 	bounds := img.Bounds()
-	tempImg := image.NewRGBA(bounds)
-	
-	// Copy the image
-	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			tempImg.Set(x, y, img.At(x, y))
-		}
-	}
-	
-	// Apply simple blur for bloom effect
-	for y := bounds.Min.Y + 1; y < bounds.Max.Y-1; y++ {
-		for x := bounds.Min.X + 1; x < bounds.Max.X-1; x++ {
-			r, g, b, a := tempImg.At(x, y).RGBA()
-			if r > 0 || g > 0 || b > 0 {
-				// Add neighboring pixels with attenuation
-				for dy := -1; dy <= 1; dy++ {
-					for dx := -1; dx <= 1; dx++ {
-						if dx == 0 && dy == 0 {
-							continue
-						}
-						nr, ng, nb, _ := img.At(x+dx, y+dy).RGBA()
-						r += uint32(float64(nr) * intensity * 0.05)
-						g += uint32(float64(ng) * intensity * 0.05)
-						b += uint32(float64(nb) * intensity * 0.05)
-					}
-				}
-				// Clamp
-				if r > 65535 {
-					r = 65535
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 || s.radius < 1 {
+		return
+	}
+	strength := float32(palette.bloomIntensity * s.intensity)
+	tint := [3]float32{
+		strength * float32(palette.glow.R) / 255,
+		strength * float32(palette.glow.G) / 255,
+		strength * float32(palette.glow.B) / 255,
+	}
+	dr, dg, db, _ := palette.dead.RGBA()
+	dead := [3]uint8{uint8(dr >> 8), uint8(dg >> 8), uint8(db >> 8)}
+
+	// Bright pass
+	bright := getBloomBuffer(w * h * 3)
+	defer putBloomBuffer(bright)
+	for y := 0; y < h; y++ {
+		row := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+y):]
+		for x := 0; x < w; x++ {
+			px := row[x*4 : x*4+3]
+			if px[0] == dead[0] && px[1] == dead[1] && px[2] == dead[2] {
+				continue
+			}
+			if (299*int(px[0])+587*int(px[1])+114*int(px[2]))/1000 < s.threshold {
+				continue
+			}
+			i := (y*w + x) * 3
+			bright[i] = float32(px[0])
+			bright[i+1] = float32(px[1])
+			bright[i+2] = float32(px[2])
+		}
+	}
+
+	kernel := gaussianKernel(s.radius)
+	blurred := getBloomBuffer(w * h * 3)
+	defer putBloomBuffer(blurred)
+
+	// Horizontal pass, clamping at the edges
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var acc [3]float32
+			for k, weight := range kernel {
+				sx := x + k - s.radius
+				if sx < 0 {
+					sx = 0
+				} else if sx >= w {
+					sx = w - 1
 				}
-				if g > 65535 {
-					g = 65535
+				i := (y*w + sx) * 3
+				acc[0] += bright[i] * weight
+				acc[1] += bright[i+1] * weight
+				acc[2] += bright[i+2] * weight
+			}
+			i := (y*w + x) * 3
+			blurred[i], blurred[i+1], blurred[i+2] = acc[0], acc[1], acc[2]
+		}
+	}
+
+	// Vertical pass, composited additively onto the image
+	for y := 0; y < h; y++ {
+		row := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+y):]
+		for x := 0; x < w; x++ {
+			var acc [3]float32
+			for k, weight := range kernel {
+				sy := y + k - s.radius
+				if sy < 0 {
+					sy = 0
+				} else if sy >= h {
+					sy = h - 1
 				}
-				if b > 65535 {
-					b = 65535
+				i := (sy*w + x) * 3
+				acc[0] += blurred[i] * weight
+				acc[1] += blurred[i+1] * weight
+				acc[2] += blurred[i+2] * weight
+			}
+			for c := 0; c < 3; c++ {
+				v := float32(row[x*4+c]) + acc[c]*tint[c]
+				if v > 255 {
+					v = 255
 				}
-				img.Set(x, y, color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)})
+				row[x*4+c] = uint8(v)
 			}
 		}
 	}
 }
 
 func main() {
-	a := app.New()
-	w := a.NewWindow("Living Numbers Game - Experimental Laboratory")
+	a := app.NewWithID(appID)
+
+	// Defaults come from config.toml, then from the last session's
+	// settings, then from the command line
+	var userCfg userConfig
+	cfgPath, err := configPath()
+	if err == nil {
+		userCfg, err = loadUserConfig(cfgPath)
+	}
+	cfgErr := err
+	if cfgErr != nil {
+		userCfg = userConfig{}
+	}
+	userCfg = restoreLastUsed(a.Preferences(), userCfg)
+	opts, err := parseFlags(os.Args[1:], userCfg.launchDefaults(defaultLaunchOptions), os.Stderr)
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	logOutput, err := setupLogging(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Cannot open the log file:", err)
+		os.Exit(1)
+	}
+	defer logOutput.Close()
+	if opts.batch > 0 {
+		if err := runBatchMode(opts); err != nil {
+			fmt.Fprintln(os.Stderr, "Batch failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if opts.manifest != "" {
+		if err := runManifestMode(opts.manifest); err != nil {
+			fmt.Fprintln(os.Stderr, "Manifest failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if opts.statsOut != "" {
+		if statsOutput, err = openStatsStream(opts.statsOut); err != nil {
+			fmt.Fprintln(os.Stderr, "Cannot open the stats output:", err)
+			os.Exit(1)
+		}
+		defer statsOutput.Close()
+	}
+	if opts.osc != "" {
+		if oscOutput, err = dialOSC(opts.osc); err != nil {
+			fmt.Fprintln(os.Stderr, "Cannot open the OSC output:", err)
+			os.Exit(1)
+		}
+		defer oscOutput.Close()
+	}
+	if cfgErr != nil {
+		slog.Warn("config file ignored", "path", cfgPath, "err", cfgErr)
+	}
+	loadTranslations()
+
+	w := a.NewWindow(lang.L("Living Numbers Game - Experimental Laboratory"))
 
+	// Shared by the UI and the simulation goroutine, and not safe for
+	// concurrent use: draw from it with state.mu held once the window is up
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	
+
+	mobile := fyne.CurrentDevice().IsMobile()
+	browser := fyne.CurrentDevice().IsBrowser()
+	uiTheme := &appTheme{mode: themeModes[0], touch: mobile}
+	a.Settings().SetTheme(uiTheme)
+	syncCanvasTheme(a, uiTheme)
+
+	// Older events spill to the session directory; without one they are only counted
+	session, _ := openSessionStore()
+
+	// Background goroutines end when the main window closes
+	life := newLifetime()
+
+	// Hidden developer option: pprof server and profile capture (Ctrl+Shift+P)
+	pprofAddr, err := startPprofServer(life)
+	if err != nil {
+		slog.Error("pprof server not started", "err", err)
+	}
+	profileDir := os.TempDir()
+	if session != nil {
+		profileDir = session.dir
+	}
+
 	state := &SimulationState{
-		growthRate:     0.05,
-		mutationChance: 0.01,
-		paletteMode:    0,
-		bloomEffect:    true,
-		events:         make([]Event, 0),
-		isPaused:       false,
-		isStarted:      false,
-		cellSize:       5,
-		gridSize:       displaySize / 5,
-		speed:          50,
-	}
-	
-	palette := generateDynamicPalette(rng, 0, state.paletteMode)
+		runModel: runModel{
+			growthRate:     opts.growthRate,
+			mutationChance: opts.mutationChance,
+			isPaused:       false,
+			isStarted:      false,
+			cellSize:       opts.cellSize,
+			gridSize:       displaySize / opts.cellSize,
+			speed:          opts.speed,
+			ruleFamily:     ruleFamilies[0].name,
+			ruleParams:     defaultRuleParams(),
+			ageCurves:      defaultAgeCurves,
+			birthCurve:     defaultBirthCurve,
+			infectionRate:  defaultInfectionRate,
+			infectionSpan:  defaultInfectionSpan,
+			novaRadius:     defaultNovaRadius,
+			climates:       defaultClimates,
+			catastrophes:   Catastrophes{kind: catastropheKinds[0]},
+		},
+		displaySettings: displaySettings{
+			paletteMode:  0,
+			paletteSpeed: 1,
+			effects:      newEffectChain(defaultBloom),
+			view:         viewport{side: displaySize / opts.cellSize},
+		},
+		events: newEventHistory(opts.eventCapacity, session),
+	}
+	userCfg.applyEffects(state.effects)
+
+	// newSeed draws a seed from rng for the windows and tabs seeding their
+	// own runs; the caller must not hold state.mu
+	newSeed := func() int64 {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		return rng.Int63()
+	}
+
+	// Events and statistics go to the log and the stats and OSC outputs
+	state.bus.eventLogged.subscribe(logEvent)
+	state.bus.eventLogged.subscribe(statsOutput.note)
+	state.bus.eventLogged.subscribe(oscOutput.note)
+	state.bus.statsUpdated.subscribe(func(u statsUpdate) {
+		statsOutput.write(u.stats)
+		oscOutput.write(u.stats)
+	})
 
-	grid := make([][]Cell, state.gridSize)
-	for i := range grid {
-		grid[i] = make([]Cell, state.gridSize)
+	// Undo and redo of the user's changes, see commandStack
+	commands := newCommandStack()
+
+	// Checkpoints let a run be resumed after a crash
+	checkpoints, err := openCheckpointStore()
+	if err != nil {
+		slog.Warn("checkpoints disabled", "err", err)
+	}
+	var recovered *Checkpoint
+	if checkpoints != nil {
+		crashed, err := checkpoints.begin()
+		if err != nil {
+			slog.Warn("cannot mark the session as running", "err", err)
+		}
+		if crashed {
+			if recovered, err = checkpoints.load(); err != nil {
+				slog.Warn("checkpoint ignored", "err", err)
+			}
+		}
 	}
 
+	palette := generateDynamicPalette(rng, 0, state.paletteMode)
+
+	sim := newSimulation(state.gridSize, rng.Int63())
+
 	// Empty grid at startup - cells appear on Start click
 	// (no initialization here)
 
 	img := image.NewRGBA(image.Rect(0, 0, displaySize, displaySize))
-	drawGridDynamic(grid, img, palette, state.cellSize, state.gridSize)
-	
+	drawGridDynamic(sim.grid, img, palette, state.cellSize, state.gridSize)
+
+	// The grid image is scaled to the available space, keeping cells crisp
 	canvasImg := canvas.NewImageFromImage(img)
-	canvasImg.FillMode = canvas.ImageFillOriginal
+	canvasImg.FillMode = canvas.ImageFillContain
+	canvasImg.ScaleMode = canvas.ImageScalePixels
 	canvasImg.SetMinSize(fyne.NewSize(float32(displaySize), float32(displaySize)))
 
-	// Control interface
-	statusLabel := widget.NewLabel("Empty grid - Press Start to begin")
-	
-	growthLabel := widget.NewLabel(fmt.Sprintf("Growth rate: %.2f", state.growthRate))
-	growthSlider := widget.NewSlider(0.05, 0.5)
+	// Control interface, bound to the model through the view-model
+	vm := newViewModel(state)
+	vm.status.Set(lang.L("Empty grid - Press Start to begin"))
+	statusLabel := widget.NewLabelWithData(vm.status)
+
+	// Background behind the status bar, flashed when a trigger fires
+	statusFlash := canvas.NewRectangle(color.Transparent)
+	flashStatus := func() {
+		canvas.NewColorRGBAAnimation(color.RGBA{220, 40, 40, 255}, color.RGBA{220, 40, 40, 0}, 800*time.Millisecond, func(c color.Color) {
+			statusFlash.FillColor = c
+			statusFlash.Refresh()
+		}).Start()
+	}
+
+	growthLabel := widget.NewLabelWithData(vm.growthRate.text(func(v float64) string {
+		return fmt.Sprintf(lang.L("Growth rate: %.2f"), v)
+	}))
+	growthSlider := widget.NewSliderWithData(0.05, 0.5, vm.growthRate)
 	growthSlider.Step = 0.01
-	growthSlider.Value = state.growthRate
-	growthSlider.OnChanged = func(v float64) {
-		state.growthRate = v
-		growthLabel.SetText(fmt.Sprintf("Growth rate: %.2f", v))
-	}
-	
-	mutationLabel := widget.NewLabel(fmt.Sprintf("Mutation: %.3f", state.mutationChance))
-	mutationSlider := widget.NewSlider(0, 0.1)
+	var tutorial *Tutorial
+	vm.growthRate.onChange(func(v float64) {
+		logParam("growth_rate", v)
+		state.mu.Lock()
+		started := state.isStarted
+		state.mu.Unlock()
+		if tutorial != nil && !started {
+			tutorial.advance("growth")
+		}
+	})
+
+	mutationLabel := widget.NewLabelWithData(vm.mutationChance.text(func(v float64) string {
+		return fmt.Sprintf(lang.L("Mutation: %.3f"), v)
+	}))
+	mutationSlider := widget.NewSliderWithData(0, 0.1, vm.mutationChance)
 	mutationSlider.Step = 0.001
-	mutationSlider.Value = state.mutationChance
-	mutationSlider.OnChanged = func(v float64) {
-		state.mutationChance = v
-		mutationLabel.SetText(fmt.Sprintf("Mutation: %.3f", v))
+	vm.mutationChance.onChange(func(v float64) { logParam("mutation_chance", v) })
+
+	// Noise of the survival decisions, for annealing-style experiments
+	temperatureLabel := widget.NewLabelWithData(vm.temperature.text(func(v float64) string {
+		return fmt.Sprintf(lang.L("Temperature: %.1f"), v)
+	}))
+	temperatureSlider := widget.NewSliderWithData(0, 5, vm.temperature)
+	temperatureSlider.Step = 0.1
+	vm.temperature.onChange(func(v float64) { logParam("temperature", v) })
+
+	// Seasons swinging the growth rate and ageing threshold, 0 turns them off
+	seasonText := func(period int) string {
+		if period == 0 {
+			return lang.L("🌦 Seasons: off")
+		}
+		return fmt.Sprintf(lang.L("🌦 Seasons: %d gens/cycle"), period)
 	}
-	
+	seasonLabel := widget.NewLabelWithData(vm.seasonPeriod.text(func(v float64) string {
+		return seasonText(int(v))
+	}))
+	seasonSlider := widget.NewSliderWithData(0, 2000, vm.seasonPeriod)
+	seasonSlider.Step = 20
+	vm.seasonPeriod.onChange(func(v float64) { logParam("season_period", int(v)) })
+
+	// Drift biasing births one way, like wind or gravity
+	driftLabel := widget.NewLabelWithData(vm.driftStrength.text(func(v float64) string {
+		return fmt.Sprintf(lang.L("💨 Drift: %.2f"), v)
+	}))
+	driftSlider := widget.NewSliderWithData(0, 1, vm.driftStrength)
+	driftSlider.Step = 0.05
+	vm.driftStrength.onChange(func(v float64) { logParam("drift_strength", v) })
+	driftSelect := widget.NewSelect(driftDirections, func(d string) {
+		state.mu.Lock()
+		state.driftDirection = slices.Index(driftDirections, d)
+		state.mu.Unlock()
+		logParam("drift_direction", d)
+	})
+	driftSelect.SetSelected(driftDirections[state.driftDirection])
+
+	// Movement phase turning colonies into migrating swarms
+	movementLabel := widget.NewLabelWithData(vm.movementRate.text(func(v float64) string {
+		return fmt.Sprintf(lang.L("🏃 Movement: %.2f"), v)
+	}))
+	movementSlider := widget.NewSliderWithData(0, 1, vm.movementRate)
+	movementSlider.Step = 0.05
+	vm.movementRate.onChange(func(v float64) { logParam("movement_rate", v) })
+
+	// Immigration along one edge, so extinction is never final
+	immigrationLabel := widget.NewLabelWithData(vm.immigration.text(func(v float64) string {
+		return fmt.Sprintf(lang.L("🧳 Immigration: %.2f"), v)
+	}))
+	immigrationSlider := widget.NewSliderWithData(0, 0.2, vm.immigration)
+	immigrationSlider.Step = 0.01
+	vm.immigration.onChange(func(v float64) { logParam("immigration_rate", v) })
+	immigrationSelect := widget.NewSelect(immigrationEdges, func(e string) {
+		state.mu.Lock()
+		state.entryEdge = slices.Index(immigrationEdges, e)
+		state.mu.Unlock()
+		logParam("immigration_edge", e)
+	})
+	immigrationSelect.SetSelected(immigrationEdges[state.entryEdge])
+
+	// Epidemics started by the patient zero button
+	contagionLabel := widget.NewLabelWithData(vm.infectionRate.text(func(v float64) string {
+		return fmt.Sprintf(lang.L("🦠 Contagion: %.2f"), v)
+	}))
+	contagionSlider := widget.NewSliderWithData(0.05, 1, vm.infectionRate)
+	contagionSlider.Step = 0.05
+	vm.infectionRate.onChange(func(v float64) { logParam("infection_rate", v) })
+	lethalityLabel := widget.NewLabelWithData(vm.infectionSpan.text(func(v float64) string {
+		return fmt.Sprintf(lang.L("Kills after: %d gens"), int(v))
+	}))
+	lethalitySlider := widget.NewSliderWithData(1, 50, vm.infectionSpan)
+	vm.infectionSpan.onChange(func(v float64) { logParam("infection_span", int(v)) })
+
 	maxPop := state.gridSize * state.gridSize
-	pixelLabel := widget.NewLabel(fmt.Sprintf("Pixel size: %dpx (Max pop: %d)", state.cellSize, maxPop))
+	pixelLabel := widget.NewLabel(fmt.Sprintf(lang.L("Pixel size: %dpx (Max pop: %d)"), state.cellSize, maxPop))
 	pixelSlider := widget.NewSlider(2, 8)
 	pixelSlider.Step = 1
 	pixelSlider.Value = float64(state.cellSize)
-	
-	// Callback for pixel slider - recreates grid and image
-	pixelSlider.OnChanged = func(v float64) {
-		oldCellSize := state.cellSize
-		state.cellSize = int(v)
-		state.gridSize = displaySize / state.cellSize
+
+	// Recreates the grid and the image for the cell size and grid size;
+	// the caller holds state.mu
+	resizeGrid := func() {
+		oldGridSize := state.gridSize
+		state.gridSize = gridSide(state.gridCells, state.cellSize)
 		maxPop := state.gridSize * state.gridSize
-		pixelLabel.SetText(fmt.Sprintf("Pixel size: %dpx (Max pop: %d)", state.cellSize, maxPop))
-		
+		pixelLabel.SetText(fmt.Sprintf(lang.L("Pixel size: %dpx (Max pop: %d)"), state.cellSize, maxPop))
+
 		// Recreate grid with new size
-		grid = make([][]Cell, state.gridSize)
-		for i := range grid {
-			grid[i] = make([]Cell, state.gridSize)
-		}
-		
-		// Recreate image
-		img = image.NewRGBA(image.Rect(0, 0, displaySize, displaySize))
-		drawGridDynamic(grid, img, palette, state.cellSize, state.gridSize)
+		sim.resize(state.gridSize)
+		state.view.fit(state.gridSize, state.cellSize)
+		state.view.center(state.gridSize)
+
+		// Redraw the image, clearing the border the new grid may not cover
+		clear(img.Pix)
+		if state.view.whole(state.gridSize) {
+			drawGridDynamic(sim.grid, img, palette, state.cellSize, state.gridSize)
+		} else {
+			drawViewport(img, sim, palette, Colorizer{}, state.cellSize, state.view)
+		}
+		if state.gridLines {
+			drawGridLines(img, state.cellSize, state.view.side, gridLineColor())
+		}
 		canvasImg.Image = img
 		canvasImg.Refresh()
-		
+
 		// Log event if significant change
-		if oldCellSize != state.cellSize {
+		if oldGridSize != state.gridSize {
 			addEvent(state, "CONFIG", fmt.Sprintf("Grid resized: %dx%d cells (%d max)", state.gridSize, state.gridSize, maxPop))
 		}
 	}
-	
-	speedLabel := widget.NewLabel(fmt.Sprintf("Speed: %dms/gen", state.speed))
-	speedSlider := widget.NewSlider(10, 200)
-	speedSlider.Step = 10
-	speedSlider.Value = float64(state.speed)
-	speedSlider.OnChanged = func(v float64) {
-		state.speed = int(v)
-		speedLabel.SetText(fmt.Sprintf("Speed: %dms/gen", state.speed))
+
+	// Callback for pixel slider - recreates grid and image
+	pixelSlider.OnChanged = func(v float64) {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		state.cellSize = int(v)
+		logParam("cell_size", state.cellSize)
+		resizeGrid()
+	}
+
+	// Grids larger than the display are drawn through a window panned with
+	// shift-drags
+	gridSizeSelect := widget.NewSelect(localized(gridSizeNames()), func(shown string) {
+		i := slices.Index(gridSizeNames(), unlocalized(gridSizeNames(), shown))
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		state.gridCells = largeGridSizes[i]
+		logParam("grid_cells", state.gridCells)
+		resizeGrid()
+	})
+	gridSizeSelect.SetSelected(lang.L(gridSizeNames()[0]))
+
+	speedLabel := widget.NewLabelWithData(vm.speed.text(func(v float64) string {
+		return fmt.Sprintf(lang.L("Speed: %dms/gen"), int(v))
+	}))
+	speedSlider := widget.NewSliderWithData(10, 200, vm.speed)
+	speedSlider.Step = 5
+	// Wakes a running simulation so a new speed applies to the next generation
+	speedChanged := make(chan struct{}, 1)
+	vm.speed.onChange(func(v float64) {
+		logParam("speed", int(v))
+		select {
+		case speedChanged <- struct{}{}:
+		default:
+		}
+	})
+
+	speedModeSelect := widget.NewSelect(localized(speedModes), func(shown string) {
+		mode := slices.Index(speedModes, unlocalized(speedModes, shown))
+		state.mu.Lock()
+		state.speedMode = mode
+		state.mu.Unlock()
+		logParam("speed_mode", speedModes[mode])
+		if mode == speedFixed {
+			speedSlider.Enable()
+		} else {
+			speedSlider.Disable()
+		}
+		select {
+		case speedChanged <- struct{}{}:
+		default:
+		}
+	})
+	speedModeSelect.SetSelected(lang.L(speedModes[speedFixed]))
+
+	// Langton's ants walking the grid, added or removed at once
+	antsLabel := widget.NewLabel(fmt.Sprintf(lang.L("🐜 Ants: %d"), 0))
+	antsSlider := widget.NewSlider(0, maxAnts)
+	antsSlider.OnChanged = func(v float64) {
+		state.mu.Lock()
+		sim.setAnts(int(v))
+		state.mu.Unlock()
+		logParam("ants", int(v))
+		antsLabel.SetText(fmt.Sprintf(lang.L("🐜 Ants: %d"), int(v)))
 	}
 
 	// Interactive color legend - BEFORE paletteSelect
-	legendLabel := widget.NewLabel("🎨 Legend:")
-	
+	legendLabel := widget.NewLabel(lang.L("🎨 Legend:"))
+
 	// Create smaller color squares
 	deadRect := canvas.NewRectangle(palette.dead)
 	deadRect.SetMinSize(fyne.NewSize(12, 12))
@@ -382,26 +800,26 @@ func main() {
 	matureRect.SetMinSize(fyne.NewSize(12, 12))
 	oldRect := canvas.NewRectangle(palette.old[15])
 	oldRect.SetMinSize(fyne.NewSize(12, 12))
-	
+
 	// Compact meaning labels
-	deadLabel := widget.NewLabel("Dead (0)")
-	youngLabel := widget.NewLabel("Young (1-4)")
-	matureLabel := widget.NewLabel("Mature (5-19)")
-	oldLabel := widget.NewLabel("Old (20-49)")
-	
+	deadLabel := widget.NewLabel(lang.L("Dead (0)"))
+	youngLabel := widget.NewLabel(lang.L("Young (1-4)"))
+	matureLabel := widget.NewLabel(lang.L("Mature (5-19)"))
+	oldLabel := widget.NewLabel(lang.L("Old (20-49)"))
+
 	// Organize in lines
 	legendRow1 := container.NewHBox(deadRect, deadLabel)
 	legendRow2 := container.NewHBox(youngRect, youngLabel)
 	legendRow3 := container.NewHBox(matureRect, matureLabel)
 	legendRow4 := container.NewHBox(oldRect, oldLabel)
-	
+
 	legendBox := container.NewVBox(
 		legendRow1,
 		legendRow2,
 		legendRow3,
 		legendRow4,
 	)
-	
+
 	// Function to update legend colors
 	updateLegendColors := func() {
 		deadRect.FillColor = palette.dead
@@ -413,469 +831,2119 @@ func main() {
 		matureRect.Refresh()
 		oldRect.Refresh()
 	}
-	
+
 	// paletteSelect AFTER updateLegendColors declaration
-	paletteSelect := widget.NewSelect([]string{"Original", "Rainbow", "Ocean", "Fire"}, func(s string) {
-		switch s {
-		case "Rainbow":
-			state.paletteMode = 0
-		case "Ocean":
-			state.paletteMode = 1
-		case "Fire":
-			state.paletteMode = 2
-		default:
-			state.paletteMode = 3
-		}
+	paletteSelect := widget.NewSelect(localized(paletteNames), func(shown string) {
+		s := unlocalized(paletteNames, shown)
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		state.paletteMode = slices.Index(paletteModeNames, s)
+		logParam("palette", s)
 		// Update palette and legend
 		palette = generateDynamicPalette(rng, 0, state.paletteMode)
 		updateLegendColors()
 		if !state.isStarted {
-			drawGrid(grid, img, palette)
+			drawGrid(sim.grid, img, palette)
 			canvasImg.Refresh()
 		}
 	})
-	paletteSelect.SetSelected("Original")
-	
-	bloomCheck := widget.NewCheck("Bloom Effect", func(checked bool) {
-		state.bloomEffect = checked
-	})
-	bloomCheck.Checked = true
-	
-	startButton := widget.NewButton("▶ Start", func() {})
-	pauseButton := widget.NewButton("⏸ Pause", func() {})
-	pauseButton.Disable()
-	
-	supernovaButton := widget.NewButton("💥 Supernova", func() {})
-	supernovaButton.Disable()
-	
-	helpButton := widget.NewButton("❓ How it works?", func() {})
-	
-	statsLabel := widget.NewLabel("Stats: --")
-	eventLog := widget.NewLabel("Log: Waiting for start...")
-	eventLog.Wrapping = fyne.TextWrapWord
-	
-	controlsLeft := container.NewVBox(
-		widget.NewLabel("🎮 Controls"),
-		widget.NewSeparator(),
-		growthLabel,
-		growthSlider,
-		mutationLabel,
-		mutationSlider,
-		pixelLabel,
-		pixelSlider,
-		speedLabel,
-		speedSlider,
-		paletteSelect,
-		bloomCheck,
-		container.NewGridWithColumns(2, startButton, pauseButton),
-		supernovaButton,
-		helpButton,
-	)
-	
-	controlsRight := container.NewVBox(
-		widget.NewLabel("📊 Statistics"),
-		widget.NewSeparator(),
-		statsLabel,
-		widget.NewSeparator(),
-		widget.NewLabel("📜 Event Log"),
-		eventLog,
-		widget.NewSeparator(),
-		legendLabel,
-		legendBox,
-	)
-	
-
-	controls := container.NewGridWithColumns(2, controlsLeft, controlsRight)
-	
-	mainContainer := container.NewBorder(
-		nil,
-		container.NewVBox(statusLabel, controls),
-		nil,
-		nil,
-		canvasImg,
-	)
-
-	w.SetContent(mainContainer)
-	w.Resize(fyne.NewSize(float32(displaySize), float32(displaySize+280)))
-	w.CenterOnScreen()
-	// Allow free window resizing
-
-	driver := a.Driver()
-	
-	// Help button - Display explanation
-	helpButton.OnTapped = func() {
-		helpText := `
-LIVING NUMBERS GAME - Quick Guide
-
-WHAT IS IT?
-A simulation where cells are born, grow old, and compete for space. Each cell has an "age" from 1 to 50, represented by different colors.
-
-WHAT HAPPENS AT START?
+	if slices.Contains(paletteNames, userCfg.Palette) {
+		paletteSelect.SetSelected(lang.L(userCfg.Palette))
+	} else {
+		paletteSelect.SetSelected(lang.L("Original"))
+	}
 
-* Black screen = Empty grid (all cells are dead)
-* Press Start -> Random cells appear (200-600 cells)
-* Each new simulation has different starting positions
-* This randomness creates unique evolution patterns
+	// Colors of the Image palette, remembered between sessions
+	if bases, ok := parsePaletteColors(a.Preferences().String(prefImagePalette)); ok {
+		imagePalette.Store(bases)
+	}
+	importPaletteButton := widget.NewButton(lang.L("🖼 Import"), func() {
+		showImportPaletteDialog(w, func(bases [3]color.RGBA) {
+			a.Preferences().SetString(prefImagePalette, formatPaletteColors(bases))
+			if !paletteSelect.Disabled() {
+				paletteSelect.SetSelected(lang.L("Image"))
+			}
+		})
+	})
+	if browser {
+		importPaletteButton.Hide()
+	}
 
-HOW DOES IT EVOLVE?
+	// Palette animation: how fast the colors cycle, or held still for
+	// consistent screenshots
+	paletteSpeedLabel := widget.NewLabelWithData(vm.paletteSpeed.text(func(v float64) string {
+		return fmt.Sprintf(lang.L("🎨 Palette speed: ×%.1f"), v)
+	}))
+	paletteSpeedSlider := widget.NewSliderWithData(0, 3, vm.paletteSpeed)
+	paletteSpeedSlider.Step = 0.1
+	vm.paletteSpeed.onChange(func(v float64) { logParam("palette_speed", v) })
+	freezePaletteCheck := widget.NewCheck(lang.L("Freeze palette"), func(checked bool) {
+		state.mu.Lock()
+		state.paletteFrozen = checked
+		state.mu.Unlock()
+		logParam("palette_frozen", checked)
+	})
 
-Every generation (20x per second), each cell follows simple rules:
+	effectsButton := widget.NewButton(lang.L("✨ Effects"), func() {
+		showEffectsDialog(w, state)
+	})
 
-1. BIRTH (empty cell becomes alive):
-   - If neighbors are present, chance of birth
-   - Growth Rate controls this probability
-   - Higher growth = faster colonization
+	// Sliders of the selected family's parameters, tuning a running rule at once
+	ruleParamsBox := container.NewVBox()
+	showRuleParams := func(family string) {
+		ruleParamsBox.RemoveAll()
+		for i, p := range ruleFamilyParams(family) {
+			state.mu.Lock()
+			value := state.ruleParams[family][i]
+			state.mu.Unlock()
+			label := widget.NewLabel(fmt.Sprintf("%s: %s", lang.L(p.name), p.format(value)))
+			slider := widget.NewSlider(p.min, p.max)
+			slider.Step = p.step
+			slider.Value = value
+			slider.OnChanged = func(v float64) {
+				state.mu.Lock()
+				state.ruleParams[family][i] = v
+				if t, ok := sim.rule.(tunable); ok && ruleName(sim.rule) == family {
+					t.tune(p.name, v)
+				}
+				state.mu.Unlock()
+				logParam(family+"/"+p.name, v)
+				label.SetText(fmt.Sprintf("%s: %s", lang.L(p.name), p.format(v)))
+			}
+			commands.track(family+" "+p.name, slider)
+			ruleParamsBox.Add(label)
+			ruleParamsBox.Add(slider)
+		}
+	}
 
-2. SURVIVAL (cell stays alive):
-   - Needs at least 3 neighbors
-   - Too isolated (<3) -> Dies (loneliness)
-   - Survives in community
+	// Rule family: the built-in ages or an alternative engine, from the next run
+	ruleSelect := widget.NewSelect(localized(ruleFamilyNames()), func(shown string) {
+		s := unlocalized(ruleFamilyNames(), shown)
+		state.mu.Lock()
+		state.ruleFamily = s
+		state.mu.Unlock()
+		logParam("rule_family", s)
+		showRuleParams(s)
+	})
+	ruleSelect.SetSelected(lang.L(state.ruleFamily))
+
+	symmetrySelect := widget.NewSelect(localized(symmetryNames), func(shown string) {
+		s := unlocalized(symmetryNames, shown)
+		state.mu.Lock()
+		state.symmetry = symmetryFromName(s)
+		state.mu.Unlock()
+		logParam("symmetry", s)
+	})
+	symmetrySelect.SetSelected(lang.L(state.symmetry.String()))
 
-3. AGING (cell gets older):
-   - When crowded (>20 neighbors) -> Age +1
-   - Color changes: Green->Yellow->Red
-   - At age 50 -> Resets to age 1 (rejuvenation)
+	// Theme changes recolor the canvas; a running simulation picks it up on the next frame
+	applyTheme := func() {
+		a.Settings().SetTheme(uiTheme)
+	}
+	recolorCanvas := func() {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		palette = generateDynamicPalette(rng, 0, state.paletteMode)
+		updateLegendColors()
+		if !state.isStarted {
+			drawGridDynamic(sim.grid, img, palette, state.cellSize, state.gridSize)
+			canvasImg.Refresh()
+		}
+	}
+	a.Settings().AddListener(func(fyne.Settings) {
+		syncCanvasTheme(a, uiTheme)
+		recolorCanvas()
+	})
+	themeSelect := widget.NewSelect(localized(themeModes), func(shown string) {
+		uiTheme.mode = unlocalized(themeModes, shown)
+		applyTheme()
+	})
+	themeSelect.SetSelected(lang.L(uiTheme.mode))
+
+	// Color of empty cells, transparent for overlay use
+	deadColorSelect := widget.NewSelect(localized(deadColorNames), func(shown string) {
+		name := unlocalized(deadColorNames, shown)
+		if name != "Custom…" {
+			setDeadColor(deadColorByName(name))
+			recolorCanvas()
+			logParam("dead_color", name)
+			return
+		}
+		background, _ := cellBackground()
+		picker := dialog.NewColorPicker(lang.L("Dead cell color"), "", func(c color.Color) {
+			rgba := color.RGBAModel.Convert(c).(color.RGBA)
+			setDeadColor(&rgba)
+			recolorCanvas()
+			logParam("dead_color", fmt.Sprintf("#%02x%02x%02x%02x", rgba.R, rgba.G, rgba.B, rgba.A))
+		}, w)
+		picker.Advanced = true
+		picker.SetColor(background)
+		picker.Show()
+	})
+	deadColorSelect.SetSelected(lang.L("Theme"))
+	ghostsCheck := widget.NewCheck(lang.L("Age ghosts"), func(checked bool) {
+		state.mu.Lock()
+		state.deadGhosts = checked
+		state.mu.Unlock()
+		logParam("dead_ghosts", checked)
+	})
+	accentNames := make([]string, len(accentColors))
+	for i, ac := range accentColors {
+		accentNames[i] = ac.name
+	}
+	accentSelect := widget.NewSelect(localized(accentNames), func(shown string) {
+		s := unlocalized(accentNames, shown)
+		for _, ac := range accentColors {
+			if ac.name == s {
+				uiTheme.accent = ac.color
+			}
+		}
+		applyTheme()
+	})
+	accentSelect.SetSelected(lang.L(accentNames[0]))
 
-4. MUTATIONS (random changes):
-   - Mutation slider = chaos level
-   - Randomly changes some cell ages
-   - Creates unpredictable patterns
+	gridLinesCheck := widget.NewCheck(fmt.Sprintf(lang.L("Grid lines (cells ≥ %dpx)"), minGridLineCell), func(checked bool) {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		state.gridLines = checked
+		if !state.isStarted {
+			drawGridDynamic(sim.grid, img, palette, state.cellSize, state.gridSize)
+			if checked {
+				drawGridLines(img, state.cellSize, state.gridSize, gridLineColor())
+			}
+			canvasImg.Refresh()
+		}
+	})
 
-CONTROLS
+	// Rule thresholds wandering over generations
+	ruleDriftCheck := widget.NewCheck(lang.L("🧬 Evolving rules"), func(checked bool) {
+		state.mu.Lock()
+		state.ruleDrift = checked
+		state.mu.Unlock()
+		logParam("rule_drift", checked)
+	})
 
-* Growth Rate (0.05-0.5): Birth probability
-  -> 0.05: Sparse, slow growth
-  -> 0.30: Dense, rapid colonization
+	// Energy budgets: births cost energy, crowding drains it
+	metabolismCheck := widget.NewCheck(lang.L("⚡ Metabolism"), func(checked bool) {
+		state.mu.Lock()
+		state.metabolism = checked
+		state.mu.Unlock()
+		logParam("metabolism", checked)
+	})
 
-* Mutation (0-0.1): Random variations
-  -> 0: Predictable, stable
-  -> 0.05: Chaotic, surprising
+	// Nutrients limiting growth, optionally drawn behind the cells
+	nutrientsCheck := widget.NewCheck(lang.L("🌱 Nutrients"), func(checked bool) {
+		state.mu.Lock()
+		state.nutrients = checked
+		state.mu.Unlock()
+		logParam("nutrients", checked)
+	})
+	showNutrients := false
+	showNutrientsCheck := widget.NewCheck(lang.L("Show nutrients"), func(checked bool) {
+		state.mu.Lock()
+		showNutrients = checked
+		state.mu.Unlock()
+	})
 
-* Color Palettes: Visual themes
-  -> Change anytime to see patterns differently
+	rebirthFlash := false
+	rebirthCheck := widget.NewCheck(lang.L("Rebirth Flash"), func(checked bool) {
+		state.mu.Lock()
+		rebirthFlash = checked
+		state.mu.Unlock()
+	})
 
-* Supernova: Local extinction
-  -> Creates "hole" in population
-  -> Watch how life recovers
+	// Timing breakdown appended to the statistics
+	perfHUD := false
+	perfCheck := widget.NewCheck(lang.L("⏱ Performance HUD"), func(checked bool) {
+		state.mu.Lock()
+		perfHUD = checked
+		state.mu.Unlock()
+	})
 
-STATISTICS
+	// View mode: how the grid is turned into pixels
+	var renderer Renderer = renderers[0]
+	colorizer := colorizers[0]
+	viewSelect := widget.NewSelect(localized(rendererNames()), func(shown string) {
+		state.mu.Lock()
+		renderer = rendererByName(unlocalized(rendererNames(), shown))
+		if _, flat := renderer.(flatRenderer); flat {
+			renderer = flatRenderer{colorBy: colorizer}
+		}
+		state.mu.Unlock()
+	})
+	viewSelect.SetSelected(lang.L(renderer.Name()))
+
+	// Color-by mode of the flat view
+	colorSelect := widget.NewSelect(localized(colorizerNames()), func(shown string) {
+		state.mu.Lock()
+		colorizer = colorizerByName(unlocalized(colorizerNames(), shown))
+		if _, flat := renderer.(flatRenderer); flat {
+			renderer = flatRenderer{colorBy: colorizer}
+		}
+		state.mu.Unlock()
+	})
+	colorSelect.SetSelected(lang.L(colorizer.name))
+
+	// Placement tool: young cells, fixtures and zones of the built-in rules,
+	// or supernovas
+	tool := paintTool // index in placeTools
+	toolSelect := widget.NewSelect(localized(placeTools), func(shown string) {
+		state.mu.Lock()
+		tool = slices.Index(placeTools, unlocalized(placeTools, shown))
+		state.mu.Unlock()
+	})
+	toolSelect.SetSelected(lang.L(placeTools[0]))
 
-* Population: Living cells count
-* Density: % of grid occupied
-* Avg Age: Population maturity
-* Entropy: System disorder (0=order, 1=chaos)
+	// Supernovas, defined below
+	var detonate func(centerX, centerY int)
 
-SIMPLE EXPERIMENTS
+	// Tapping or dragging on the grid of a run uses the placement tool; a
+	// paused grid is redrawn at once
+	aimedAt := -1 // generation of the last supernova of the tool
+	// redrawPaused shows changes to a paused grid; the caller holds state.mu
+	redrawPaused := func() {
+		if !state.isPaused {
+			return
+		}
+		if !state.view.whole(state.gridSize) {
+			r, _ := renderer.(flatRenderer)
+			drawViewport(img, sim, palette, r.colorBy, state.cellSize, state.view)
+			if state.gridLines {
+				drawGridLines(img, state.cellSize, state.view.side, gridLineColor())
+			}
+			canvasImg.Refresh()
+			return
+		}
+		renderer.Render(sim, img, palette, state.cellSize)
+		if _, stereo := renderer.(stereoRenderer); state.gridLines && !stereo {
+			drawGridLines(img, state.cellSize, state.gridSize, gridLineColor())
+		}
+		if _, stereo := renderer.(stereoRenderer); !stereo && sim.fixtures != nil {
+			drawFixtures(img, sim.fixtures, state.gridSize, state.cellSize)
+		}
+		if _, stereo := renderer.(stereoRenderer); !stereo && sim.zones != nil && sim.rule == nil {
+			drawZoneBorders(img, sim.zones, state.gridSize, state.cellSize)
+		}
+		canvasImg.Refresh()
+	}
 
-1. Slow & Stable (growth=0.15, mutation=0)
-   -> Observe gradual, predictable spread
+	// Edits of the running grid undo cell by cell while their run goes on;
+	// runs counts the runs started
+	runs := 0
+	// recordEdit records e as an undoable change; the caller holds state.mu
+	recordEdit := func(name string, e *gridEdit) {
+		if e.empty() {
+			return
+		}
+		run := runs
+		replay := func(after bool) func() {
+			return func() {
+				state.mu.Lock()
+				defer state.mu.Unlock()
+				sim.applyEdit(e, after)
+				redrawPaused()
+			}
+		}
+		commands.record(command{name: name, do: replay(true), undo: replay(false), stale: func() bool {
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			return !state.isStarted || runs != run
+		}})
+	}
+	// editGrid makes edit an undoable change; the caller holds state.mu
+	editGrid := func(name string, edit func()) {
+		e := newGridEdit(state.gridSize)
+		sim.beginEdit(e)
+		edit()
+		sim.endEdit()
+		recordEdit(name, e)
+	}
+	var stroke *gridEdit // of the tap or drag in progress
+	gridDisplay := newGridView(canvasImg, func(p image.Point) {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		if !state.isStarted {
+			return
+		}
+		x, y := state.view.cell(p, state.cellSize)
+		if x >= state.gridSize || y >= state.gridSize {
+			return
+		}
+		if stroke == nil {
+			stroke = newGridEdit(state.gridSize)
+		}
+		sim.beginEdit(stroke)
+		defer sim.endEdit()
+		switch {
+		case tool == paintTool:
+			if sim.grid.at(x, y) > 0 || sim.fixtures != nil && sim.fixtures[y*state.gridSize+x] != noFixture {
+				return
+			}
+			sim.setCell(x, y, 1)
+		case tool == supernovaTool:
+			// One per generation while dragging
+			if sim.generation == aimedAt {
+				return
+			}
+			aimedAt = sim.generation
+			detonate(x, y)
+			tutorial.advance("supernova")
+		case sim.rule != nil:
+			return
+		case tool == zoneTool:
+			sim.paintZone(x, y, state.zoneBrush)
+		case tool == eraseTool:
+			sim.placeFixture(x, y, noFixture)
+		default:
+			sim.placeFixture(x, y, fixture(tool))
+		}
+		redrawPaused()
+	})
+	// A whole stroke undoes at once
+	gridDisplay.onStrokeEnd = func() {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		if stroke != nil {
+			recordEdit(placeTools[tool], stroke)
+			stroke = nil
+		}
+	}
 
-2. Fast & Chaotic (growth=0.30, mutation=0.05)
-   -> Watch explosive, random evolution
+	startButton := widget.NewButton(lang.L("▶ Start"), func() {})
+	pauseButton := widget.NewButton(lang.L("⏸ Pause"), func() {})
+	pauseButton.Disable()
 
-3. Recovery Test: Start -> Wait 50% -> Supernova
-   -> See how fast population recovers
+	supernovaButton := widget.NewButton(lang.L("💥 Supernova"), func() {})
+	supernovaButton.Disable()
+	patientZeroButton := widget.NewButton(lang.L("🦠 Patient zero"), func() {})
+	patientZeroButton.Disable()
+	novaLabel := widget.NewLabelWithData(vm.novaRadius.text(func(v float64) string {
+		return fmt.Sprintf(lang.L("Supernova radius: %d"), int(v))
+	}))
+	novaSlider := widget.NewSliderWithData(3, 40, vm.novaRadius)
+	vm.novaRadius.onChange(func(v float64) { logParam("supernova_radius", int(v)) })
+	radiationCheck := widget.NewCheck(lang.L("☢ Radiation"), func(checked bool) {
+		state.mu.Lock()
+		state.radiation = checked
+		state.mu.Unlock()
+		logParam("radiation", checked)
+	})
 
-CELL COLORS
+	automationButton := widget.NewButton(lang.L("🎚 Automation"), func() {
+		showAutomationDialog(w, state)
+	})
 
-Dead (Black) -> Young (Green) -> Mature (Yellow) -> Old (Red)
+	ageCurvesButton := widget.NewButton(lang.L("📉 Age curves"), func() {
+		showAgeCurvesDialog(w, state)
+	})
+	birthCurveButton := widget.NewButton(lang.L("🐣 Birth curve"), func() {
+		showBirthCurveDialog(w, state)
+	})
+	triggersButton := widget.NewButton(lang.L("🔔 Triggers"), func() {
+		showTriggersDialog(w, state)
+	})
 
-The magic: Simple rules create complex, beautiful patterns!
-Each simulation is unique due to random start.
+	catastrophesButton := widget.NewButton(lang.L("🌋 Catastrophes"), func() {
+		showCatastrophesDialog(w, state)
+	})
 
-Press Start to begin your experiment!
-	`
+	zonesButton := widget.NewButton(lang.L("🗺 Zones"), func() {
+		showZonesDialog(w, state)
+	})
 
-		helpLabel := widget.NewLabel(helpText)
-		helpLabel.Wrapping = fyne.TextWrapWord
-		
-		scrollHelp := container.NewScroll(helpLabel)
-		scrollHelp.SetMinSize(fyne.NewSize(600, 400))
-		
-		d := dialog.NewCustom("How it works?", "Close", scrollHelp, w)
-		d.Show()
+	// A loaded grid replaces the running one, or else the next run starts
+	// from it, see Simulation.seedFrom
+	var startGrid [][]int
+	loadStartGrid := func(values [][]int) {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		if state.isStarted {
+			editGrid("loaded grid", func() {
+				before := slices.Clone(sim.grid.cells)
+				sim.seedFrom(values)
+				sim.noteCells(before)
+			})
+			addEvent(state, "LOAD", "Grid replaced by a loaded one")
+			return
+		}
+		startGrid = values
+		// Preview it on the stopped grid
+		sim.resize(state.gridSize)
+		sim.seedFrom(values)
+		if state.view.whole(state.gridSize) {
+			drawGridDynamic(sim.grid, img, palette, state.cellSize, state.gridSize)
+		} else {
+			drawViewport(img, sim, palette, Colorizer{}, state.cellSize, state.view)
+		}
+		canvasImg.Refresh()
 	}
 
-	// Function to reset grid
-	resetGrid := func() {
-		// Recreate grid with new size
-		grid = make([][]Cell, state.gridSize)
-		for i := range grid {
-			grid[i] = make([]Cell, state.gridSize)
-		}
-		
-		// Recreate image with new size
-		img = image.NewRGBA(image.Rect(0, 0, displaySize, displaySize))
-		
-		// Add new cells
-		newInitCount := 200 + rng.Intn(400)
-		for i := 0; i < newInitCount; i++ {
-			x := rng.Intn(state.gridSize)
-			y := rng.Intn(state.gridSize)
-			grid[y][x].val = rng.Intn(10) + 1
-		}
-		
-		// Redraw grid
+	// Files dropped on the window load at once
+	w.SetOnDropped(func(_ fyne.Position, uris []fyne.URI) {
+		if len(uris) == 0 {
+			return
+		}
+		rc, err := storage.Reader(uris[0])
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		state.mu.Lock()
+		size := state.gridSize
+		state.mu.Unlock()
+		values, err := decodeGridFile(uris[0].Name(), data, size)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		loadStartGrid(values)
+	})
+	imageGridButton := widget.NewButton(lang.L("🖼 Image as grid"), func() {
+		state.mu.Lock()
+		size := state.gridSize
+		state.mu.Unlock()
+		showImageGridDialog(w, size, loadStartGrid)
+	})
+	if browser {
+		imageGridButton.Hide()
+	}
+
+	scenarioLabel := widget.NewLabelWithData(vm.scenario)
+	scenarioLabel.Wrapping = fyne.TextWrapWord
+	scenarioLabel.Hide()
+	scenarioButton := widget.NewButton(lang.L("🏆 Scenarios"), func() {
+		showScenarioPicker(w, func(s *Scenario) {
+			if s == nil {
+				state.mu.Lock()
+				state.scenario = nil
+				state.mu.Unlock()
+				scenarioLabel.Hide()
+				return
+			}
+			active := &ActiveScenario{scenario: s, message: s.description}
+			state.mu.Lock()
+			state.scenario = active
+			state.mu.Unlock()
+			commands.group("scenario", func() {
+				growthSlider.SetValue(s.growthRate)
+				mutationSlider.SetValue(s.mutationChance)
+			})
+			vm.scenario.Set(active.String())
+			scenarioLabel.Show()
+		})
+	})
+
+	helpButton := widget.NewButton(lang.L("❓ How it works?"), func() {})
+
+	compareButton := widget.NewButton(lang.L("⚖ Compare A/B"), func() {
+		openCompareWindow(a, life, newSeed(), state)
+	})
+
+	wallpaperButton := widget.NewButton(lang.L("🖼 Wallpaper mode"), func() {
+		openWallpaperWindow(a, life, newSeed(), state)
+	})
+
+	// Streaming overlay: the grid alone on a chroma key, empty cells in the
+	// key color while it is open
+	var overlay *overlayWindow
+	overlayButton := widget.NewButton(lang.L("📺 Overlay"), nil)
+	overlayButton.OnTapped = func() {
+		if overlay != nil {
+			overlay.win.Close()
+			return
+		}
+		showOverlayDialog(w, func(size overlaySize, background color.RGBA) {
+			previous := currentDeadColor()
+			setDeadColor(&background)
+			recolorCanvas()
+			overlay = openOverlay(a, size, background, img, func() {
+				overlay = nil
+				setDeadColor(previous)
+				recolorCanvas()
+				overlayButton.SetText(lang.L("📺 Overlay"))
+			})
+			overlayButton.SetText(lang.L("📺 Close overlay"))
+		})
+	}
+	if browser {
+		// A web page has a single window and no desktop background
+		compareButton.Hide()
+		wallpaperButton.Hide()
+		overlayButton.Hide()
+	}
+
+	shareButton := widget.NewButton(lang.L("🌐 Share"), func() {
+		showShareDialog(w, a.Driver(), state, img)
+	})
+
+	browseButton := widget.NewButton(lang.L("🌐 Browse shared"), func() {
+		showBrowseSharedDialog(w, a.Driver(), state, func(cfg sharedConfig) {
+			state.mu.Lock()
+			started := state.isStarted
+			state.mu.Unlock()
+			if started {
+				dialog.ShowInformation(lang.L("Browse shared"), lang.L("Stop the simulation before loading a configuration."), w)
+				return
+			}
+			cfg.validate()
+			commands.group("shared configuration", func() {
+				growthSlider.SetValue(cfg.GrowthRate)
+				mutationSlider.SetValue(cfg.MutationChance)
+				pixelSlider.SetValue(float64(cfg.CellSize))
+				speedSlider.SetValue(float64(cfg.Speed))
+				paletteSelect.SetSelected(lang.L(paletteModeNames[cfg.PaletteMode]))
+				symmetrySelect.SetSelected(lang.L(symmetryFromName(cfg.Symmetry).String()))
+			})
+			automation := Automation{}
+			if cfg.GrowthCurve != "" || cfg.MutationCurve != "" {
+				growth, err1 := parseKeyframes(cfg.GrowthCurve)
+				mutation, err2 := parseKeyframes(cfg.MutationCurve)
+				if err1 == nil && err2 == nil {
+					automation = Automation{enabled: true, growth: growth, mutation: mutation}
+				}
+			}
+			state.mu.Lock()
+			state.automation = automation
+			addEvent(state, "SHARE", "Loaded shared configuration: "+cfg.Name)
+			state.mu.Unlock()
+		})
+	})
+
+	// Simulation codes: the seed and parameters of a run as a line of text,
+	// replayed by the next Start of any instance it is pasted into
+	copyCodeButton := widget.NewButton(lang.L("🔗 Copy code"), func() {
+		state.mu.Lock()
+		seed := sim.seed
+		if !state.isStarted && sim.generation == 0 {
+			// No run yet: the code is of the next one
+			if opts.seed == 0 {
+				opts.seed = rng.Int63()
+			}
+			seed = opts.seed
+		}
+		code := captureSimCode(state, sim, seed).String()
+		state.mu.Unlock()
+		a.Clipboard().SetContent(code)
+		showSimCodeDialog(w, code)
+	})
+	loadCodeButton := widget.NewButton(lang.L("🔗 Load code"), func() {
+		showPasteSimCodeDialog(w, a.Clipboard().Content(), func(code simCode) {
+			state.mu.Lock()
+			started := state.isStarted
+			if !started {
+				code.applyHidden(state)
+				opts.seed = code.Seed
+			}
+			state.mu.Unlock()
+			if started {
+				dialog.ShowInformation(lang.L("Simulation code"), lang.L("Stop the simulation before loading a configuration."), w)
+				return
+			}
+			// The parameters undo at once; the hidden ones and the seed stay
+			commands.group("simulation code", func() {
+				ruleSelect.SetSelected(lang.L(code.Rule))
+				showRuleParams(code.Rule)
+				growthSlider.SetValue(code.GrowthRate)
+				mutationSlider.SetValue(code.MutationChance)
+				temperatureSlider.SetValue(code.Temperature)
+				seasonSlider.SetValue(float64(code.SeasonPeriod))
+				driftSlider.SetValue(code.DriftStrength)
+				driftSelect.SetSelected(driftDirections[code.DriftDirection])
+				movementSlider.SetValue(code.MovementRate)
+				immigrationSlider.SetValue(code.Immigration)
+				immigrationSelect.SetSelected(immigrationEdges[code.EntryEdge])
+				pixelSlider.SetValue(float64(code.CellSize))
+				gridSizeSelect.SetSelected(lang.L(gridSizeNames()[slices.Index(largeGridSizes, code.GridCells)]))
+				speedSlider.SetValue(float64(code.Speed))
+				antsSlider.SetValue(float64(code.Ants))
+				paletteSelect.SetSelected(lang.L(paletteModeNames[code.PaletteMode]))
+				symmetrySelect.SetSelected(lang.L(symmetryFromName(code.Symmetry).String()))
+				ruleDriftCheck.SetChecked(code.RuleDrift)
+				metabolismCheck.SetChecked(code.Metabolism)
+				nutrientsCheck.SetChecked(code.Nutrients)
+				contagionSlider.SetValue(code.InfectionRate)
+				lethalitySlider.SetValue(float64(code.InfectionSpan))
+				novaSlider.SetValue(float64(code.NovaRadius))
+				radiationCheck.SetChecked(code.Radiation)
+			})
+			state.mu.Lock()
+			addEvent(state, "CODE", fmt.Sprintf("Loaded simulation code, seed %d for the next run", code.Seed))
+			state.mu.Unlock()
+		})
+	})
+
+	vm.stats.Set(lang.L("Stats: --"))
+	statsLabel := widget.NewLabelWithData(vm.stats)
+
+	// Rebirths per generation (age 50 -> 1), newest on the right
+	rebirthHistory := make([]int, 0, displaySize)
+	rebirthImg := image.NewRGBA(image.Rect(0, 0, displaySize/2, 40))
+	drawBarChart(rebirthImg, rebirthHistory, color.RGBA{20, 20, 20, 255}, color.RGBA{255, 255, 255, 255})
+	rebirthChart := canvas.NewImageFromImage(rebirthImg)
+	rebirthChart.FillMode = canvas.ImageFillStretch
+	rebirthChart.SetMinSize(fyne.NewSize(float32(displaySize/2), 40))
+
+	// Living cells per age, the youngest at the bottom
+	pyramidImg := image.NewRGBA(image.Rect(0, 0, displaySize/2, 2*maxCellAge))
+	drawAgePyramid(pyramidImg, state.stats.ageHistogram, palette, color.RGBA{20, 20, 20, 255})
+	pyramidChart := canvas.NewImageFromImage(pyramidImg)
+	pyramidChart.FillMode = canvas.ImageFillStretch
+	pyramidChart.SetMinSize(fyne.NewSize(float32(displaySize/2), 2*maxCellAge))
+	vm.events.Set(lang.L("Log: Waiting for start..."))
+	eventLog := widget.NewLabelWithData(vm.events)
+	eventLog.Wrapping = fyne.TextWrapWord
+
+	snapshotButton := widget.NewButton(lang.L("📷 Snapshot"), func() {
+		saveFile(w, "snapshot.png", "image/png", func(out io.Writer) error {
+			return png.Encode(out, img)
+		})
+	})
+
+	// The grid as numbers, for diffs and analysis tools, and as RLE patterns
+	exportGrid := func(name, mimeType string, write func(out io.Writer, values [][]int, generation int) error) {
+		state.mu.Lock()
+		values, generation := gridValues(sim.grid), sim.generation
+		state.mu.Unlock()
+		saveFile(w, name, mimeType, func(out io.Writer) error {
+			return write(out, values, generation)
+		})
+	}
+	exportCSVButton := widget.NewButton(lang.L("📄 Grid CSV"), func() {
+		exportGrid("grid.csv", "text/csv", func(out io.Writer, values [][]int, _ int) error {
+			return writeGridCSV(out, values)
+		})
+	})
+	exportASCIIButton := widget.NewButton(lang.L("📄 Grid ASCII"), func() {
+		exportGrid("grid.txt", "text/plain", func(out io.Writer, values [][]int, _ int) error {
+			return writeGridASCII(out, values)
+		})
+	})
+	exportRLEButton := widget.NewButton(lang.L("🧬 RLE"), func() {
+		exportGrid("pattern.rle", "text/plain", func(out io.Writer, values [][]int, generation int) error {
+			return writeRLE(out, values, generation, false)
+		})
+	})
+	exportAgesRLEButton := widget.NewButton(lang.L("🧬 RLE with ages"), func() {
+		exportGrid("pattern-ages.rle", "text/plain", func(out io.Writer, values [][]int, generation int) error {
+			return writeRLE(out, values, generation, true)
+		})
+	})
+
+	// Animation recording; stopping it offers the export
+	recordButton := widget.NewButton(lang.L("⏺ Record"), nil)
+	var recorded *animation // in progress, or full
+	recordButton.OnTapped = func() {
+		state.mu.Lock()
+		if recorded == nil {
+			recorded = newAnimation(state.gridSize, state.cellSize, time.Duration(state.speed)*time.Millisecond)
+			state.recording = recorded
+			state.mu.Unlock()
+			recordButton.SetText(lang.L("⏹ Stop recording"))
+			return
+		}
+		state.recording = nil
+		state.mu.Unlock()
+		recordButton.SetText(lang.L("⏺ Record"))
+		showAnimationExportDialog(w, recorded)
+		recorded = nil
+	}
+
+	// Timelapse: a frame every N generations saved to a folder
+	timelapseButton := widget.NewButton(lang.L("⏱ Timelapse"), nil)
+	timelapseButton.OnTapped = func() {
+		state.mu.Lock()
+		capture := state.timelapse
+		state.timelapse = nil
+		state.mu.Unlock()
+		if capture == nil {
+			showTimelapseDialog(w, func(t *timelapse) {
+				state.mu.Lock()
+				state.timelapse = t
+				state.mu.Unlock()
+				timelapseButton.SetText(lang.L("⏹ Stop timelapse"))
+			})
+			return
+		}
+		timelapseButton.SetText(lang.L("⏱ Timelapse"))
+		if err := capture.finish(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	}
+	if browser {
+		// there is no folder to save into
+		timelapseButton.Hide()
+	}
+
+	exportLogButton := widget.NewButton(lang.L("💾 Export log"), func() {
+		saveFile(w, "events.txt", "text/plain", state.events.writeText)
+	})
+
+	// Guided tour: each step highlights a control and waits for its action
+	startHighlight, startView := newHighlight(startButton)
+	pauseHighlight, pauseView := newHighlight(pauseButton)
+	growthHighlight, growthView := newHighlight(growthSlider)
+	supernovaHighlight, supernovaView := newHighlight(supernovaButton)
+	tutorial = newTutorial([]tutorialStep{
+		{
+			title:  lang.L("Start a run"),
+			text:   lang.L("The black screen is an empty grid. Press ▶ Start to seed it with 200-600 random cells. Each cell has an age from 1 to 50, shown by its color; cells are born next to living neighbours and grow older over the generations."),
+			action: "start",
+			target: startHighlight,
+		},
+		{
+			title:  lang.L("Pause"),
+			text:   lang.L("Press ⏸ Pause to freeze the evolution and study the patterns. Press it again to resume."),
+			action: "pause",
+			target: pauseHighlight,
+		},
+		{
+			title:  lang.L("Change the growth rate"),
+			text:   lang.L("Settings are locked while a run is in progress. Press ⏹ Stop, then drag the growth rate slider: higher values let empty cells come alive faster."),
+			action: "growth",
+			target: growthHighlight,
+		},
+		{
+			title:  lang.L("Trigger a supernova"),
+			text:   lang.L("Press ▶ Start again, then 💥 Supernova to wipe out a random circular area and watch the survivors recolonize it."),
+			action: "supernova",
+			target: supernovaHighlight,
+		},
+		{
+			title: lang.L("You're ready!"),
+			text:  lang.L("Simple rules create complex patterns, and every run is unique thanks to its random start. Explore palettes, symmetry, scenarios and triggers, and follow the curves in the 📈 Charts tab. Reopen this tutorial any time with ❓ How it works?"),
+		},
+	})
+
+	controlsLeft := container.NewVBox(
+		widget.NewLabel(lang.L("🎮 Controls")),
+		widget.NewSeparator(),
+		growthLabel,
+		growthView,
+		mutationLabel,
+		mutationSlider,
+		temperatureLabel,
+		temperatureSlider,
+		seasonLabel,
+		seasonSlider,
+		container.NewBorder(nil, nil, nil, driftSelect, driftLabel),
+		driftSlider,
+		movementLabel,
+		movementSlider,
+		container.NewBorder(nil, nil, nil, immigrationSelect, immigrationLabel),
+		immigrationSlider,
+		contagionLabel,
+		contagionSlider,
+		lethalityLabel,
+		lethalitySlider,
+		container.NewBorder(nil, nil, nil, gridSizeSelect, pixelLabel),
+		pixelSlider,
+		container.NewBorder(nil, nil, nil, speedModeSelect, speedLabel),
+		speedSlider,
+		antsLabel,
+		antsSlider,
+		container.NewBorder(nil, nil, widget.NewLabel(lang.L("Rule family")), nil, ruleSelect),
+		ruleParamsBox,
+		container.NewBorder(nil, nil, nil, importPaletteButton, paletteSelect),
+		container.NewBorder(nil, nil, nil, freezePaletteCheck, paletteSpeedLabel),
+		paletteSpeedSlider,
+		symmetrySelect,
+		container.NewGridWithColumns(2, themeSelect, accentSelect),
+		container.NewBorder(nil, nil, widget.NewLabel(lang.L("Dead cells")), ghostsCheck, deadColorSelect),
+		effectsButton,
+		gridLinesCheck,
+		ruleDriftCheck,
+		metabolismCheck,
+		container.NewGridWithColumns(2, nutrientsCheck, showNutrientsCheck),
+		rebirthCheck,
+		perfCheck,
+		viewSelect,
+		container.NewBorder(nil, nil, widget.NewLabel(lang.L("Color by")), nil, colorSelect),
+		toolSelect,
+		container.NewGridWithColumns(2, startView, pauseView),
+		container.NewGridWithColumns(2, supernovaView, patientZeroButton),
+		novaLabel,
+		novaSlider,
+		radiationCheck,
+		automationButton,
+		ageCurvesButton,
+		birthCurveButton,
+		triggersButton,
+		catastrophesButton,
+		zonesButton,
+		scenarioButton,
+		imageGridButton,
+		container.NewGridWithColumns(2, snapshotButton, recordButton),
+		timelapseButton,
+		container.NewGridWithColumns(2, exportCSVButton, exportASCIIButton),
+		container.NewGridWithColumns(2, exportRLEButton, exportAgesRLEButton),
+		compareButton,
+		wallpaperButton,
+		overlayButton,
+		container.NewGridWithColumns(2, shareButton, browseButton),
+		container.NewGridWithColumns(2, copyCodeButton, loadCodeButton),
+		helpButton,
+	)
+
+	// Statistics and log can be popped out so the main window is mostly grid
+	statsPanel := newDetachablePanel(a, lang.L("📊 Statistics"), container.NewVBox(
+		statsLabel,
+		widget.NewLabel(lang.L("🔁 Rebirths/gen")),
+		rebirthChart,
+		widget.NewLabel(lang.L("👥 Age pyramid")),
+		pyramidChart,
+	))
+	logPanel := newDetachablePanel(a, lang.L("📜 Event Log"), container.NewVBox(eventLog, exportLogButton))
+	if mobile || browser {
+		statsPanel.button.Hide()
+		logPanel.button.Hide()
+	}
+	controlsRight := container.NewVBox(
+		statsPanel.slot,
+		widget.NewSeparator(),
+		logPanel.slot,
+		widget.NewSeparator(),
+		legendLabel,
+		legendBox,
+	)
+
+	status := container.NewVBox(tutorial.panel, scenarioLabel, container.NewStack(statusFlash, statusLabel))
+	var mainContainer fyne.CanvasObject
+	if mobile {
+		// Phones and tablets: the controls sit in a bottom sheet whose
+		// divider can be dragged, with one tab per panel
+		sheet := container.NewBorder(status, nil, nil, nil, container.NewAppTabs(
+			container.NewTabItem(lang.L("🎮 Controls"), container.NewVScroll(controlsLeft)),
+			container.NewTabItem(lang.L("📊 Statistics"), container.NewVScroll(controlsRight)),
+		))
+		split := container.NewVSplit(gridDisplay, sheet)
+		split.SetOffset(0.55)
+		mainContainer = split
+	} else {
+		controls := container.NewGridWithColumns(2, controlsLeft, controlsRight)
+
+		// Controls go below the grid, or beside it on wide windows
+		mainContainer = container.New(&responsiveLayout{},
+			gridDisplay,
+			container.NewVScroll(container.NewVBox(status, controls)),
+		)
+	}
+
+	history := &StatsHistory{}
+	chartPane := newSeriesPane(history, state)
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem(lang.L("🔬 Simulation"), mainContainer),
+		container.NewTabItem(lang.L("📈 Charts"), chartPane.content(w)),
+		container.NewTabItem(lang.L("🧪 Experiments"), newExperimentsTab(a.Driver(), life, newSeed(), state)),
+	)
+
+	// The simulation parameters undo and redo, see commandStack; the view
+	// and theme settings do not
+	for _, c := range []struct {
+		name    string
+		control fyne.Disableable
+	}{
+		{"growth rate", growthSlider},
+		{"mutation", mutationSlider},
+		{"temperature", temperatureSlider},
+		{"seasons", seasonSlider},
+		{"drift", driftSlider},
+		{"drift direction", driftSelect},
+		{"movement", movementSlider},
+		{"immigration", immigrationSlider},
+		{"immigration edge", immigrationSelect},
+		{"contagion", contagionSlider},
+		{"lethality", lethalitySlider},
+		{"pixel size", pixelSlider},
+		{"grid size", gridSizeSelect},
+		{"speed", speedSlider},
+		{"speed mode", speedModeSelect},
+		{"ants", antsSlider},
+		{"palette", paletteSelect},
+		{"palette speed", paletteSpeedSlider},
+		{"frozen palette", freezePaletteCheck},
+		{"rule family", ruleSelect},
+		{"symmetry", symmetrySelect},
+		{"age ghosts", ghostsCheck},
+		{"grid lines", gridLinesCheck},
+		{"evolving rules", ruleDriftCheck},
+		{"metabolism", metabolismCheck},
+		{"nutrients", nutrientsCheck},
+		{"supernova radius", novaSlider},
+		{"radiation", radiationCheck},
+	} {
+		commands.track(c.name, c.control)
+	}
+
+	// Ctrl+Z undoes the last change, Ctrl+Shift+Z or Ctrl+Y redoes it
+	undoRedo := func(step func() (string, error), verb string) (string, error) {
+		name, err := step()
+		if err != nil {
+			return "", err
+		}
+		state.mu.Lock()
+		addEvent(state, strings.ToUpper(verb), fmt.Sprintf("%s %s", verb, name))
+		state.mu.Unlock()
+		return fmt.Sprintf("%s %s", verb, name), nil
+	}
+	undo := func() (string, error) { return undoRedo(commands.undo, "Undo") }
+	redo := func() (string, error) { return undoRedo(commands.redo, "Redo") }
+	showUndone := func(step func() (string, error)) func(fyne.Shortcut) {
+		return func(fyne.Shortcut) {
+			done, err := step()
+			if err != nil {
+				done = err.Error()
+			}
+			vm.status.Set(done)
+		}
+	}
+	w.Canvas().AddShortcut(&fyne.ShortcutUndo{}, showUndone(undo))
+	w.Canvas().AddShortcut(&fyne.ShortcutRedo{}, showUndone(redo))
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}, showUndone(redo))
+
+	// Developer console, toggled with `: the controls as text commands
+	devConsole := newConsole()
+	consoleSliders := map[string]*widget.Slider{
+		"growth":      growthSlider,
+		"mutation":    mutationSlider,
+		"temperature": temperatureSlider,
+		"seasons":     seasonSlider,
+		"drift":       driftSlider,
+		"movement":    movementSlider,
+		"immigration": immigrationSlider,
+		"pixel":       pixelSlider,
+		"speed":       speedSlider,
+		"ants":        antsSlider,
+		"nova":        novaSlider,
+	}
+	devConsole.register("set", consoleCommand{
+		usage: "[name value]",
+		help:  "Move the slider of a parameter; alone, list them",
+		run: func(args []string) (string, error) {
+			if len(args) == 0 {
+				var lines []string
+				for name, s := range consoleSliders {
+					lines = append(lines, fmt.Sprintf("%s = %g (%g-%g)", name, s.Value, s.Min, s.Max))
+				}
+				slices.Sort(lines)
+				return strings.Join(lines, "\n"), nil
+			}
+			if len(args) != 2 {
+				return "", errConsoleArgs
+			}
+			s, ok := consoleSliders[strings.ToLower(args[0])]
+			if !ok {
+				return "", fmt.Errorf("unknown parameter %q", args[0])
+			}
+			v, err := strconv.ParseFloat(args[1], 64)
+			if err != nil {
+				return "", fmt.Errorf("%q is not a number", args[1])
+			}
+			if s.Disabled() {
+				return "", errors.New("locked during a run")
+			}
+			s.SetValue(v)
+			return fmt.Sprintf("%s = %g", args[0], s.Value), nil
+		},
+	})
+	devConsole.register("seed", consoleCommand{
+		usage: "<n>",
+		help:  "Seed the next run",
+		run: func(args []string) (string, error) {
+			if len(args) != 1 {
+				return "", errConsoleArgs
+			}
+			seed, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || seed == 0 {
+				return "", fmt.Errorf("%q is not a non-zero whole number", args[0])
+			}
+			setSeed := func(seed int64) func() {
+				return func() {
+					state.mu.Lock()
+					opts.seed = seed
+					state.mu.Unlock()
+				}
+			}
+			state.mu.Lock()
+			previous := opts.seed
+			opts.seed = seed
+			started := state.isStarted
+			state.mu.Unlock()
+			commands.record(command{name: "seed", do: setSeed(seed), undo: setSeed(previous)})
+			if started {
+				return fmt.Sprintf("The next run will use seed %d", seed), nil
+			}
+			return fmt.Sprintf("Seed %d", seed), nil
+		},
+	})
+	devConsole.register("nova", consoleCommand{
+		usage: "<x> <y> [radius]",
+		help:  "Detonate a supernova",
+		run: func(args []string) (string, error) {
+			ints, err := consoleInts(args)
+			if err != nil {
+				return "", err
+			}
+			if len(ints) < 2 || len(ints) > 3 {
+				return "", errConsoleArgs
+			}
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			if !state.isStarted {
+				return "", errors.New("no run in progress")
+			}
+			x, y := ints[0], ints[1]
+			if x < 0 || y < 0 || x >= state.gridSize || y >= state.gridSize {
+				return "", fmt.Errorf("(%d, %d) is off the %dx%d grid", x, y, state.gridSize, state.gridSize)
+			}
+			radius := state.novaRadius
+			if len(ints) == 3 {
+				state.novaRadius = clampInt(ints[2], 1, state.gridSize)
+			}
+			editGrid("supernova", func() { detonate(x, y) })
+			state.novaRadius = radius
+			redrawPaused()
+			return "", nil
+		},
+	})
+	devConsole.register("export", consoleCommand{
+		usage: "png|csv|txt|rle|rle-ages [file]",
+		help:  "Write the image or the grid to a file, or download it",
+		run: func(args []string) (string, error) {
+			if len(args) < 1 || len(args) > 2 {
+				return "", errConsoleArgs
+			}
+			state.mu.Lock()
+			values, generation := gridValues(sim.grid), sim.generation
+			state.mu.Unlock()
+			var name, mimeType string
+			var write func(io.Writer) error
+			switch strings.ToLower(args[0]) {
+			case "png":
+				name, mimeType = "snapshot.png", "image/png"
+				write = func(out io.Writer) error { return png.Encode(out, img) }
+			case "csv":
+				name, mimeType = "grid.csv", "text/csv"
+				write = func(out io.Writer) error { return writeGridCSV(out, values) }
+			case "txt":
+				name, mimeType = "grid.txt", "text/plain"
+				write = func(out io.Writer) error { return writeGridASCII(out, values) }
+			case "rle", "rle-ages":
+				name, mimeType = "pattern.rle", "text/plain"
+				ages := args[0] == "rle-ages"
+				write = func(out io.Writer) error { return writeRLE(out, values, generation, ages) }
+			default:
+				return "", fmt.Errorf("unknown format %q", args[0])
+			}
+			if len(args) == 1 || browser {
+				saveFile(w, name, mimeType, write)
+				return "", nil
+			}
+			if err := writeFileWith(args[1], write); err != nil {
+				return "", err
+			}
+			return "Wrote " + args[1], nil
+		},
+	})
+	devConsole.register("rule", consoleCommand{
+		usage: "<family>",
+		help:  "Choose the rule family of the next run",
+		run: func(args []string) (string, error) {
+			name := strings.Join(args, " ")
+			i := slices.IndexFunc(ruleFamilyNames(), func(n string) bool { return strings.EqualFold(n, name) })
+			if i < 0 {
+				return "", fmt.Errorf("unknown family %q; one of %s", name, strings.Join(ruleFamilyNames(), ", "))
+			}
+			if ruleSelect.Disabled() {
+				return "", errors.New("locked during a run")
+			}
+			ruleSelect.SetSelected(lang.L(ruleFamilyNames()[i]))
+			return "", nil
+		},
+	})
+	tap := func(button *widget.Button) func([]string) (string, error) {
+		return func(args []string) (string, error) {
+			if len(args) != 0 {
+				return "", errConsoleArgs
+			}
+			button.OnTapped()
+			return "", nil
+		}
+	}
+	devConsole.register("undo", consoleCommand{help: "Undo the last change", run: func([]string) (string, error) { return undo() }})
+	devConsole.register("redo", consoleCommand{help: "Redo the last undone change", run: func([]string) (string, error) { return redo() }})
+	devConsole.register("start", consoleCommand{help: "Start or stop a run", run: tap(startButton)})
+	devConsole.register("pause", consoleCommand{help: "Pause or resume the run", run: tap(pauseButton)})
+	console := newConsolePane(devConsole, w.Canvas())
+	w.Canvas().SetOnTypedRune(func(r rune) {
+		if r == '`' {
+			console.toggle()
+		}
+	})
+
+	w.SetContent(container.NewBorder(nil, console.content, nil, nil, tabs))
+
+	// Ctrl+V stamps a Life pattern from the clipboard at the mouse, or the
+	// center of the grid; a stopped grid starts the next run from it
+	w.Canvas().AddShortcut(&fyne.ShortcutPaste{}, func(fyne.Shortcut) {
+		values, err := parsePattern(a.Clipboard().Content())
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		state.mu.Lock()
+		if !state.isStarted {
+			state.mu.Unlock()
+			loadStartGrid(values)
+			return
+		}
+		defer state.mu.Unlock()
+		x, y := state.gridSize/2, state.gridSize/2
+		if p, ok := gridDisplay.cursor(); ok {
+			x, y = state.view.cell(p, state.cellSize)
+		}
+		editGrid("paste", func() { sim.stamp(values, x, y) })
+		addEvent(state, "PASTE", fmt.Sprintf("Pattern pasted at (%d, %d)", x, y))
+		redrawPaused()
+	})
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyP, Modifier: fyne.KeyModifierControl | fyne.KeyModifierShift}, func(fyne.Shortcut) {
+		showProfilingDialog(w, state, profileDir, pprofAddr)
+	})
+	if userCfg.Window.Width > 0 && userCfg.Window.Height > 0 {
+		w.Resize(fyne.NewSize(userCfg.Window.Width, userCfg.Window.Height))
+	} else {
+		w.Resize(fyne.NewSize(float32(displaySize), float32(displaySize+280)))
+	}
+
+	currentSettings := func() userConfig {
+		size := w.Canvas().Size()
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		mutation := state.mutationChance
+		return userConfig{
+			Palette:        unlocalized(paletteNames, paletteSelect.Selected),
+			GrowthRate:     state.growthRate,
+			MutationChance: &mutation,
+			CellSize:       state.cellSize,
+			Speed:          state.speed,
+			Window:         windowConfig{Width: size.Width, Height: size.Height},
+			Effects:        effectsConfig(state.effects),
+		}
+	}
+
+	// Settings > Save as defaults writes the current settings to config.toml
+	saveDefaults := fyne.NewMenuItem(lang.L("Save as defaults"), func() {
+		if cfgPath == "" {
+			dialog.ShowError(errors.New("no user configuration directory"), w)
+			return
+		}
+		if err := saveUserConfig(cfgPath, currentSettings()); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		dialog.ShowInformation(lang.L("Save as defaults"), fmt.Sprintf(lang.L("Defaults saved to %s"), cfgPath), w)
+	})
+	if !browser {
+		// there is no config file in the browser
+		w.SetMainMenu(fyne.NewMainMenu(fyne.NewMenu(lang.L("Settings"), saveDefaults)))
+	}
+
+	// Closing the window remembers the settings and ends background work
+	w.SetOnClosed(func() {
+		rememberSettings(a.Preferences(), currentSettings())
+		statsPanel.close()
+		logPanel.close()
+		life.cancel()
+	})
+	w.CenterOnScreen()
+	// Allow free window resizing
+
+	driver := a.Driver()
+
+	// Help button - Start the guided tutorial
+	helpButton.OnTapped = func() {
+		tutorial.start()
+	}
+
+	// Function to reset grid
+	// A crash-recovery checkpoint picked up by the next Start
+	var resumeFrom *Checkpoint
+
+	resetGrid := func() {
+		// Recreate grid with new size and new random cells
+		sim.symmetry = state.symmetry
+		sim.rule = newRuleFamily(state.ruleFamily)
+		sim.resize(state.gridSize)
+		sim.setZones(state.zoneLayout)
+		state.zonesChanged = false
+		runs++
+		if resumeFrom != nil {
+			resumeFrom.restore(sim)
+			addEvent(state, "CHECKPOINT", fmt.Sprintf("Resumed from checkpoint at generation %d", sim.generation))
+			resumeFrom = nil
+		} else {
+			seed := rng.Int63()
+			if opts.seed != 0 {
+				// -seed and loaded simulation codes only apply to the next run
+				seed, opts.seed = opts.seed, 0
+			}
+			sim.reset(seed)
+			if startGrid != nil {
+				sim.seedFrom(startGrid)
+				addEvent(state, "LOAD", "Started from a loaded grid")
+				startGrid = nil
+			}
+		}
+		tuneRule(sim.rule, state.ruleParams[ruleName(sim.rule)])
+
+		clear(img.Pix)
+
+		// Redraw grid
 		palette = generateDynamicPalette(rng, 0, state.paletteMode)
 		updateLegendColors()
-		drawGridDynamic(grid, img, palette, state.cellSize, state.gridSize)
+		drawGridDynamic(sim.grid, img, palette, state.cellSize, state.gridSize)
 		canvasImg.Image = img
 		canvasImg.Refresh()
 	}
 
+	// simulate is the generation loop of one run, defined below
+	var simulate func(ctx context.Context)
+
+	// runStopped resets the run buttons and unlocks the controls once a run
+	// is over
+	runStopped := func() {
+		startButton.SetText(lang.L("▶ Start"))
+		pauseButton.Disable()
+		supernovaButton.Disable()
+		patientZeroButton.Disable()
+		growthSlider.Enable()
+		mutationSlider.Enable()
+		pixelSlider.Enable()
+		gridSizeSelect.Enable()
+		paletteSelect.Enable()
+		ruleSelect.Enable()
+		scenarioButton.Enable()
+		imageGridButton.Enable()
+	}
+
 	startButton.OnTapped = func() {
+		state.mu.Lock()
+		defer state.mu.Unlock()
 		if !state.isStarted {
 			// Reset grid with new parameters
 			resetGrid()
-			
+
 			state.isStarted = true
 			state.isPaused = false
-			startButton.SetText("⏹ Stop")
+			rebirthHistory = rebirthHistory[:0]
+			history.reset()
+			resetTriggers(state.triggers)
+			state.catastrophes.next = 0
+			startButton.SetText(lang.L("⏹ Stop"))
 			pauseButton.Enable()
 			supernovaButton.Enable()
-			
+			patientZeroButton.Enable()
+
 			// Lock controls during simulation
 			growthSlider.Disable()
 			mutationSlider.Disable()
 			pixelSlider.Disable()
-			speedSlider.Disable()
+			gridSizeSelect.Disable()
 			paletteSelect.Disable()
-			
-			addEvent(state, "START", fmt.Sprintf("Simulation started (growth=%.2f, mutation=%.3f)", state.growthRate, state.mutationChance))
-			eventLog.SetText("Simulation running...")
+			ruleSelect.Disable()
+			scenarioButton.Disable()
+			imageGridButton.Disable()
+
+			addEvent(state, "START", fmt.Sprintf("Simulation started (growth=%.2f, mutation=%.3f, seed=%d)", state.growthRate, state.mutationChance, sim.seed))
+			if state.scenario != nil {
+				state.scenario.start(state.growthRate)
+				addEvent(state, "SCENARIO", "Challenge started: "+state.scenario.scenario.name)
+				if state.scenario.status == ScenarioFailed {
+					addEvent(state, "SCENARIO", state.scenario.message)
+				}
+				vm.scenario.Set(state.scenario.String())
+			}
+			vm.events.Set(lang.L("Simulation running..."))
+			tutorial.advance("start")
+
+			// Each run has its own goroutine, ended by Stop or on exit
+			runCtx, stop := context.WithCancel(life.ctx)
+			state.stopRun = stop
+			life.spawn(func() {
+				defer stop()
+				simulate(runCtx)
+			})
 		} else {
 			state.isStarted = false
 			state.isPaused = false
-			startButton.SetText("▶ Start")
-			pauseButton.SetText("Pause")
-			pauseButton.Disable()
-			supernovaButton.Disable()
-			
-			// Unlock controls
-			growthSlider.Enable()
-			mutationSlider.Enable()
-			pixelSlider.Enable()
-			speedSlider.Enable()
-			paletteSelect.Enable()
-			
+			state.stopRun()
+			pauseButton.SetText(lang.L("Pause"))
+			runStopped()
 			addEvent(state, "STOP", "Simulation stopped")
 		}
 	}
-	
+
 	pauseButton.OnTapped = func() {
+		state.mu.Lock()
+		defer state.mu.Unlock()
 		if !state.isStarted {
 			return
 		}
 		state.isPaused = !state.isPaused
 		if state.isPaused {
-			pauseButton.SetText("▶ Resume")
+			// The labels may lag a few generations behind the grid
+			vm.status.Set(runningStatus(state.stats, state.gridSize*state.gridSize))
+			pauseButton.SetText(lang.L("▶ Resume"))
 			addEvent(state, "PAUSE", "Simulation paused")
+			tutorial.advance("pause")
 		} else {
-			pauseButton.SetText("Pause")
+			pauseButton.SetText(lang.L("Pause"))
 			addEvent(state, "RESUME", "Simulation resumed")
 		}
 	}
-	
+
+	// Supernova: reset the area around a center; the caller holds state.mu
+	detonate = func(centerX, centerY int) {
+		radius := state.novaRadius
+		sim.supernova(centerX, centerY, radius, state.radiation)
+		addEvent(state, "SUPERNOVA", fmt.Sprintf("Explosion at (%d,%d) radius %d", centerX, centerY, radius))
+	}
+
 	supernovaButton.OnTapped = func() {
+		state.mu.Lock()
+		defer state.mu.Unlock()
 		if !state.isStarted {
 			return
 		}
-		// Supernova: reset random area
-		centerX := rng.Intn(state.gridSize)
-		centerY := rng.Intn(state.gridSize)
-		radius := 10 + rng.Intn(15)
-		
-		for y := 0; y < state.gridSize; y++ {
-			for x := 0; x < state.gridSize; x++ {
-				dx := x - centerX
-				dy := y - centerY
-				if dx*dx+dy*dy < radius*radius {
-					grid[y][x].val = 0
-				}
-			}
+		editGrid("supernova", func() { detonate(sim.randomCell()) })
+		tutorial.advance("supernova")
+	}
+
+	// Ctrl-click aims a supernova whatever the tool
+	gridDisplay.onAim = func(p image.Point) {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		x, y := state.view.cell(p, state.cellSize)
+		if !state.isStarted || x >= state.gridSize || y >= state.gridSize {
+			return
 		}
-		addEvent(state, "SUPERNOVA", fmt.Sprintf("Explosion at (%d,%d) radius %d", centerX, centerY, radius))
+		editGrid("supernova", func() { detonate(x, y) })
+		tutorial.advance("supernova")
+	}
+
+	// Shift-drags move the window over a grid larger than the display,
+	// carrying the fraction of a cell to the next drag
+	var panX, panY float32
+	gridDisplay.onPan = func(dx, dy float32) {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		panX, panY = panX-dx, panY-dy
+		cellsX, cellsY := int(panX)/state.cellSize, int(panY)/state.cellSize
+		if cellsX == 0 && cellsY == 0 {
+			return
+		}
+		panX -= float32(cellsX * state.cellSize)
+		panY -= float32(cellsY * state.cellSize)
+		state.view.pan(cellsX, cellsY, state.gridSize)
+		redrawPaused()
+	}
+
+	// Epidemic from a random patient zero; the caller holds state.mu
+	startEpidemic := func() {
+		if sim.rule != nil {
+			return
+		}
+		if p, ok := sim.infect(); ok {
+			addEvent(state, "INFECTION", fmt.Sprintf("Patient zero at (%d,%d)", p.X, p.Y))
+		}
+	}
+
+	patientZeroButton.OnTapped = func() {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		if !state.isStarted {
+			return
+		}
+		editGrid("patient zero", startEpidemic)
+	}
+
+	frames := newFrameBuffers(img.Bounds())
+
+	// Chart images are only drawn on the main thread, from copies of the data
+	refreshCharts := func(rebirths []int, ages [maxCellAge]int, palette ColorPalette) {
+		drawBarChart(rebirthImg, rebirths, color.RGBA{20, 20, 20, 255}, color.RGBA{255, 255, 255, 255})
+		rebirthChart.Refresh()
+		drawAgePyramid(pyramidImg, ages, palette, color.RGBA{20, 20, 20, 255})
+		pyramidChart.Refresh()
+		state.mu.Lock()
+		chartPane.render()
+		state.mu.Unlock()
+		chartPane.canvasImg.Refresh()
 	}
 
-	go func() {
-		ticker := time.NewTicker(10 * time.Millisecond)
-		defer ticker.Stop()
+	// The window follows the runs through the bus
+	state.bus.statsUpdated.subscribe(func(u statsUpdate) {
+		if u.labels == nil && !u.paused && u.scenario == "" {
+			return
+		}
+		runOnMain(driver, func() {
+			if t := u.labels; t != nil {
+				refreshCharts(t.rebirths, t.ages, t.palette)
+				if t.automated {
+					// The engine changed them in the model
+					commands.quietly(func() {
+						vm.growthRate.changed()
+						vm.mutationChance.changed()
+					})
+				}
+				vm.status.Set(t.status)
+				vm.stats.Set(t.stats)
+				if t.scenario != "" {
+					vm.scenario.Set(t.scenario)
+				}
+				vm.events.Set(t.events)
+			}
+			if u.triggered {
+				flashStatus()
+			}
+			if u.paused {
+				pauseButton.SetText(lang.L("▶ Resume"))
+			}
+			if u.scenario != "" {
+				vm.scenario.Set(u.scenario)
+				dialog.ShowInformation(lang.L("Scenario"), u.scenario, w)
+			}
+		})
+	})
+	state.bus.frameReady.subscribe(func(f frameReady) {
+		runOnMain(driver, func() {
+			img = frames.present(canvasImg, f.frame)
+			overlay.show(img)
+		})
+	})
+	state.bus.runEnded.subscribe(func(e runEnd) {
+		runOnMain(driver, func() {
+			refreshCharts(e.rebirths, e.ages, e.palette)
+			vm.status.Set(e.message)
+			runStopped()
+			img = frames.present(canvasImg, e.frame)
+			overlay.show(img)
+		})
+	})
+
+	simulate = func(ctx context.Context) {
+		// One generation per interval: the timer is re-armed as each
+		// generation starts, so the time spent computing it is not added
+		var perf perfMeter
+		interval := func() time.Duration {
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			switch state.speedMode {
+			case speedMax:
+				return 0
+			case speedAuto:
+				return autoInterval(perf.busy)
+			}
+			return time.Duration(state.speed) * time.Millisecond
+		}
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+		last := time.Now()
+		// Frames are drawn at most opts.fps times a second; the generations
+		// in between are computed, logged and checked but not drawn
+		frameInterval := time.Second / time.Duration(opts.fps)
+		var lastFrame time.Time
+		// Labels and charts are refreshed at most labelRate times a second
+		const labelRate = 10
+		var lastLabels time.Time
 
-		generation := 0
 		cycle := 0.0
-		frameCounter := 0
 
-		for range ticker.C {
-			if !state.isStarted || state.isPaused {
+		// drawFrame renders the grid as it stands into frame. The caller
+		// holds state.mu.
+		drawFrame := func(frame *image.RGBA) {
+			// Dynamic palette based on average age, unless frozen
+			if !state.paletteFrozen {
+				palette = generateDynamicPalette(rng, cycle+state.stats.avgAge*0.1*state.paletteSpeed, state.paletteMode)
+			}
+
+			// Draw offscreen; the frame is swapped in on the main thread
+			renderStart := time.Now()
+			if state.view.fit(state.gridSize, state.cellSize); !state.view.whole(state.gridSize) {
+				r, _ := renderer.(flatRenderer)
+				drawViewport(frame, sim, palette, r.colorBy, state.cellSize, state.view)
+				if state.gridLines {
+					drawGridLines(frame, state.cellSize, state.view.side, gridLineColor())
+				}
+				perf.render = smoothDuration(perf.render, time.Since(renderStart))
+				return
+			}
+			renderer.Render(sim, frame, palette, state.cellSize)
+			if _, flat := renderer.(flatRenderer); flat && state.deadGhosts && sim.rule == nil {
+				drawGhosts(frame, sim, palette, state.cellSize)
+			}
+			if _, flat := renderer.(flatRenderer); flat && showNutrients && sim.nutrients != nil && sim.rule == nil {
+				drawNutrients(frame, sim.grid, sim.nutrients, palette.dead, state.cellSize)
+			}
+			if _, stereo := renderer.(stereoRenderer); state.gridLines && !stereo {
+				drawGridLines(frame, state.cellSize, state.gridSize, gridLineColor())
+			}
+
+			if rebirthFlash {
+				drawRebirthFlash(frame, sim.reborn, state.cellSize)
+			}
+			if _, stereo := renderer.(stereoRenderer); !stereo {
+				if _, flat := renderer.(flatRenderer); flat && sim.infection != nil {
+					drawInfected(frame, sim.infection, state.gridSize, state.cellSize)
+				}
+				if sim.radiation != nil {
+					drawRadiation(frame, sim.radiation, state.gridSize, state.cellSize)
+				}
+				if sim.fixtures != nil {
+					drawFixtures(frame, sim.fixtures, state.gridSize, state.cellSize)
+				}
+				if sim.zones != nil && sim.rule == nil {
+					drawZoneBorders(frame, sim.zones, state.gridSize, state.cellSize)
+				}
+				drawAnts(frame, sim.ants, state.cellSize)
+			}
+			perf.render = smoothDuration(perf.render, time.Since(renderStart))
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-speedChanged:
+				// Wait for the new interval from the last generation
+				timer.Reset(time.Until(last.Add(interval())))
 				continue
+			case <-timer.C:
 			}
-			
-			// Speed control via counter
-			frameCounter++
-			if frameCounter < state.speed/10 {
+			last = time.Now()
+			timer.Reset(interval())
+			state.mu.Lock()
+			running := state.isStarted && !state.isPaused
+			shot := state.timelapse.due(sim.generation + 1)
+			state.mu.Unlock()
+			if !running {
 				continue
 			}
-			frameCounter = 0
-			
-			generation++
-			cycle += 0.05
-			
+			perf.generation(last)
+
+			// Wait for the offscreen buffer before taking the lock: the UI
+			// thread may need the lock before it can hand the buffer back.
+			var frame *image.RGBA
+			if shot || last.Sub(lastFrame) >= frameInterval {
+				if frame = frames.acquire(ctx); frame == nil {
+					return
+				}
+				lastFrame = last
+			}
+			state.mu.Lock()
+			if ctx.Err() != nil || !state.isStarted || state.isPaused {
+				state.mu.Unlock()
+				if frame != nil {
+					frames.release(frame)
+				}
+				continue
+			}
+
+			cycle += 0.05 * state.paletteSpeed
+
 			totalCells := state.gridSize * state.gridSize
-			
-			// Random events
-			if rng.Float64() < state.mutationChance {
-				// Genetic mutation
-				for i := 0; i < 5+rng.Intn(10); i++ {
-					x := rng.Intn(state.gridSize)
-					y := rng.Intn(state.gridSize)
-					if grid[y][x].val > 0 {
-						grid[y][x].val = 1 + rng.Intn(20)
+
+			// Scripted parameter curves
+			for _, msg := range state.automation.apply(state, sim.generation+1) {
+				addEvent(state, "AUTOMATION", msg)
+			}
+
+			// Random events and evolution
+			sim.growthRate = state.growthRate
+			sim.mutationChance = state.mutationChance
+			sim.temperature = state.temperature
+			sim.ageCurves = state.ageCurves
+			sim.birthCurve = state.birthCurve
+			sim.setMetabolism(state.metabolism)
+			sim.setNutrients(state.nutrients)
+			sim.seasonPeriod = state.seasonPeriod
+			sim.drift = newDriftWeights(state.driftDirection, state.driftStrength)
+			sim.setRuleDrift(state.ruleDrift)
+			if state.zonesChanged {
+				sim.setZones(state.zoneLayout)
+				state.zonesChanged = false
+			}
+			sim.climates = state.climates
+			sim.movementRate = state.movementRate
+			sim.immigration = state.immigration
+			sim.entryEdge = state.entryEdge
+			sim.infectionRate = state.infectionRate
+			sim.infectionSpan = state.infectionSpan
+			sim.symmetry = state.symmetry
+			if state.profile != nil {
+				if done, err := state.profile.generation(); done {
+					if err != nil {
+						addEvent(state, "PROFILE", "Profile failed: "+err.Error())
+					} else {
+						addEvent(state, "PROFILE", "Profiles written to "+state.profile.dir)
 					}
+					state.profile = nil
 				}
+			}
+
+			stepStart := time.Now()
+			if sim.step() {
 				addEvent(state, "MUTATION", "Genetic mutations detected")
 			}
-			
-			evolve(grid, rng, state.growthRate)
-			
-			// Calculate stats
-			state.stats = calculateStats(grid, generation, state.gridSize)
-			
-			// Dynamic palette based on average age
-			palette = generateDynamicPalette(rng, cycle+state.stats.avgAge*0.1, state.paletteMode)
-			
-			drawGridDynamic(grid, img, palette, state.cellSize, state.gridSize)
-			
-			// Bloom effect
-			if state.bloomEffect {
-				applyBloom(img, 0.3)
-			}
-
-			if state.stats.population >= totalCells {
-				finalMessage := fmt.Sprintf("COMPLETED - Generation %d - Grid filled!", generation)
+			perf.step = smoothDuration(perf.step, time.Since(stepStart))
+			for _, msg := range sim.colonyEvents {
+				addEvent(state, "COLONY", msg)
+			}
+			for _, e := range sim.ruleEvents {
+				addEvent(state, e.kind, e.msg)
+			}
+			if name, began := sim.season(); began {
+				addEvent(state, "SEASON", name+" begins")
+			}
+			if state.catastrophes.due(sim.generation, sim.rng) {
+				switch state.catastrophes.kind {
+				case "Epidemic":
+					startEpidemic()
+				default:
+					detonate(sim.randomCell())
+				}
+			}
+			generation := sim.generation
+			state.stats = sim.stats
+
+			if frame != nil {
+				drawFrame(frame)
+			}
+
+			// Post-processing effects (bloom, scanlines, CRT...) run after
+			// the lock is released, on a copy of the settings
+			effects := state.effects.snapshot()
+			framePalette := palette
+
+			rebirthHistory = append(rebirthHistory, state.stats.rebirths)
+			if len(rebirthHistory) > displaySize/2 {
+				rebirthHistory = rebirthHistory[1:]
+			}
+			history.add(state.stats, state.growthRate, state.mutationChance, totalCells)
+
+			// Challenge evaluation, including the generation that fills the grid
+			update := statsUpdate{stats: state.stats}
+			scenarioText := ""
+			if state.scenario != nil {
+				done := state.scenario.update(state.stats, sim.isFull())
+				scenarioText = state.scenario.String()
+				if done {
+					addEvent(state, "SCENARIO", state.scenario.message)
+					update.scenario = scenarioText
+					if !sim.isFull() {
+						state.isPaused = true
+						update.paused = true
+					}
+				}
+			}
+
+			if sim.isFull() {
+				if frame == nil {
+					// The full grid is always drawn
+					state.mu.Unlock()
+					if frame = frames.acquire(ctx); frame == nil {
+						return
+					}
+					state.mu.Lock()
+					drawFrame(frame)
+					framePalette = palette
+				}
+				end := runEnd{
+					message:  fmt.Sprintf(lang.L("COMPLETED - Generation %d - Grid filled!"), generation),
+					frame:    frame,
+					rebirths: append([]int(nil), rebirthHistory...),
+					ages:     state.stats.ageHistogram,
+					palette:  framePalette,
+				}
 				addEvent(state, "END", "Maximum population reached")
 				state.isStarted = false
-				generation = 0
-				runOnMain(driver, func() {
-					statusLabel.SetText(finalMessage)
-					startButton.SetText("▶ Start")
-					pauseButton.Disable()
-					supernovaButton.Disable()
-					growthSlider.Enable()
-					mutationSlider.Enable()
-					pixelSlider.Enable()
-					speedSlider.Enable()
-					paletteSelect.Enable()
-					canvasImg.Refresh()
-				})
-				continue
+				state.mu.Unlock()
+				state.bus.statsUpdated.publish(update)
+				effects.apply(frame, framePalette)
+				state.bus.runEnded.publish(end)
+				return
+			}
+
+			// User-defined conditions
+			fired := checkTriggers(state.triggers, state.stats)
+			pausedByTrigger := false
+			for _, t := range fired {
+				addEvent(state, "TRIGGER", "Condition met: "+t.String())
+				if t.pause {
+					pausedByTrigger = true
+				}
+			}
+			if pausedByTrigger {
+				state.isPaused = true
+				update.paused = true
+				addEvent(state, "PAUSE", "Simulation paused by trigger")
 			}
-			
+			update.triggered = len(fired) > 0
+
 			// Detection of remarkable events
 			if state.stats.density > 0.9 && generation%50 == 0 {
 				addEvent(state, "DENSITY", fmt.Sprintf("Critical density: %.1f%%", state.stats.density*100))
 			}
 
-			runningMessage := fmt.Sprintf("Gen %d - Pop %d/%d (%.1f%%) - Avg age: %.1f - Entropy: %.3f",
-				generation, state.stats.population, totalCells, state.stats.density*100, state.stats.avgAge, state.stats.entropy)
-			
-			statsText := fmt.Sprintf("Population: %d\nDensity: %.1f%%\nAvg age: %.1f\nEntropy: %.3f",
-				state.stats.population, state.stats.density*100, state.stats.avgAge, state.stats.entropy)
-			
-			eventText := ""
-			for i := len(state.events) - 1; i >= 0 && i >= len(state.events)-3; i-- {
-				e := state.events[i]
-				eventText += fmt.Sprintf("[Gen %d] %s: %s\n", e.generation, e.eventType, e.message)
-			}
-			
-			runOnMain(driver, func() {
-				statusLabel.SetText(runningMessage)
-				statsLabel.SetText(statsText)
-				eventLog.SetText(eventText)
-				canvasImg.Refresh()
-			})
+			if generation%perfLogInterval == 0 {
+				logPerf(generation, &perf)
+			}
+			if state.recording != nil && !state.recording.capture(sim.grid, palette) {
+				addEvent(state, "RECORDING", fmt.Sprintf("Recording full at %d generations", maxAnimationFrames))
+				state.recording = nil
+			}
+
+			// Text is only built for the label refreshes, and when a trigger
+			// fires
+			if last.Sub(lastLabels) >= time.Second/labelRate || len(fired) > 0 {
+				lastLabels = last
+				runningMessage := runningStatus(state.stats, totalCells)
+
+				statsText := fmt.Sprintf(lang.L("Population: %d\nDensity: %.1f%%\nAvg age: %.1f\nEntropy: %.3f\nRebirths: %d (total %d)"),
+					state.stats.population, state.stats.density*100, state.stats.avgAge, state.stats.entropy,
+					state.stats.rebirths, sim.totalRebirths)
+				statsText += "\n" + colonySummary(sim.colonySizes)
+				if name, _ := sim.season(); name != "" {
+					statsText += fmt.Sprintf(lang.L("\nSeason: %s"), lang.L(name))
+				}
+				if sim.ruleDrift && sim.rule == nil {
+					statsText += fmt.Sprintf(lang.L("\nRule: %s"), sim.thresholds)
+				}
+				if sim.zones != nil && sim.rule == nil {
+					statsText += "\n" + zoneSummary(zonePopulations(sim.grid, sim.zones))
+				}
+				if sim.energy != nil {
+					statsText += fmt.Sprintf(lang.L("\nEnergy: avg %.2f - starved %d"), state.stats.avgEnergy, state.stats.starved)
+				}
+				if sim.infection != nil || state.stats.diseaseDeaths > 0 {
+					statsText += fmt.Sprintf(lang.L("\nInfected: %d - killed %d"), state.stats.infected, state.stats.diseaseDeaths)
+				}
+				if _, ok := sim.rule.(*wator); ok {
+					statsText += fmt.Sprintf(lang.L("\nFish: %d - Sharks: %d"), state.stats.prey, state.stats.predators)
+				}
+				if id, age := sim.colonies.oldest(generation); id > 0 {
+					statsText += fmt.Sprintf(lang.L("\nOldest colony: #%d (%d gens)"), id, age)
+				}
+				if sim.rule == nil {
+					statsText += "\n" + sim.lineages.summary(generation)
+				}
+				if perfHUD {
+					statsText += "\n" + perf.String()
+				}
+				eventText := ""
+				for _, e := range state.events.recent(3) {
+					eventText += e.String() + "\n"
+				}
+				update.labels = &statsLabels{
+					status:    runningMessage,
+					stats:     statsText,
+					events:    eventText,
+					scenario:  scenarioText,
+					rebirths:  append([]int(nil), rebirthHistory...),
+					ages:      state.stats.ageHistogram,
+					palette:   framePalette,
+					automated: state.automation.enabled,
+				}
+			}
+
+			// Periodic checkpoint, written once the lock is released
+			var checkpoint *Checkpoint
+			if checkpoints != nil && opts.checkpoint > 0 && generation%opts.checkpoint == 0 {
+				cp := captureCheckpoint(sim, state)
+				checkpoint = &cp
+			}
+
+			var timelapseShot *timelapse
+			if frame != nil && state.timelapse.due(generation) {
+				timelapseShot = state.timelapse
+			}
+
+			state.mu.Unlock()
+			state.bus.statsUpdated.publish(update)
+			if checkpoint != nil {
+				if err := checkpoints.save(*checkpoint); err != nil {
+					state.mu.Lock()
+					addEvent(state, "CHECKPOINT", "Checkpoint failed: "+err.Error())
+					state.mu.Unlock()
+				}
+			}
+
+			if frame == nil {
+				perf.busy = smoothDuration(perf.busy, time.Since(last))
+				continue
+			}
+			effectsStart := time.Now()
+			effects.apply(frame, framePalette)
+			perf.effects = smoothDuration(perf.effects, time.Since(effectsStart))
+			if timelapseShot != nil {
+				if err := timelapseShot.save(frame, generation); err != nil {
+					state.mu.Lock()
+					addEvent(state, "TIMELAPSE", "Timelapse frame failed: "+err.Error())
+					state.mu.Unlock()
+				}
+			}
+			state.bus.frameReady.publish(frameReady{frame: frame, generation: generation})
+			perf.frame = smoothDuration(perf.frame, time.Since(last))
+			perf.busy = smoothDuration(perf.busy, time.Since(last))
 		}
-	}()
+	}
 
+	if recovered != nil {
+		message := fmt.Sprintf(lang.L("The previous session did not exit cleanly.\nResume its run from generation %d (saved %s)?"),
+			recovered.Generation, recovered.Saved.Format("2006-01-02 15:04"))
+		dialog.ShowConfirm(lang.L("Resume from checkpoint"), message, func(resume bool) {
+			if !resume {
+				if opts.autostart {
+					startButton.OnTapped()
+				}
+				return
+			}
+			// Resuming is not a change to undo
+			commands.quietly(func() {
+				growthSlider.SetValue(clampFloat(recovered.GrowthRate, 0.05, 0.5))
+				mutationSlider.SetValue(clampFloat(recovered.MutationChance, 0, 0.1))
+				pixelSlider.SetValue(float64(clampInt(recovered.CellSize, 2, 8)))
+				if i := slices.Index(largeGridSizes, recovered.GridSize); i > 0 {
+					gridSizeSelect.SetSelected(lang.L(gridSizeNames()[i]))
+				}
+				speedSlider.SetValue(float64(clampInt(recovered.Speed, 10, 200)))
+				if recovered.PaletteMode >= 0 && recovered.PaletteMode < len(paletteModeNames) {
+					paletteSelect.SetSelected(lang.L(paletteModeNames[recovered.PaletteMode]))
+				}
+				symmetrySelect.SetSelected(lang.L(symmetryFromName(recovered.Symmetry).String()))
+				if newRuleFamily(recovered.Rule) != nil {
+					ruleSelect.SetSelected(lang.L(recovered.Rule))
+				} else {
+					ruleSelect.SetSelected(lang.L(ruleFamilies[0].name))
+				}
+			})
+			state.mu.Lock()
+			if recovered.GridSize == state.gridSize {
+				resumeFrom = recovered
+			}
+			state.mu.Unlock()
+			startButton.OnTapped()
+		}, w)
+	} else if opts.autostart {
+		startButton.OnTapped()
+	}
 	w.ShowAndRun()
+
+	// Let the running goroutines finish their current write, then complete
+	// the session's event file
+	if !life.shutdown() {
+		slog.Warn("background tasks still running at exit")
+	}
+	state.mu.Lock()
+	if state.profile != nil {
+		if err := state.profile.stop(); err != nil {
+			slog.Error("cannot write the profile", "err", err)
+		}
+		state.profile = nil
+	}
+	state.mu.Unlock()
+	if err := state.events.flush(); err != nil {
+		slog.Error("cannot save the event history", "err", err)
+	}
+	if checkpoints != nil {
+		if err := checkpoints.end(); err != nil {
+			slog.Warn("cannot clear the running marker", "err", err)
+		}
+	}
 }
 
-func drawGridDynamic(grid [][]Cell, img *image.RGBA, palette ColorPalette, cellSize int, gridSize int) {
-	for y := 0; y < gridSize; y++ {
-		for x := 0; x < gridSize; x++ {
-			c := getCellColor(grid[y][x].val, palette)
-			for dy := 0; dy < cellSize; dy++ {
-				for dx := 0; dx < cellSize; dx++ {
-					img.Set(x*cellSize+dx, y*cellSize+dy, c)
-				}
+// maxCellAge is the highest age a cell reaches before being reborn.
+const maxCellAge = 50
+
+// cellColorTable is the palette's color table as pixel bytes, for writing
+// straight into img.Pix.
+func cellColorTable(palette ColorPalette) [maxCellAge + 1][4]uint8 {
+	var lut [maxCellAge + 1][4]uint8
+	for v, c := range palette.colors {
+		lut[v] = [4]uint8{c.R, c.G, c.B, c.A}
+	}
+	return lut
+}
+
+// drawGridDynamic draws the grid colored by age, see drawCells.
+func drawGridDynamic(grid *Grid, img *image.RGBA, palette ColorPalette, cellSize int, gridSize int) {
+	drawCells(img, cellSize, gridSize, ageColors(grid, palette))
+	if palette.patterned {
+		drawBandPatterns(img, grid, palette, cellSize)
+	}
+}
+
+// drawCells writes one color per cell straight into img.Pix: the first pixel
+// row of each cell row is filled with the cells' colors, then copied to the
+// other rows of the cell. Cells falling outside img are cropped.
+func drawCells(img *image.RGBA, cellSize, gridSize int, colors cellColors) {
+	bounds := img.Bounds()
+	width := min(gridSize*cellSize, bounds.Dx())
+	height := min(gridSize*cellSize, bounds.Dy())
+	if width <= 0 || height <= 0 {
+		return
+	}
+	for y := 0; y*cellSize < height; y++ {
+		top := img.PixOffset(bounds.Min.X, bounds.Min.Y+y*cellSize)
+		row := img.Pix[top : top+width*4]
+		for x := 0; x*cellSize < width; x++ {
+			c := colors(x, y)
+			end := min((x+1)*cellSize, width) * 4
+			for i := x * cellSize * 4; i < end; i += 4 {
+				copy(row[i:i+4], c[:])
 			}
 		}
+		for dy := 1; dy < cellSize && y*cellSize+dy < height; dy++ {
+			off := top + dy*img.Stride
+			copy(img.Pix[off:off+width*4], row)
+		}
 	}
 }
 
-func drawGrid(grid [][]Cell, img *image.RGBA, palette ColorPalette) {
+func drawGrid(grid *Grid, img *image.RGBA, palette ColorPalette) {
 	drawGridDynamic(grid, img, palette, currentCellSize, currentGridSize)
 }
 
 func getCellColor(val int, palette ColorPalette) color.Color {
-	if val == 0 {
-		return palette.dead
-	} else if val < 5 {
-		return palette.young[val-1]
-	} else if val < 20 {
-		return palette.mature[val-5]
-	} else {
-		idx := val - 20
-		if idx >= len(palette.old) {
-			idx = len(palette.old) - 1
+	return palette.colors[min(max(val, 0), maxCellAge)]
+}
+
+// fillColors resolves the color of every cell value from the age bands, as
+// pre-multiplied RGBA, so drawing a cell is a table lookup. It runs once the
+// bands are final.
+func (p *ColorPalette) fillColors() {
+	for val := range p.colors {
+		var c color.Color
+		switch {
+		case val == 0:
+			c = p.dead
+		case val < 5:
+			c = p.young[val-1]
+		case val < 20:
+			c = p.mature[val-5]
+		default:
+			c = p.old[min(val-20, len(p.old)-1)]
 		}
-		return palette.old[idx]
+		p.colors[val] = color.RGBAModel.Convert(c).(color.RGBA)
 	}
 }
 
-func evolve(g [][]Cell, rng *rand.Rand, growthRate float64) {
-	h := len(g)
-	w := len(g[0])
-	newGrid := make([][]Cell, h)
-	for y := range newGrid {
-		newGrid[y] = make([]Cell, w)
-		for x := range newGrid[y] {
-			sum := neighbors(g, x, y)
-			val := g[y][x].val
-			if val == 0 && rng.Float64() < growthRate*(float64(sum)/50) {
+// evolve writes the generation following g into next, a grid of the same
+// size, and returns the births and the rebirths (age 50 wrapping back to 1),
+// marking the reborn cells in reborn if it is non-nil. Temperature, curves,
+// nutrients, drift and zones each reshape the chances, see evolveParams.
+func evolve(g, next *Grid, rng Rand, params evolveParams, reborn [][]bool) (births, rebirths int) {
+	weighted := params.curves.fertility != defaultAgeCurves.fertility || params.drift != nil
+	shaped := params.birth != defaultBirthCurve
+	sums := params.sums
+	if sums == nil {
+		sums = &summedArea{}
+	}
+	sums.build(g)
+	for y := range next.size {
+		row := next.row(y)
+		for x := range row {
+			growthRate, th := params.growthRate, params.thresholds
+			if params.zones != nil {
+				c := params.climates[params.zones[y*g.size+x]]
+				growthRate, th = growthRate*c.growth, c.thresholds
+			}
+			val := g.at(x, y)
+			old := val
+			sum := sums.around(x, y, 1, val)
+			fertile := float64(sum)
+			if weighted {
+				fertile = params.curves.fertileNeighbors(g, x, y, params.drift)
+			}
+			wrapped := false
+			weight := fertile / 50
+			if shaped {
+				weight = params.birth.weight(fertile)
+			}
+			chance := growthRate * weight
+			if params.nutrients != nil {
+				chance *= params.nutrients[y*g.size+x]
+			}
+			if val == 0 && rng.Float64() < chance {
 				val = 1
+				births++
 			} else if val > 0 {
-				if sum < 3 {
+				survival := params.curves.survival[cellBand(val)-1]
+				if exceeds(rng, th.survival-float64(sum), params.temperature) || survival < 1 && rng.Float64() >= survival {
 					val = 0
-				} else if sum > 20 {
+				} else if exceeds(rng, float64(sum)-th.aging-params.agingShift, params.temperature) {
 					val++
 					if val > 50 {
 						val = 1
+						wrapped = true
+						rebirths++
 					}
 				}
 			}
-			newGrid[y][x].val = val
+			row[x] = uint8(val)
+			if params.tally != nil {
+				params.tally.change(old, val)
+			}
+			if reborn != nil {
+				reborn[y][x] = wrapped
+			}
 		}
 	}
-	for y := range g {
-		copy(g[y], newGrid[y])
+	return births, rebirths
+}
+
+// evolveParams are the parameters of the built-in rules; see exceeds for
+// the fuzzy thresholds of a temperature above 0.
+type evolveParams struct {
+	growthRate  float64
+	temperature float64
+	curves      ageCurves
+	birth       birthCurve
+	nutrients   []float64 // scales the birth chance of each cell, nil for none
+	agingShift  float64   // added to the ageing threshold
+	drift       *driftWeights
+	thresholds  thresholds
+	zones       []uint8     // zone of each cell, nil for none
+	climates    []climate   // of each zone
+	sums        *summedArea // neighbour sums of the current grid, rebuilt by evolve
+	tally       *cellTally  // of the current grid, updated to the next; nil for none
+}
+
+// exceeds decides a threshold rule for a neighbour sum lying margin beyond
+// the threshold (negative when short of it). At temperature 0 it is the plain
+// comparison; above, it holds with the logistic probability
+// 1/(1+exp(-margin/temperature)), so sums near the threshold go either way.
+func exceeds(rng Rand, margin, temperature float64) bool {
+	if temperature <= 0 {
+		return margin > 0
 	}
+	return rng.Float64() < 1/(1+math.Exp(-margin/temperature))
 }
 
-func neighbors(g [][]Cell, x, y int) int {
-	h := len(g)
-	w := len(g[0])
+func neighbors(g *Grid, x, y int) int {
+	n := g.size
 	sum := 0
-	for dy := -1; dy <= 1; dy++ {
-		for dx := -1; dx <= 1; dx++ {
-			if dx == 0 && dy == 0 {
-				continue
-			}
-			ny := y + dy
-			nx := x + dx
-			if nx >= 0 && ny >= 0 && nx < w && ny < h {
-				sum += g[ny][nx].val
-			}
+	for ny := max(y-1, 0); ny <= min(y+1, n-1); ny++ {
+		row := g.cells[ny*n : ny*n+n]
+		for nx := max(x-1, 0); nx <= min(x+1, n-1); nx++ {
+			sum += int(row[nx])
 		}
 	}
-	return sum
+	return sum - g.at(x, y)
 }
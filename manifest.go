@@ -0,0 +1,275 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log/slog"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifest is an experiment for -run-manifest: the seeds of its runs, their
+// parameters and generation limit, and the files they produce. It is read
+// from YAML, or from JSON, which is YAML too; unset fields keep the
+// defaults of newManifest.
+type manifest struct {
+	Name        string          `yaml:"name"`
+	Seeds       []int64         `yaml:"seeds"` // or runs seeds from seed on
+	Seed        int64           `yaml:"seed"`  // 0 picks a random one
+	Runs        int             `yaml:"runs"`
+	Generations int             `yaml:"generations"` // limit of each run
+	GridSize    int             `yaml:"grid_size"`   // in cells
+	CellSize    int             `yaml:"cell_size"`   // in pixels, of the images
+	Parameters  manifestParams  `yaml:"parameters"`
+	Outputs     manifestOutputs `yaml:"outputs"`
+}
+
+// manifestParams are the parameters of a manifest's runs, named like the
+// controls.
+type manifestParams struct {
+	Rule            string    `yaml:"rule"`        // a rule family, see ruleFamilies
+	RuleParams      []float64 `yaml:"rule_params"` // in the order of the family's parameters
+	GrowthRate      float64   `yaml:"growth_rate"`
+	MutationChance  float64   `yaml:"mutation_chance"`
+	Temperature     float64   `yaml:"temperature"`
+	SeasonPeriod    int       `yaml:"season_period"`
+	DriftDirection  string    `yaml:"drift_direction"` // see driftCompass
+	DriftStrength   float64   `yaml:"drift_strength"`
+	MovementRate    float64   `yaml:"movement_rate"`
+	Immigration     float64   `yaml:"immigration"`
+	ImmigrationEdge string    `yaml:"immigration_edge"` // see immigrationEdges
+	Metabolism      bool      `yaml:"metabolism"`
+	Nutrients       bool      `yaml:"nutrients"`
+	Palette         string    `yaml:"palette"` // see paletteNames
+}
+
+// manifestOutputs are the files a manifest's runs produce, relative to its
+// directory; empty ones are not written. In the per-run files {seed} is
+// replaced by the seed of the run, and is required when there are several.
+type manifestOutputs struct {
+	Summary  string `yaml:"summary_csv"` // the -batch summary of all the runs
+	StatsCSV string `yaml:"stats_csv"`   // per run, one generation per row
+	FinalPNG string `yaml:"final_png"`   // per run, its last generation
+	GIF      string `yaml:"gif"`         // per run, its first maxAnimationFrames generations
+}
+
+// driftCompass names the drift directions in manifests, in the order of
+// driftDirections.
+var driftCompass = []string{"n", "ne", "e", "se", "s", "sw", "w", "nw"}
+
+func newManifest() manifest {
+	return manifest{
+		Runs:        1,
+		Generations: 2000,
+		GridSize:    gridSide(0, defaultLaunchOptions.cellSize),
+		CellSize:    defaultLaunchOptions.cellSize,
+		Parameters: manifestParams{
+			Rule:            ruleFamilies[0].name,
+			GrowthRate:      defaultLaunchOptions.growthRate,
+			MutationChance:  defaultLaunchOptions.mutationChance,
+			DriftDirection:  driftCompass[0],
+			ImmigrationEdge: immigrationEdges[0],
+			Palette:         paletteNames[0],
+		},
+	}
+}
+
+// readManifest decodes a manifest, rejecting unknown fields and values
+// outside the ranges of the controls.
+func readManifest(r io.Reader) (manifest, error) {
+	m := newManifest()
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return m, err
+	}
+	p := m.Parameters
+	switch {
+	case m.Generations < 1:
+		return m, fmt.Errorf("generations must be positive, got %d", m.Generations)
+	case m.GridSize < 4 || m.GridSize > largeGridSizes[len(largeGridSizes)-1]:
+		return m, fmt.Errorf("grid_size must be between 4 and %d, got %d", largeGridSizes[len(largeGridSizes)-1], m.GridSize)
+	case m.CellSize < 1 || m.CellSize > 8:
+		return m, fmt.Errorf("cell_size must be between 1 and 8, got %d", m.CellSize)
+	case len(m.Seeds) == 0 && m.Runs < 1:
+		return m, fmt.Errorf("runs must be positive, got %d", m.Runs)
+	case slices.Contains(m.Seeds, 0):
+		return m, errors.New("seeds must not be 0")
+	case !slices.Contains(ruleFamilyNames(), p.Rule):
+		return m, fmt.Errorf("unknown rule %q", p.Rule)
+	case p.GrowthRate < 0.05 || p.GrowthRate > 0.5:
+		return m, fmt.Errorf("growth_rate must be between 0.05 and 0.5, got %g", p.GrowthRate)
+	case p.MutationChance < 0 || p.MutationChance > 0.1:
+		return m, fmt.Errorf("mutation_chance must be between 0 and 0.1, got %g", p.MutationChance)
+	case p.Temperature < 0 || p.Temperature > 5:
+		return m, fmt.Errorf("temperature must be between 0 and 5, got %g", p.Temperature)
+	case p.SeasonPeriod < 0:
+		return m, fmt.Errorf("season_period must not be negative, got %d", p.SeasonPeriod)
+	case !slices.Contains(driftCompass, strings.ToLower(p.DriftDirection)):
+		return m, fmt.Errorf("drift_direction must be one of %s, got %q", strings.Join(driftCompass, ", "), p.DriftDirection)
+	case p.DriftStrength < 0 || p.DriftStrength > 1:
+		return m, fmt.Errorf("drift_strength must be between 0 and 1, got %g", p.DriftStrength)
+	case p.MovementRate < 0 || p.MovementRate > 1:
+		return m, fmt.Errorf("movement_rate must be between 0 and 1, got %g", p.MovementRate)
+	case p.Immigration < 0 || p.Immigration > 0.2:
+		return m, fmt.Errorf("immigration must be between 0 and 0.2, got %g", p.Immigration)
+	case !slices.Contains(immigrationEdges, p.ImmigrationEdge):
+		return m, fmt.Errorf("immigration_edge must be one of %s, got %q", strings.Join(immigrationEdges, ", "), p.ImmigrationEdge)
+	case !slices.Contains(paletteNames, p.Palette):
+		return m, fmt.Errorf("unknown palette %q", p.Palette)
+	}
+	several := len(m.Seeds) > 1 || len(m.Seeds) == 0 && m.Runs > 1
+	for _, path := range []string{m.Outputs.StatsCSV, m.Outputs.FinalPNG, m.Outputs.GIF} {
+		if several && path != "" && !strings.Contains(path, "{seed}") {
+			return m, fmt.Errorf("output %q needs {seed} in its name, for the runs to write different files", path)
+		}
+	}
+	return m, nil
+}
+
+// seeds returns the seeds of the runs, the listed ones or runs from seed
+// on, a random seed when none is given.
+func (m manifest) seeds() []int64 {
+	if len(m.Seeds) > 0 {
+		return m.Seeds
+	}
+	base := m.Seed
+	if base == 0 {
+		base = rand.Int63()
+	}
+	seeds := make([]int64, m.Runs)
+	for i := range seeds {
+		seeds[i] = base + int64(i)
+	}
+	return seeds
+}
+
+// simulation returns the simulation of a run, seeded with seed.
+func (m manifest) simulation(seed int64) *Simulation {
+	p := m.Parameters
+	sim := newSimulation(m.GridSize, 0)
+	sim.rule = newRuleFamily(p.Rule)
+	sim.reset(seed)
+	if p.RuleParams != nil {
+		tuneRule(sim.rule, p.RuleParams)
+	}
+	sim.growthRate = p.GrowthRate
+	sim.mutationChance = p.MutationChance
+	sim.temperature = p.Temperature
+	sim.setMetabolism(p.Metabolism)
+	sim.setNutrients(p.Nutrients)
+	sim.seasonPeriod = p.SeasonPeriod
+	sim.drift = newDriftWeights(slices.Index(driftCompass, strings.ToLower(p.DriftDirection)), p.DriftStrength)
+	sim.movementRate = p.MovementRate
+	sim.immigration = p.Immigration
+	sim.entryEdge = slices.Index(immigrationEdges, p.ImmigrationEdge)
+	return sim
+}
+
+// runManifest runs the experiment of m, writing its outputs under dir, and
+// returns its summary and the seeds of its runs.
+func runManifest(m manifest, dir string) (BatchSummary, []int64, error) {
+	paletteMode := slices.Index(paletteNames, m.Parameters.Palette)
+	output := func(name string, seed int64) string {
+		return filepath.Join(dir, strings.ReplaceAll(name, "{seed}", strconv.FormatInt(seed, 10)))
+	}
+	seeds := m.seeds()
+	var runs []BatchRun
+	for _, seed := range seeds {
+		sim := m.simulation(seed)
+		paletteRng := rand.New(rand.NewSource(seed))
+		cycle := 0.0
+		palette := generateDynamicPalette(paletteRng, cycle, paletteMode)
+		var history StatsHistory
+		var recording *animation
+		if m.Outputs.GIF != "" {
+			recording = newAnimation(m.GridSize, m.CellSize, 50*time.Millisecond)
+			recording.capture(sim.grid, palette)
+		}
+		run := measureRun(sim, m.Generations, func() {
+			cycle += 0.05
+			palette = generateDynamicPalette(paletteRng, cycle+sim.stats.avgAge*0.1, paletteMode)
+			if m.Outputs.StatsCSV != "" {
+				history.add(sim.stats, sim.growthRate, sim.mutationChance, m.GridSize*m.GridSize)
+			}
+			if recording != nil {
+				recording.capture(sim.grid, palette)
+			}
+		})
+		runs = append(runs, run)
+		slog.Info("manifest run done", "seed", seed, "generations", run.generations, "filled", run.filled, "extinct", run.extinct)
+
+		if m.Outputs.StatsCSV != "" {
+			if err := writeFileWith(output(m.Outputs.StatsCSV, seed), history.writeCSV); err != nil {
+				return BatchSummary{}, seeds, err
+			}
+		}
+		if m.Outputs.FinalPNG != "" {
+			img := image.NewRGBA(image.Rect(0, 0, m.GridSize*m.CellSize, m.GridSize*m.CellSize))
+			drawGridDynamic(sim.grid, img, palette, m.CellSize, m.GridSize)
+			if err := writeFileWith(output(m.Outputs.FinalPNG, seed), func(w io.Writer) error { return png.Encode(w, img) }); err != nil {
+				return BatchSummary{}, seeds, err
+			}
+		}
+		if recording != nil {
+			frames := recording.frames(m.CellSize, 1)
+			if err := writeFileWith(output(m.Outputs.GIF, seed), func(w io.Writer) error { return writeGIF(w, frames, recording.delay) }); err != nil {
+				return BatchSummary{}, seeds, err
+			}
+		}
+	}
+
+	summary := summarizeBatch(runs)
+	if m.Outputs.Summary != "" {
+		cfg := BatchConfig{
+			growthRate:     m.Parameters.GrowthRate,
+			mutationChance: m.Parameters.MutationChance,
+			runs:           len(seeds),
+			generations:    m.Generations,
+			gridSize:       m.GridSize,
+			seed:           seeds[0],
+		}
+		err := writeFileWith(output(m.Outputs.Summary, seeds[0]), func(w io.Writer) error {
+			return writeBatchCSV(w, cfg, summary, true)
+		})
+		if err != nil {
+			return summary, seeds, err
+		}
+	}
+	return summary, seeds, nil
+}
+
+// runManifestMode runs the manifest at path without opening a window,
+// writing its outputs next to it, and prints its summary and seeds to
+// stderr, so a run without seeds can be repeated exactly.
+func runManifestMode(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	m, err := readManifest(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	summary, seeds, err := runManifest(m, filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	name := m.Name
+	if name == "" {
+		name = filepath.Base(path)
+	}
+	fmt.Fprintf(os.Stderr, "%s, seeds %v\n%s\n", name, seeds, summary)
+	return nil
+}
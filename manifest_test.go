@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testManifest = `
+name: drift test
+seeds: [7, 8]
+generations: 40
+grid_size: 30
+cell_size: 2
+parameters:
+  growth_rate: 0.3
+  drift_direction: SE
+  drift_strength: 0.5
+outputs:
+  summary_csv: summary.csv
+  stats_csv: stats-{seed}.csv
+  final_png: final-{seed}.png
+  gif: run-{seed}.gif
+`
+
+func TestReadManifest(t *testing.T) {
+	m, err := readManifest(strings.NewReader(testManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Parameters.GrowthRate != 0.3 || m.Parameters.MutationChance != defaultLaunchOptions.mutationChance || m.GridSize != 30 {
+		t.Errorf("manifest %+v", m)
+	}
+
+	json := `{"runs": 3, "seed": 100, "parameters": {"rule": "Conway's Life"}}`
+	if m, err := readManifest(strings.NewReader(json)); err != nil || len(m.seeds()) != 3 || m.seeds()[2] != 102 {
+		t.Errorf("JSON manifest: %+v, %v", m, err)
+	}
+
+	for _, bad := range []string{
+		"generation: 10",                           // unknown field
+		"parameters: {growth_rate: 0.9}",           // out of range
+		"parameters: {rule: Nope}",                 // unknown rule
+		"runs: 2\noutputs: {final_png: final.png}", // runs overwriting each other's file
+	} {
+		if _, err := readManifest(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q was accepted", bad)
+		}
+	}
+}
+
+func TestRunManifest(t *testing.T) {
+	m, err := readManifest(strings.NewReader(testManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	summary, seeds, err := runManifest(m, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.runs != 2 || len(seeds) != 2 {
+		t.Errorf("summary of %d runs, seeds %v", summary.runs, seeds)
+	}
+	for _, name := range []string{"summary.csv", "stats-7.csv", "stats-8.csv", "final-7.png", "final-8.png", "run-7.gif", "run-8.gif"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || info.Size() == 0 {
+			t.Errorf("%s not written: %v", name, err)
+		}
+	}
+
+	// The same manifest runs the same way
+	first, _ := os.ReadFile(filepath.Join(dir, "stats-7.csv"))
+	again := t.TempDir()
+	if _, _, err := runManifest(m, again); err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := os.ReadFile(filepath.Join(again, "stats-7.csv")); !bytes.Equal(first, second) {
+		t.Error("the same manifest gave different statistics")
+	}
+}
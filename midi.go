@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+)
+
+// MIDI export of a recorded run: one sixteenth note per generation at
+// 120 BPM, with separate tracks for population, births and disasters.
+const (
+	midiTicksPerQuarter = 96
+	midiTicksPerGen     = midiTicksPerQuarter / 4
+	midiTempo           = 500000 // microseconds per quarter note (120 BPM)
+)
+
+// C major pentatonic over three octaves, starting at C3
+var midiScale = []byte{48, 50, 52, 55, 57, 60, 62, 64, 67, 69, 72, 74, 76, 79, 81}
+
+type midiEvent struct {
+	tick int
+	data []byte
+}
+
+type midiTrack struct {
+	events []midiEvent
+}
+
+func (t *midiTrack) note(tick, length int, channel, key, velocity byte) {
+	t.events = append(t.events,
+		midiEvent{tick, []byte{0x90 | channel, key, velocity}},
+		midiEvent{tick + length, []byte{0x80 | channel, key, 0}})
+}
+
+func (t *midiTrack) meta(tick int, kind byte, payload []byte) {
+	data := append([]byte{0xFF, kind}, varLen(len(payload))...)
+	t.events = append(t.events, midiEvent{tick, append(data, payload...)})
+}
+
+func (t *midiTrack) program(channel, program byte) {
+	t.events = append(t.events, midiEvent{0, []byte{0xC0 | channel, program}})
+}
+
+func varLen(v int) []byte {
+	buf := []byte{byte(v & 0x7F)}
+	for v >>= 7; v > 0; v >>= 7 {
+		buf = append([]byte{byte(v&0x7F) | 0x80}, buf...)
+	}
+	return buf
+}
+
+// bytes encodes the track chunk with delta times; note-offs sort before
+// note-ons at the same tick so repeated notes retrigger cleanly.
+func (t *midiTrack) bytes() []byte {
+	sort.SliceStable(t.events, func(i, j int) bool {
+		if t.events[i].tick != t.events[j].tick {
+			return t.events[i].tick < t.events[j].tick
+		}
+		return t.events[i].data[0]&0xF0 == 0x80 && t.events[j].data[0]&0xF0 != 0x80
+	})
+
+	var body bytes.Buffer
+	last := 0
+	for _, e := range t.events {
+		body.Write(varLen(e.tick - last))
+		body.Write(e.data)
+		last = e.tick
+	}
+	body.Write([]byte{0x00, 0xFF, 0x2F, 0x00}) // end of track
+
+	var chunk bytes.Buffer
+	chunk.WriteString("MTrk")
+	binary.Write(&chunk, binary.BigEndian, uint32(body.Len()))
+	chunk.Write(body.Bytes())
+	return chunk.Bytes()
+}
+
+func scaleNote(v float64) byte {
+	i := int(v * float64(len(midiScale)-1))
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(midiScale) {
+		i = len(midiScale) - 1
+	}
+	return midiScale[i]
+}
+
+// writeRunMIDI converts a recorded run into a format 1 Standard MIDI File.
+func writeRunMIDI(w io.Writer, history *StatsHistory, events []Event, gridCells int) error {
+	tempo := &midiTrack{}
+	tempo.meta(0, 0x51, []byte{midiTempo >> 16, midiTempo >> 8 & 0xFF, midiTempo & 0xFF})
+	tempo.meta(0, 0x03, []byte("Living Numbers"))
+
+	// Population: a quarter note every 4 generations, pitch following density
+	// and loudness following average age.
+	population := &midiTrack{}
+	population.meta(0, 0x03, []byte("Population"))
+	population.program(0, 88) // Pad 1 (new age)
+	for i := 0; i < len(history.generations); i += 4 {
+		density := float64(history.population[i]) / float64(gridCells)
+		velocity := byte(50 + clampFloat(history.avgAge[i]/50, 0, 1)*77)
+		population.note(i*midiTicksPerGen, 4*midiTicksPerGen, 0, scaleNote(density), velocity)
+	}
+
+	// Births: a plucked note whenever the population grows noticeably.
+	births := &midiTrack{}
+	births.meta(0, 0x03, []byte("Births"))
+	births.program(1, 11) // Vibraphone
+	for i, b := range history.births {
+		if b == 0 {
+			continue
+		}
+		share := float64(b) / float64(gridCells) * 20
+		if share > 1 {
+			share = 1
+		}
+		if share < 0.05 {
+			continue
+		}
+		births.note(i*midiTicksPerGen, midiTicksPerGen, 1, scaleNote(share)+12, byte(40+share*87))
+	}
+
+	// Disasters and other notable events on the General MIDI drum channel.
+	disasters := &midiTrack{}
+	disasters.meta(0, 0x03, []byte("Disasters"))
+	first := 0
+	if len(history.generations) > 0 {
+		first = history.generations[0]
+	}
+	for _, e := range events {
+		if e.generation < first {
+			continue
+		}
+		tick := (e.generation - first) * midiTicksPerGen
+		switch e.eventType {
+		case "SUPERNOVA":
+			disasters.note(tick, midiTicksPerQuarter, 9, 49, 127) // crash cymbal
+			disasters.note(tick, midiTicksPerQuarter, 9, 35, 127) // bass drum
+		case "MUTATION":
+			disasters.note(tick, midiTicksPerGen, 9, 54, 90) // tambourine
+		case "END":
+			disasters.note(tick, 2*midiTicksPerQuarter, 9, 57, 120) // crash 2
+		}
+	}
+
+	tracks := []*midiTrack{tempo, population, births, disasters}
+	var header bytes.Buffer
+	header.WriteString("MThd")
+	binary.Write(&header, binary.BigEndian, uint32(6))
+	binary.Write(&header, binary.BigEndian, uint16(1))
+	binary.Write(&header, binary.BigEndian, uint16(len(tracks)))
+	binary.Write(&header, binary.BigEndian, uint16(midiTicksPerQuarter))
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return err
+	}
+	for _, t := range tracks {
+		if _, err := w.Write(t.bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
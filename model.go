@@ -0,0 +1,62 @@
+package main
+
+import "context"
+
+// runModel is the engine-owned part of SimulationState: the parameters a
+// run steps with and its progress.
+type runModel struct {
+	growthRate     float64
+	mutationChance float64
+	temperature    float64 // noise of the survival thresholds, see exceeds
+	ageCurves      ageCurves
+	birthCurve     birthCurve
+	metabolism     bool // energy layer, see Simulation.metabolize
+	nutrients      bool // nutrient field, see Simulation.feed
+	seasonPeriod   int  // generations per season cycle, 0 for none
+	driftDirection int  // index in driftDirections
+	driftStrength  float64
+	ruleDrift      bool // see Simulation.setRuleDrift
+	zoneLayout     int  // index in zoneLayouts
+	zonesChanged   bool // zoneLayout differs from the running layout
+	zoneBrush      int  // zone painted by the zone brush
+	climates       [4]climate
+	movementRate   float64 // see Simulation.migrate
+	immigration    float64 // see Simulation.immigrate
+	entryEdge      int     // index in immigrationEdges
+	infectionRate  float64 // see Simulation.spread
+	infectionSpan  int
+	radiation      bool // supernovas leave radiation, see Simulation.irradiate
+	novaRadius     int  // of supernovas, in cells
+	stats          Stats
+	isPaused       bool
+	isStarted      bool
+	cellSize       int
+	gridSize       int
+	gridCells      int // chosen grid side, see largeGridSizes; 0 fits the display
+	speed          int // ms between each generation
+	speedMode      int // speedFixed, speedMax or speedAuto; speed only applies to the first
+	symmetry       Symmetry
+	ruleFamily     string               // engine of the next run, see ruleFamilies
+	ruleParams     map[string][]float64 // parameter values per rule family
+	automation     Automation
+	triggers       []*Trigger
+	catastrophes   Catastrophes
+	scenario       *ActiveScenario    // nil in free play
+	stopRun        context.CancelFunc // ends the running simulation goroutine
+}
+
+// displaySettings is the part of SimulationState about how runs are shown
+// and captured, which the engine never reads.
+type displaySettings struct {
+	paletteMode   int
+	paletteSpeed  float64 // scales the palette animation, 1 by default
+	paletteFrozen bool    // the palette is held as it is
+	gridLines     bool    // 1px lines between cells, at cell sizes >= minGridLineCell
+	deadGhosts    bool    // dead cells keep a fading trace of their age, see drawGhosts
+	effects       EffectChain
+	view          viewport         // window of the grid drawn on the display
+	recording     *animation       // generations being recorded, nil when not
+	timelapse     *timelapse       // frames being saved to a folder, nil when not
+	profile       *profileRecorder // developer CPU/heap profile in progress
+	shareEndpoint string           // share server chosen by the user
+}
@@ -0,0 +1,56 @@
+package main
+
+// migrate runs the movement phase: in a random order, each living cell
+// moves with probability movementRate times the share of its neighbours
+// that are alive, to the empty neighbouring cell with the fewest living
+// neighbours where it still survives, so crowded colonies spread out and
+// drift as swarms. Energy and infection move with the cell, and a cell
+// moves at most once.
+func (s *Simulation) migrate() {
+	n := s.gridSize
+	moved := make([]bool, n*n)
+	for _, i := range s.rng.Perm(n * n) {
+		x, y := i%n, i/n
+		if s.grid.cells[i] == 0 || moved[i] {
+			continue
+		}
+		crowd := livingNeighbors(s.grid, x, y)
+		if crowd == 0 || s.rng.Float64() >= s.movementRate*float64(crowd)/8 {
+			continue
+		}
+		bestX, bestY, best, ties := -1, -1, 9, 0
+		for ny := max(y-1, 0); ny <= min(y+1, n-1); ny++ {
+			for nx := max(x-1, 0); nx <= min(x+1, n-1); nx++ {
+				if s.grid.at(nx, ny) > 0 {
+					continue
+				}
+				// Own cell excluded, as it empties with the move
+				c := livingNeighbors(s.grid, nx, ny) - 1
+				switch {
+				case neighbors(s.grid, nx, ny)-s.grid.at(x, y) < 3:
+					// The cell would die of isolation there
+				case c < best:
+					bestX, bestY, best, ties = nx, ny, c, 1
+				case c == best:
+					// Reservoir sampling among equally good cells
+					ties++
+					if s.rng.Intn(ties) == 0 {
+						bestX, bestY = nx, ny
+					}
+				}
+			}
+		}
+		if bestX < 0 {
+			continue
+		}
+		j := bestY*n + bestX
+		s.grid.cells[j], s.grid.cells[i] = s.grid.cells[i], 0
+		moved[j] = true
+		if s.energy != nil {
+			s.energy[j], s.energy[i] = s.energy[i], 0
+		}
+		if s.infection != nil {
+			s.infection[j], s.infection[i] = s.infection[i], 0
+		}
+	}
+}
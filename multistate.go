@@ -0,0 +1,178 @@
+package main
+
+import "image"
+
+// Grid values standing for the states of the multi-state rule families, one
+// per palette band: the shades shown in the legend.
+const (
+	youngState  = 3
+	matureState = 12
+	oldState    = 35
+)
+
+// Palette bands of the grid values.
+const (
+	bandDead = iota
+	bandYoung
+	bandMature
+	bandOld
+)
+
+// cellBand returns the palette band of a grid value, so painted or restored
+// cells of any age read as one of the states.
+func cellBand(val int) int {
+	switch {
+	case val <= 0:
+		return bandDead
+	case val < 5:
+		return bandYoung
+	case val < 20:
+		return bandMature
+	default:
+		return bandOld
+	}
+}
+
+// bandNeighbors counts the neighbours of (x, y) in the given band.
+func bandNeighbors(g *Grid, x, y, band int) int {
+	n := 0
+	for ny := max(y-1, 0); ny <= min(y+1, g.size-1); ny++ {
+		for nx := max(x-1, 0); nx <= min(x+1, g.size-1); nx++ {
+			if (nx != x || ny != y) && cellBand(g.at(nx, ny)) == band {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// setOrbit sets a cell and its orbit under the simulation's symmetry.
+func setOrbit(sim *Simulation, pts []image.Point, x, y, val int) []image.Point {
+	pts = sim.symmetry.orbit(pts[:0], x, y, sim.gridSize)
+	for _, p := range pts {
+		sim.grid.set(p.X, p.Y, val)
+	}
+	return pts
+}
+
+// briansBrain is Brian's Brain: an off cell fires when exactly two of its
+// neighbours fire, a firing cell is dying at the next generation and a dying
+// cell turns off. Firing cells are young and dying cells mature.
+type briansBrain struct{}
+
+func (briansBrain) Name() string { return "Brian's Brain" }
+
+// seed fills a few square patches with firing cells, a third of them on.
+func (briansBrain) seed(sim *Simulation) {
+	n := sim.gridSize
+	side := max(n/4, 1)
+	var pts []image.Point
+	for range 1 + sim.rng.Intn(3) {
+		x0, y0 := sim.rng.Intn(n-side+1), sim.rng.Intn(n-side+1)
+		for dy := 0; dy < side; dy++ {
+			for dx := 0; dx < side; dx++ {
+				if sim.rng.Intn(3) == 0 {
+					pts = setOrbit(sim, pts, x0+dx, y0+dy, youngState)
+				}
+			}
+		}
+	}
+}
+
+func (briansBrain) load(*Simulation) {}
+
+func (briansBrain) step(sim *Simulation) (births int) {
+	for y := range sim.gridSize {
+		next := sim.next.row(y)
+		for x, c := range sim.grid.row(y) {
+			val := 0
+			switch cellBand(int(c)) {
+			case bandDead:
+				if bandNeighbors(sim.grid, x, y, bandYoung) == 2 {
+					val = youngState
+					births++
+				}
+			case bandYoung:
+				val = matureState
+			}
+			next[x] = uint8(val)
+		}
+	}
+	sim.grid, sim.next = sim.next, sim.grid
+	return births
+}
+
+func (briansBrain) set(x, y, val int) {}
+
+// wireworld is Wireworld: electrons run along conductors (old cells), an
+// electron head (young) becoming a tail (mature) and then conductor again,
+// and a conductor next to one or two heads becoming a head. Empty cells
+// never change.
+type wireworld struct{}
+
+func (wireworld) Name() string { return "Wireworld" }
+
+// seed lays out rectangular loops of wire, each carrying one electron
+// running around it, joined by a few straight wires where signals meet.
+func (wireworld) seed(sim *Simulation) {
+	n := sim.gridSize
+	if n < 8 {
+		return
+	}
+	var pts []image.Point
+	for range 3 + sim.rng.Intn(4) {
+		w, h := 4+sim.rng.Intn(n/3), 4+sim.rng.Intn(n/3)
+		x0, y0 := sim.rng.Intn(n-w), sim.rng.Intn(n-h)
+		for x := x0; x <= x0+w; x++ {
+			pts = setOrbit(sim, pts, x, y0, oldState)
+			pts = setOrbit(sim, pts, x, y0+h, oldState)
+		}
+		for y := y0; y <= y0+h; y++ {
+			pts = setOrbit(sim, pts, x0, y, oldState)
+			pts = setOrbit(sim, pts, x0+w, y, oldState)
+		}
+		pts = setOrbit(sim, pts, x0+1, y0, youngState)
+		pts = setOrbit(sim, pts, x0, y0, matureState)
+	}
+	for range 2 + sim.rng.Intn(3) {
+		at := sim.rng.Intn(n)
+		vertical := sim.rng.Intn(2) == 0
+		for i := 0; i < n; i++ {
+			x, y := i, at
+			if vertical {
+				x, y = at, i
+			}
+			if sim.grid.at(x, y) == 0 {
+				pts = setOrbit(sim, pts, x, y, oldState)
+			}
+		}
+	}
+}
+
+func (wireworld) load(*Simulation) {}
+
+func (wireworld) step(sim *Simulation) (births int) {
+	for y := range sim.gridSize {
+		next := sim.next.row(y)
+		for x, c := range sim.grid.row(y) {
+			val := 0
+			switch cellBand(int(c)) {
+			case bandYoung:
+				val = matureState
+			case bandMature:
+				val = oldState
+			case bandOld:
+				val = oldState
+				if heads := bandNeighbors(sim.grid, x, y, bandYoung); heads == 1 || heads == 2 {
+					val = youngState
+					births++
+				}
+			}
+			next[x] = uint8(val)
+		}
+	}
+	sim.grid, sim.next = sim.next, sim.grid
+	return births
+}
+
+func (wireworld) set(x, y, val int) {}
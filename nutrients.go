@@ -0,0 +1,94 @@
+package main
+
+import (
+	"image"
+	"image/color"
+)
+
+// Nutrient field settings, levels running from 0 (exhausted) to 1.
+const (
+	nutrientRegen     = 0.005 // regrowth of every cell per generation
+	nutrientUse       = 0.03  // eaten by each living cell per generation
+	nutrientDiffusion = 0.2   // share of the gap to the neighbours' mean closed per generation
+)
+
+// nutrientTint is the background color of a cell full of nutrients.
+var nutrientTint = color.RGBA{40, 90, 30, 255}
+
+// setNutrients turns the nutrient field on, full everywhere, or off.
+func (s *Simulation) setNutrients(on bool) {
+	if !on {
+		s.nutrients, s.nextNutrients = nil, nil
+		return
+	}
+	if s.nutrients != nil {
+		return
+	}
+	s.nutrients = make([]float64, s.gridSize*s.gridSize)
+	s.nextNutrients = make([]float64, s.gridSize*s.gridSize)
+	s.fillNutrients()
+}
+
+func (s *Simulation) fillNutrients() {
+	for i := range s.nutrients {
+		s.nutrients[i] = 1
+	}
+}
+
+// feed runs the nutrient field for one generation: living cells eat from
+// their cell, every cell regrows a little, and the field diffuses towards
+// the mean of the four neighbours, edges reflecting.
+func (s *Simulation) feed() {
+	n := s.gridSize
+	for i, c := range s.grid.cells {
+		v := s.nutrients[i] + nutrientRegen
+		if c > 0 {
+			v -= nutrientUse
+		}
+		s.nutrients[i] = min(max(v, 0), 1)
+	}
+	at := func(x, y int) float64 {
+		return s.nutrients[min(max(y, 0), n-1)*n+min(max(x, 0), n-1)]
+	}
+	for y := range n {
+		for x := range n {
+			mean := (at(x-1, y) + at(x+1, y) + at(x, y-1) + at(x, y+1)) / 4
+			v := at(x, y)
+			s.nextNutrients[y*n+x] = v + nutrientDiffusion*(mean-v)
+		}
+	}
+	s.nutrients, s.nextNutrients = s.nextNutrients, s.nutrients
+}
+
+// drawNutrients paints the empty cells with a gradient from the dead color,
+// for exhausted cells, to nutrientTint, for full ones.
+func drawNutrients(img *image.RGBA, grid *Grid, nutrients []float64, dead color.Color, cellSize int) {
+	var lut [256][4]uint8
+	for i := range lut {
+		lut[i] = gradientAt([]color.Color{dead, nutrientTint}, float64(i)/255)
+	}
+	bounds := img.Bounds()
+	n := grid.size
+	for y := range n {
+		for x, c := range grid.row(y) {
+			if c > 0 {
+				continue
+			}
+			col := lut[int(nutrients[y*n+x]*255)]
+			for dy := 0; dy < cellSize; dy++ {
+				py := bounds.Min.Y + y*cellSize + dy
+				if py >= bounds.Max.Y {
+					break
+				}
+				for dx := 0; dx < cellSize; dx++ {
+					px := bounds.Min.X + x*cellSize + dx
+					if px >= bounds.Max.X {
+						break
+					}
+					off := img.PixOffset(px, py)
+					copy(img.Pix[off:off+4], col[:])
+				}
+			}
+		}
+	}
+}
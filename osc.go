@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log/slog"
+	"math"
+	"net"
+	"sync"
+)
+
+// oscOutput is the sender given with -osc, nil without one.
+var oscOutput *oscSender
+
+// oscSender sends the run to an OSC receiver over UDP, for TouchDesigner,
+// SuperCollider, Max/MSP and the like: a bundle of /living/... statistics
+// every generation, and an /living/event message for every event.
+type oscSender struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	failed bool // a send failed; logged once, receivers may come and go
+}
+
+// dialOSC sends to addr, given as host:port.
+func dialOSC(addr string) (*oscSender, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &oscSender{conn: conn}, nil
+}
+
+// oscMessage encodes an OSC message with int32, float32 and string
+// arguments.
+func oscMessage(address string, args ...any) []byte {
+	var buf bytes.Buffer
+	oscString(&buf, address)
+	tags := []byte{','}
+	for _, arg := range args {
+		switch arg.(type) {
+		case int:
+			tags = append(tags, 'i')
+		case float64:
+			tags = append(tags, 'f')
+		case string:
+			tags = append(tags, 's')
+		}
+	}
+	oscString(&buf, string(tags))
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case int:
+			binary.Write(&buf, binary.BigEndian, int32(v))
+		case float64:
+			binary.Write(&buf, binary.BigEndian, math.Float32bits(float32(v)))
+		case string:
+			oscString(&buf, v)
+		}
+	}
+	return buf.Bytes()
+}
+
+// oscString writes s null-terminated and padded to a multiple of 4 bytes.
+func oscString(buf *bytes.Buffer, s string) {
+	buf.WriteString(s)
+	buf.Write(make([]byte, 4-len(s)%4))
+}
+
+// oscBundle wraps messages into a bundle to be handled at once.
+func oscBundle(messages ...[]byte) []byte {
+	var buf bytes.Buffer
+	oscString(&buf, "#bundle")
+	binary.Write(&buf, binary.BigEndian, uint64(1)) // time tag: immediately
+	for _, m := range messages {
+		binary.Write(&buf, binary.BigEndian, int32(len(m)))
+		buf.Write(m)
+	}
+	return buf.Bytes()
+}
+
+func (s *oscSender) send(packet []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.conn.Write(packet); err != nil && !s.failed {
+		s.failed = true
+		slog.Warn("OSC send failed", "addr", s.conn.RemoteAddr(), "err", err)
+	}
+}
+
+// note sends an event as /living/event generation type message. A nil
+// sender ignores it.
+func (s *oscSender) note(e Event) {
+	if s == nil {
+		return
+	}
+	s.send(oscMessage("/living/event", e.generation, e.eventType, e.message))
+}
+
+// write sends the statistics of a generation. A nil sender ignores it.
+func (s *oscSender) write(stats Stats) {
+	if s == nil {
+		return
+	}
+	s.send(oscBundle(
+		oscMessage("/living/generation", stats.generation),
+		oscMessage("/living/population", stats.population),
+		oscMessage("/living/density", stats.density),
+		oscMessage("/living/avg_age", stats.avgAge),
+		oscMessage("/living/entropy", stats.entropy),
+		oscMessage("/living/births", stats.births),
+		oscMessage("/living/rebirths", stats.rebirths),
+		oscMessage("/living/colonies", stats.colonies),
+		oscMessage("/living/largest_colony", stats.largestColony),
+	))
+}
+
+func (s *oscSender) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
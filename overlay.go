@@ -0,0 +1,107 @@
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// overlaySize is a resolution offered for the overlay window.
+type overlaySize struct {
+	name          string
+	width, height float32
+}
+
+var overlaySizes = []overlaySize{
+	{"1280×720", 1280, 720},
+	{"1920×1080", 1920, 1080},
+	{"1080×1080", 1080, 1080},
+	{"1080×1920 (vertical)", 1080, 1920},
+}
+
+// chromaKeys are the backgrounds of the overlay, flat colors a streaming
+// tool keys out. Fyne windows cannot be see-through themselves.
+var chromaKeys = []struct {
+	name  string
+	color color.RGBA
+}{
+	{"Green screen", color.RGBA{0, 177, 64, 255}},
+	{"Blue screen", color.RGBA{0, 71, 187, 255}},
+	{"Magenta", color.RGBA{255, 0, 255, 255}},
+}
+
+// overlayWindow mirrors the grid in a borderless window on a chroma-key
+// background, for streamers to composite over their video. Escape closes it.
+type overlayWindow struct {
+	win   fyne.Window
+	image *canvas.Image
+}
+
+func openOverlay(a fyne.App, size overlaySize, background color.RGBA, grid image.Image, onClosed func()) *overlayWindow {
+	var win fyne.Window
+	if drv, ok := a.Driver().(desktop.Driver); ok {
+		win = drv.CreateSplashWindow()
+	} else {
+		win = a.NewWindow(lang.L("Living Numbers Game - Overlay"))
+	}
+	o := &overlayWindow{win: win, image: canvas.NewImageFromImage(grid)}
+	o.image.FillMode = canvas.ImageFillContain
+	o.image.ScaleMode = canvas.ImageScalePixels
+	win.SetContent(container.NewStack(canvas.NewRectangle(background), o.image))
+	win.SetPadded(false)
+	win.Resize(fyne.NewSize(size.width, size.height))
+	win.Canvas().SetOnTypedKey(func(ev *fyne.KeyEvent) {
+		if ev.Name == fyne.KeyEscape {
+			win.Close()
+		}
+	})
+	win.SetOnClosed(onClosed)
+	win.Show()
+	return o
+}
+
+// show displays a new frame. A nil overlay ignores it. It must run on the
+// main thread.
+func (o *overlayWindow) show(frame image.Image) {
+	if o == nil {
+		return
+	}
+	o.image.Image = frame
+	o.image.Refresh()
+}
+
+// showOverlayDialog asks for the resolution and background of the overlay.
+func showOverlayDialog(w fyne.Window, onOpen func(size overlaySize, background color.RGBA)) {
+	sizeNames := make([]string, len(overlaySizes))
+	for i, s := range overlaySizes {
+		sizeNames[i] = s.name
+	}
+	keyNames := make([]string, len(chromaKeys))
+	for i, k := range chromaKeys {
+		keyNames[i] = k.name
+	}
+	sizeSelect := widget.NewSelect(sizeNames, nil)
+	sizeSelect.SetSelectedIndex(0)
+	keySelect := widget.NewSelect(localized(keyNames), nil)
+	keySelect.SetSelectedIndex(0)
+	form := widget.NewForm(
+		widget.NewFormItem(lang.L("Resolution"), sizeSelect),
+		widget.NewFormItem(lang.L("Background"), keySelect),
+	)
+	content := container.NewVBox(
+		widget.NewLabel(lang.L("A borderless window showing only the grid, empty cells in\nthe key color. Capture it in your streaming tool with a chroma key\nfilter; press Escape in it or the button again to close it.")),
+		form,
+	)
+	dialog.ShowCustomConfirm(lang.L("📺 Overlay"), lang.L("Open"), lang.L("Cancel"), content, func(ok bool) {
+		if ok {
+			onOpen(overlaySizes[sizeSelect.SelectedIndex()], chromaKeys[keySelect.SelectedIndex()].color)
+		}
+	}, w)
+}
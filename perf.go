@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// perfSmoothing is the weight of the newest sample in the moving averages.
+const perfSmoothing = 0.1
+
+// Speed modes: the slider's interval, generations back to back, or an
+// interval adapted to what the generations cost.
+const (
+	speedFixed = iota
+	speedMax
+	speedAuto
+)
+
+var speedModes = []string{"Fixed", "⚡ Max", "🎯 Auto"}
+
+// frameBudget is the time a generation may keep the loop busy under the
+// auto speed, a frame at 60 Hz.
+const frameBudget = 16 * time.Millisecond
+
+// autoInterval is the interval between generations under the auto speed,
+// for generations keeping the loop busy for cost: the main thread gets at
+// least as much time as the simulation, and a whole frame budget once a
+// generation exceeds it.
+func autoInterval(cost time.Duration) time.Duration {
+	if cost > frameBudget {
+		return cost + frameBudget
+	}
+	return 2 * cost
+}
+
+// perfMeter measures where a run's time goes. Durations are exponential
+// moving averages; the frame time spans a drawn generation, from stepping
+// the grid to the display swap, and the busy time any generation, drawn or
+// not. Only the simulation goroutine uses it.
+type perfMeter struct {
+	step, render, effects, frame, busy time.Duration
+	gensPerSec                         float64
+	lastGeneration                     time.Time
+}
+
+func smoothDuration(avg, sample time.Duration) time.Duration {
+	if avg == 0 {
+		return sample
+	}
+	return avg + time.Duration(perfSmoothing*float64(sample-avg))
+}
+
+// generation records the start of a generation at now.
+func (m *perfMeter) generation(now time.Time) {
+	if !m.lastGeneration.IsZero() {
+		rate := 1 / now.Sub(m.lastGeneration).Seconds()
+		if m.gensPerSec == 0 {
+			m.gensPerSec = rate
+		} else {
+			m.gensPerSec += perfSmoothing * (rate - m.gensPerSec)
+		}
+	}
+	m.lastGeneration = now
+}
+
+func formatMillis(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
+
+func (m *perfMeter) String() string {
+	return fmt.Sprintf("⏱ %.1f gen/s - frame %s\nevolve %s, render %s, effects %s",
+		m.gensPerSec, formatMillis(m.frame),
+		formatMillis(m.step), formatMillis(m.render), formatMillis(m.effects))
+}
@@ -0,0 +1,8 @@
+//go:build highlife
+
+package main
+
+// The example plugin, included with -tags highlife. Other plugins are added
+// the same way: a file importing the package for its side effects behind a
+// build tag of its own.
+import _ "github.com/maximedotair/living_numbers_game_go/plugins/highlife"
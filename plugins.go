@@ -0,0 +1,93 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/maximedotair/living_numbers_game_go/livingnumbers"
+)
+
+// Rule families and view modes from other packages register with the
+// livingnumbers package in their init functions; importing them for their
+// side effects, as plugin_*.go does behind build tags, adds them to the
+// selectors after the built-in ones.
+func init() {
+	for _, newRuleset := range livingnumbers.Rulesets() {
+		name := newRuleset().Name()
+		if slices.Contains(ruleFamilyNames(), name) {
+			continue
+		}
+		ruleFamilies = append(ruleFamilies, ruleFamily{name, func() Rule { return &pluginRule{ruleset: newRuleset()} }, nil})
+	}
+	for _, c := range livingnumbers.Colorizers() {
+		if slices.Contains(colorizerNames(), c.Name()) {
+			continue
+		}
+		colorizers = append(colorizers, Colorizer{name: c.Name(), colors: pluginColors(c)})
+	}
+}
+
+// pluginRule runs a registered ruleset on copies of the grid in the
+// library's grid type.
+type pluginRule struct {
+	ruleset   livingnumbers.Ruleset
+	cur, next *livingnumbers.Grid
+}
+
+func (r *pluginRule) Name() string { return r.ruleset.Name() }
+
+func (r *pluginRule) seed(sim *Simulation) {
+	r.cur, r.next = livingnumbers.NewGrid(sim.gridSize), livingnumbers.NewGrid(sim.gridSize)
+	r.ruleset.Seed(r.cur, sim.rng)
+	r.store(sim.grid)
+}
+
+func (r *pluginRule) load(sim *Simulation) {
+	r.cur, r.next = libraryGrid(sim.grid), livingnumbers.NewGrid(sim.gridSize)
+}
+
+func (r *pluginRule) step(sim *Simulation) (births int) {
+	births = r.ruleset.Step(r.cur, r.next, sim.rng)
+	r.cur, r.next = r.next, r.cur
+	r.store(sim.grid)
+	return births
+}
+
+func (r *pluginRule) set(x, y, val int) {
+	if r.cur != nil {
+		r.cur.Set(x, y, val)
+	}
+}
+
+// store copies the ruleset's grid into g.
+func (r *pluginRule) store(g *Grid) {
+	for y := range g.size {
+		row := g.row(y)
+		for x := range row {
+			row[x] = uint8(r.cur.At(x, y))
+		}
+	}
+}
+
+// libraryGrid copies g into the library's grid type.
+func libraryGrid(g *Grid) *livingnumbers.Grid {
+	lg := livingnumbers.NewGrid(g.size)
+	for y := range g.size {
+		for x, c := range g.row(y) {
+			lg.Set(x, y, int(c))
+		}
+	}
+	return lg
+}
+
+// pluginColors colors the cells through a registered colorizer, handed a
+// copy of the grid and the palette's colors each frame.
+func pluginColors(c livingnumbers.Colorizer) func(*Simulation, ColorPalette) cellColors {
+	return func(sim *Simulation, palette ColorPalette) cellColors {
+		p := livingnumbers.Palette(palette.colors)
+		colors := c.Colors(libraryGrid(sim.grid), &p)
+		return func(x, y int) [4]uint8 {
+			c := colors(x, y)
+			return [4]uint8{c.R, c.G, c.B, c.A}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Package highlife is an example plugin: it adds HighLife, Conway's Life
+// with births on six neighbours as well (B36/S23), and a grayscale view
+// mode to the app when imported for its side effects. Build the app with
+// -tags highlife to include it, see plugin_highlife.go.
+package highlife
+
+import (
+	"image/color"
+
+	"github.com/maximedotair/living_numbers_game_go/livingnumbers"
+)
+
+func init() {
+	livingnumbers.RegisterRuleset(func() livingnumbers.Ruleset { return highLife{} })
+	livingnumbers.RegisterColorizer(grayscale{})
+}
+
+// highLife is B36/S23 on a bounded grid; living cells age through the
+// palette.
+type highLife struct{}
+
+func (highLife) Name() string { return "HighLife" }
+
+// Seed fills the middle square, half the grid wide, with a random soup, a
+// third of it alive.
+func (highLife) Seed(g *livingnumbers.Grid, rng livingnumbers.Rand) {
+	n := g.Size()
+	side := max(n/2, 1)
+	x0 := (n - side) / 2
+	for y := x0; y < x0+side; y++ {
+		for x := x0; x < x0+side; x++ {
+			if rng.Intn(3) == 0 {
+				g.Set(x, y, 1)
+			}
+		}
+	}
+}
+
+func (highLife) Step(cur, next *livingnumbers.Grid, _ livingnumbers.Rand) (births int) {
+	n := cur.Size()
+	for y := range n {
+		for x := range n {
+			living := 0
+			for ny := max(y-1, 0); ny <= min(y+1, n-1); ny++ {
+				for nx := max(x-1, 0); nx <= min(x+1, n-1); nx++ {
+					if (nx != x || ny != y) && cur.At(nx, ny) > 0 {
+						living++
+					}
+				}
+			}
+			age := cur.At(x, y)
+			switch {
+			case age > 0 && (living == 2 || living == 3):
+				next.Set(x, y, age+1)
+			case age == 0 && (living == 3 || living == 6):
+				next.Set(x, y, 1)
+				births++
+			default:
+				next.Set(x, y, 0)
+			}
+		}
+	}
+	return births
+}
+
+// grayscale shades living cells from dark to white as they age.
+type grayscale struct{}
+
+func (grayscale) Name() string { return "Grayscale" }
+
+func (grayscale) Colors(g *livingnumbers.Grid, p *livingnumbers.Palette) func(x, y int) color.RGBA {
+	return func(x, y int) color.RGBA {
+		age := g.At(x, y)
+		if age == 0 {
+			return p.Color(0)
+		}
+		v := uint8(80 + 175*age/livingnumbers.MaxAge)
+		return color.RGBA{v, v, v, 255}
+	}
+}
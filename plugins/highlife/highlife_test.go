@@ -0,0 +1,111 @@
+package highlife
+
+import (
+	"image/color"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/maximedotair/living_numbers_game_go/livingnumbers"
+)
+
+func TestRegistered(t *testing.T) {
+	var rulesets []string
+	for _, r := range livingnumbers.Rulesets() {
+		rulesets = append(rulesets, r().Name())
+	}
+	var colorizers []string
+	for _, c := range livingnumbers.Colorizers() {
+		colorizers = append(colorizers, c.Name())
+	}
+	if !slices.Contains(rulesets, "HighLife") || !slices.Contains(colorizers, "Grayscale") {
+		t.Errorf("registered rulesets %v, colorizers %v", rulesets, colorizers)
+	}
+}
+
+// TestStep pins B36/S23: births on 3 or 6 neighbours, survival on 2 or 3,
+// survivors ageing by one.
+func TestStep(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		neighbors [][2]int // living cells around (2, 2), age 1
+		center    int      // age of (2, 2)
+		want      int
+	}{
+		{"birth on 3", [][2]int{{1, 1}, {2, 1}, {3, 1}}, 0, 1},
+		{"birth on 6", [][2]int{{1, 1}, {2, 1}, {3, 1}, {1, 3}, {2, 3}, {3, 3}}, 0, 1},
+		{"no birth on 2", [][2]int{{1, 1}, {3, 3}}, 0, 0},
+		{"no birth on 4", [][2]int{{1, 1}, {2, 1}, {3, 1}, {1, 2}}, 0, 0},
+		{"survival on 2", [][2]int{{1, 1}, {3, 3}}, 5, 6},
+		{"survival on 3", [][2]int{{1, 1}, {2, 1}, {3, 1}}, 5, 6},
+		{"death on 1", [][2]int{{1, 1}}, 5, 0},
+		{"death on 6", [][2]int{{1, 1}, {2, 1}, {3, 1}, {1, 3}, {2, 3}, {3, 3}}, 5, 0},
+	} {
+		cur, next := livingnumbers.NewGrid(5), livingnumbers.NewGrid(5)
+		for _, n := range tc.neighbors {
+			cur.Set(n[0], n[1], 1)
+		}
+		cur.Set(2, 2, tc.center)
+		births := highLife{}.Step(cur, next, nil)
+		if got := next.At(2, 2); got != tc.want {
+			t.Errorf("%s: center %d, want %d", tc.name, got, tc.want)
+		}
+		if tc.center == 0 && tc.want == 1 && births == 0 {
+			t.Errorf("%s: no births counted", tc.name)
+		}
+	}
+}
+
+func TestBlinker(t *testing.T) {
+	// The blinker oscillates under B36/S23 as under Conway's Life
+	cur, next := livingnumbers.NewGrid(5), livingnumbers.NewGrid(5)
+	for x := 1; x <= 3; x++ {
+		cur.Set(x, 2, 1)
+	}
+	for range 2 {
+		highLife{}.Step(cur, next, nil)
+		cur, next = next, cur
+	}
+	for y := range 5 {
+		for x := range 5 {
+			if alive := cur.At(x, y) > 0; alive != (y == 2 && x >= 1 && x <= 3) {
+				t.Fatalf("blinker after 2 generations: (%d, %d) alive %v", x, y, alive)
+			}
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	g := livingnumbers.NewGrid(20)
+	highLife{}.Seed(g, rand.New(rand.NewSource(1)))
+	living := 0
+	for y := range 20 {
+		for x := range 20 {
+			if g.At(x, y) == 0 {
+				continue
+			}
+			living++
+			if x < 5 || x >= 15 || y < 5 || y >= 15 {
+				t.Fatalf("(%d, %d) seeded outside the middle square", x, y)
+			}
+		}
+	}
+	if living < 15 || living > 55 {
+		t.Errorf("%d cells seeded in the 10x10 middle square, want about a third", living)
+	}
+}
+
+func TestGrayscale(t *testing.T) {
+	g := livingnumbers.NewGrid(3)
+	g.Set(1, 0, 1)
+	g.Set(2, 0, livingnumbers.MaxAge)
+	p := livingnumbers.DefaultPalette()
+	colors := grayscale{}.Colors(g, &p)
+	if got := colors(0, 0); got != p.Color(0) {
+		t.Errorf("dead cell %v, want the palette's dead color %v", got, p.Color(0))
+	}
+	young, old := colors(1, 0), colors(2, 0)
+	if young.R != young.G || young.G != young.B || young.R >= old.R || old != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("young %v, old %v, want grays lightening to white", young, old)
+	}
+}
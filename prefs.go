@@ -0,0 +1,62 @@
+package main
+
+import "fyne.io/fyne/v2"
+
+// appID identifies the app to Fyne, which keys the preferences storage on it.
+const appID = "io.github.maximedotair.livingnumbers"
+
+// Preference keys of the settings remembered between sessions.
+const (
+	prefGrowthRate     = "growthRate"
+	prefMutationChance = "mutationChance"
+	prefCellSize       = "cellSize"
+	prefSpeed          = "speed"
+	prefPalette        = "palette"
+	prefImagePalette   = "imagePalette" // colors of the Image palette
+	prefBloom          = "bloom"
+	prefWindowWidth    = "windowWidth"
+	prefWindowHeight   = "windowHeight"
+)
+
+// restoreLastUsed overlays the settings remembered from the previous
+// session on the config file defaults.
+func restoreLastUsed(p fyne.Preferences, cfg userConfig) userConfig {
+	cfg.GrowthRate = p.FloatWithFallback(prefGrowthRate, cfg.GrowthRate)
+	// 0 is a valid mutation chance, so a missing key reads as -1
+	if mutation := p.FloatWithFallback(prefMutationChance, -1); mutation >= 0 {
+		cfg.MutationChance = &mutation
+	}
+	cfg.CellSize = p.IntWithFallback(prefCellSize, cfg.CellSize)
+	cfg.Speed = p.IntWithFallback(prefSpeed, cfg.Speed)
+	cfg.Palette = p.StringWithFallback(prefPalette, cfg.Palette)
+	cfg.Window.Width = float32(p.FloatWithFallback(prefWindowWidth, float64(cfg.Window.Width)))
+	cfg.Window.Height = float32(p.FloatWithFallback(prefWindowHeight, float64(cfg.Window.Height)))
+
+	key := effectKey(bloomEffect{}.Name())
+	bloom, ok := cfg.Effects[key]
+	if !ok {
+		bloom = effectConfig{Enabled: true, Intensity: defaultBloom.intensity, Radius: defaultBloom.radius}
+	}
+	bloom.Enabled = p.BoolWithFallback(prefBloom, bloom.Enabled)
+	if cfg.Effects == nil {
+		cfg.Effects = make(map[string]effectConfig)
+	}
+	cfg.Effects[key] = bloom
+	return cfg
+}
+
+// rememberSettings stores the current settings for the next session.
+func rememberSettings(p fyne.Preferences, cfg userConfig) {
+	p.SetFloat(prefGrowthRate, cfg.GrowthRate)
+	if cfg.MutationChance != nil {
+		p.SetFloat(prefMutationChance, *cfg.MutationChance)
+	}
+	p.SetInt(prefCellSize, cfg.CellSize)
+	p.SetInt(prefSpeed, cfg.Speed)
+	p.SetString(prefPalette, cfg.Palette)
+	p.SetFloat(prefWindowWidth, float64(cfg.Window.Width))
+	p.SetFloat(prefWindowHeight, float64(cfg.Window.Height))
+	if bloom, ok := cfg.Effects[effectKey(bloomEffect{}.Name())]; ok {
+		p.SetBool(prefBloom, bloom.Enabled)
+	}
+}
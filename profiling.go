@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	_ "net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// pprofAddrEnv is the developer option that serves net/http/pprof, e.g.
+// LIVING_NUMBERS_PPROF=localhost:6060.
+const pprofAddrEnv = "LIVING_NUMBERS_PPROF"
+
+// startPprofServer serves the pprof handlers when pprofAddrEnv is set and
+// returns the address listened on, or "" when the option is off. The server
+// stops when life ends.
+func startPprofServer(life *lifetime) (string, error) {
+	addr := os.Getenv(pprofAddrEnv)
+	if addr == "" {
+		return "", nil
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	life.spawn(func() {
+		stop := context.AfterFunc(life.ctx, func() { ln.Close() })
+		defer stop()
+		http.Serve(ln, http.DefaultServeMux) // returns once ln is closed
+	})
+	return ln.Addr().String(), nil
+}
+
+// profileRecorder captures a CPU profile over a number of generations,
+// then a heap profile, into dir.
+type profileRecorder struct {
+	dir       string
+	remaining int
+	cpu       *os.File
+}
+
+func startProfile(dir string, generations int) (*profileRecorder, error) {
+	dir = filepath.Join(dir, "profile-"+time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		return nil, err
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &profileRecorder{dir: dir, remaining: generations, cpu: f}, nil
+}
+
+// generation counts one simulated generation and reports true once the
+// requested number has been profiled and the files are written.
+func (p *profileRecorder) generation() (bool, error) {
+	p.remaining--
+	if p.remaining > 0 {
+		return false, nil
+	}
+	return true, p.stop()
+}
+
+// stop ends the CPU profile and writes the heap profile.
+func (p *profileRecorder) stop() error {
+	pprof.StopCPUProfile()
+	if err := p.cpu.Close(); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(p.dir, "heap.pprof"))
+	if err != nil {
+		return err
+	}
+	runtime.GC() // up-to-date live heap statistics
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// showProfilingDialog starts recording profiles for the next generations
+// of the run. It is opened with Ctrl+Shift+P.
+func showProfilingDialog(w fyne.Window, state *SimulationState, dir, pprofAddr string) {
+	state.mu.Lock()
+	recording := state.profile != nil
+	state.mu.Unlock()
+	if recording {
+		dialog.ShowInformation(lang.L("Profiling"), lang.L("A profile is already being recorded."), w)
+		return
+	}
+
+	gensEntry := widget.NewEntry()
+	gensEntry.SetText("500")
+	server := lang.LocalizeKey("pprof.off", "off (set {{.Env}}=localhost:6060 to enable)", map[string]any{"Env": pprofAddrEnv})
+	if pprofAddr != "" {
+		server = "http://" + pprofAddr + "/debug/pprof/"
+	}
+	items := []*widget.FormItem{
+		widget.NewFormItem(lang.L("Generations"), gensEntry),
+		widget.NewFormItem(lang.L("Output"), widget.NewLabel(dir)),
+		widget.NewFormItem(lang.L("pprof server"), widget.NewLabel(server)),
+	}
+	dialog.ShowForm(lang.L("🛠 Record CPU/heap profile"), lang.L("Record"), lang.L("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		gens, err := strconv.Atoi(gensEntry.Text)
+		if err != nil || gens <= 0 {
+			dialog.ShowError(fmt.Errorf("invalid number of generations %q", gensEntry.Text), w)
+			return
+		}
+		p, err := startProfile(dir, gens)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		state.mu.Lock()
+		state.profile = p
+		addEvent(state, "PROFILE", fmt.Sprintf("Recording %d generations to %s", gens, p.dir))
+		state.mu.Unlock()
+	}, w)
+}
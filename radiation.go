@@ -0,0 +1,81 @@
+package main
+
+import "image"
+
+// Radiation settings, levels running from 0 to 1.
+const (
+	radiationLife     = 150  // generations for a full level to decay away
+	radiationMutation = 0.05 // mutation chance of a living cell at full level
+)
+
+// radiationGlow is the color of the glow over a fully irradiated cell.
+var radiationGlow = [3]float64{120, 255, 80}
+
+// irradiate fills the disc of a supernova with radiation at full level.
+func (s *Simulation) irradiate(cx, cy, radius int) {
+	n := s.gridSize
+	if s.radiation == nil {
+		s.radiation = make([]float64, n*n)
+	}
+	for y := max(cy-radius, 0); y <= min(cy+radius, n-1); y++ {
+		for x := max(cx-radius, 0); x <= min(cx+radius, n-1); x++ {
+			dx, dy := x-cx, y-cy
+			if dx*dx+dy*dy < radius*radius {
+				if s.edit != nil {
+					s.edit.radiation.note(y*n+x, s.radiation[y*n+x], 1)
+				}
+				s.radiation[y*n+x] = 1
+			}
+		}
+	}
+}
+
+// radiate runs the radiation field for one generation: living cells mutate
+// to a random age with a chance growing with the level of their cell, and
+// every level decays linearly over radiationLife generations. The field is
+// dropped once it has decayed everywhere.
+func (s *Simulation) radiate() {
+	left := false
+	for i, level := range s.radiation {
+		if level <= 0 {
+			continue
+		}
+		if s.grid.cells[i] > 0 && s.rng.Float64() < level*radiationMutation {
+			s.change(i, 1+s.rng.Intn(20))
+		}
+		s.radiation[i] = max(level-1.0/radiationLife, 0)
+		left = left || s.radiation[i] > 0
+	}
+	if !left {
+		s.radiation = nil
+	}
+}
+
+// drawRadiation blends a faint glow over the irradiated cells, stronger
+// where the level is higher.
+func drawRadiation(img *image.RGBA, radiation []float64, gridSize, cellSize int) {
+	bounds := img.Bounds()
+	for i, level := range radiation {
+		if level <= 0 {
+			continue
+		}
+		a := 0.3 * level
+		x, y := i%gridSize, i/gridSize
+		for dy := 0; dy < cellSize; dy++ {
+			py := bounds.Min.Y + y*cellSize + dy
+			if py >= bounds.Max.Y {
+				break
+			}
+			for dx := 0; dx < cellSize; dx++ {
+				px := bounds.Min.X + x*cellSize + dx
+				if px >= bounds.Max.X {
+					break
+				}
+				off := img.PixOffset(px, py)
+				for k, g := range radiationGlow {
+					img.Pix[off+k] = uint8(float64(img.Pix[off+k])*(1-a) + g*a)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"image"
+	"image/color"
+)
+
+// Renderer turns the simulation grid into pixels for the display and for
+// exports. Implementations may keep scratch buffers between frames.
+type Renderer interface {
+	Name() string
+	Render(sim *Simulation, img *image.RGBA, palette ColorPalette, cellSize int)
+}
+
+// flatRenderer draws one color per cell, chosen by its colorizer, by age
+// when unset.
+type flatRenderer struct {
+	colorBy Colorizer
+}
+
+func (flatRenderer) Name() string { return "Flat" }
+
+func (r flatRenderer) Render(sim *Simulation, img *image.RGBA, palette ColorPalette, cellSize int) {
+	c := r.colorBy
+	if c.colors == nil {
+		c = colorizers[0]
+	}
+	if d, ok := sim.rule.(ruleDrawer); ok && c.native {
+		d.draw(img, palette, cellSize)
+		return
+	}
+	drawCells(img, cellSize, sim.gridSize, c.colors(sim, palette))
+	if palette.patterned && sim.rule == nil {
+		drawBandPatterns(img, sim.grid, palette, cellSize)
+	}
+}
+
+type colonyRenderer struct{}
+
+func (colonyRenderer) Name() string { return "Colony view" }
+
+func (colonyRenderer) Render(sim *Simulation, img *image.RGBA, palette ColorPalette, cellSize int) {
+	drawColonies(sim.colonyLabels, img, palette.dead, cellSize)
+}
+
+// maxDisparity is the horizontal eye offset, in pixels, of the oldest cells.
+const maxDisparity = 6
+
+// drawEye renders one eye's view: each living cell is shifted horizontally
+// by its depth, older cells appearing closer to the viewer.
+func drawEye(sim *Simulation, img *image.RGBA, palette ColorPalette, cellSize int, origin image.Point, eye int) {
+	bounds := img.Bounds()
+	for y := 0; y < sim.gridSize; y++ {
+		for x := 0; x < sim.gridSize; x++ {
+			val := sim.grid.at(x, y)
+			if val == 0 {
+				continue
+			}
+			shift := eye * val * maxDisparity / 100
+			c := getCellColor(val, palette)
+			px := origin.X + x*cellSize + shift
+			py := origin.Y + y*cellSize
+			for dy := 0; dy < cellSize; dy++ {
+				for dx := 0; dx < cellSize; dx++ {
+					if image.Pt(px+dx, py+dy).In(bounds) {
+						img.Set(px+dx, py+dy, c)
+					}
+				}
+			}
+		}
+	}
+}
+
+func fillRGBA(img *image.RGBA, c color.Color) {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			img.Set(x, y, c)
+		}
+	}
+}
+
+// anaglyphRenderer combines the two eyes into a red/cyan image.
+type anaglyphRenderer struct {
+	left, right *image.RGBA
+}
+
+func (*anaglyphRenderer) Name() string { return "Anaglyph 3D" }
+
+func (r *anaglyphRenderer) Render(sim *Simulation, img *image.RGBA, palette ColorPalette, cellSize int) {
+	bounds := img.Bounds()
+	if r.left == nil || r.left.Bounds() != bounds {
+		r.left = image.NewRGBA(bounds)
+		r.right = image.NewRGBA(bounds)
+	}
+	fillRGBA(r.left, palette.dead)
+	fillRGBA(r.right, palette.dead)
+	drawEye(sim, r.left, palette, cellSize, bounds.Min, 1)
+	drawEye(sim, r.right, palette, cellSize, bounds.Min, -1)
+
+	luma := func(c color.RGBA) uint8 {
+		return uint8((299*int(c.R) + 587*int(c.G) + 114*int(c.B)) / 1000)
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			l := luma(r.left.RGBAAt(x, y))
+			rr := luma(r.right.RGBAAt(x, y))
+			img.SetRGBA(x, y, color.RGBA{l, rr, rr, 255})
+		}
+	}
+}
+
+// stereoRenderer draws the two eyes side by side for parallel viewing.
+type stereoRenderer struct{}
+
+func (stereoRenderer) Name() string { return "Side-by-side stereo" }
+
+func (stereoRenderer) Render(sim *Simulation, img *image.RGBA, palette ColorPalette, cellSize int) {
+	bounds := img.Bounds()
+	fillRGBA(img, palette.dead)
+
+	half := bounds.Dx() / 2
+	eyeCell := half / sim.gridSize
+	if eyeCell < 1 {
+		eyeCell = 1
+	}
+	top := bounds.Min.Y + (bounds.Dy()-eyeCell*sim.gridSize)/2
+
+	leftView := img.SubImage(image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Min.X+half, bounds.Max.Y)).(*image.RGBA)
+	rightView := img.SubImage(image.Rect(bounds.Min.X+half, bounds.Min.Y, bounds.Max.X, bounds.Max.Y)).(*image.RGBA)
+	drawEye(sim, leftView, palette, eyeCell, image.Pt(bounds.Min.X, top), 1)
+	drawEye(sim, rightView, palette, eyeCell, image.Pt(bounds.Min.X+half, top), -1)
+}
+
+// renderers lists the view modes offered in the UI, in display order.
+var renderers = []Renderer{
+	flatRenderer{},
+	colonyRenderer{},
+	&anaglyphRenderer{},
+	stereoRenderer{},
+	energyRenderer{},
+}
+
+func rendererNames() []string {
+	names := make([]string, len(renderers))
+	for i, r := range renderers {
+		names[i] = r.Name()
+	}
+	return names
+}
+
+func rendererByName(name string) Renderer {
+	for _, r := range renderers {
+		if r.Name() == name {
+			return r
+		}
+	}
+	return renderers[0]
+}
+
+// minGridLineCell is the smallest cell size, in pixels, that still leaves
+// room for a grid line.
+const minGridLineCell = 5
+
+// drawGridLines draws a 1px line along the right and bottom edge of every
+// cell. It does nothing at cell sizes below minGridLineCell.
+func drawGridLines(img *image.RGBA, cellSize, gridSize int, c color.RGBA) {
+	if cellSize < minGridLineCell {
+		return
+	}
+	extent := cellSize * gridSize
+	bounds := img.Bounds()
+	for i := 1; i <= gridSize; i++ {
+		p := i*cellSize - 1
+		for q := 0; q < extent; q++ {
+			if image.Pt(p, q).In(bounds) {
+				img.SetRGBA(p, q, c)
+			}
+			if image.Pt(q, p).In(bounds) {
+				img.SetRGBA(q, p, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// drawGridSet is the per-pixel img.Set renderer drawGridDynamic replaced,
+// kept as the benchmark baseline and as the reference output.
+func drawGridSet(grid *Grid, img *image.RGBA, palette ColorPalette, cellSize int, gridSize int) {
+	for y := 0; y < gridSize; y++ {
+		for x := 0; x < gridSize; x++ {
+			c := getCellColor(grid.at(x, y), palette)
+			for dy := 0; dy < cellSize; dy++ {
+				for dx := 0; dx < cellSize; dx++ {
+					img.Set(x*cellSize+dx, y*cellSize+dy, c)
+				}
+			}
+		}
+	}
+}
+
+func benchGrid(cellSize int) (*Grid, ColorPalette, int) {
+	rng := rand.New(rand.NewSource(1))
+	gridSize := displaySize / cellSize
+	grid := newGrid(gridSize)
+	for i := range grid.cells {
+		if rng.Intn(2) == 0 {
+			grid.cells[i] = uint8(1 + rng.Intn(50))
+		}
+	}
+	return grid, generateDynamicPalette(rng, 0, 3), gridSize
+}
+
+func TestDrawGridDynamicMatchesSet(t *testing.T) {
+	for _, cellSize := range []int{2, 3, 5, 7} {
+		grid, palette, gridSize := benchGrid(cellSize)
+		want := image.NewRGBA(image.Rect(0, 0, displaySize, displaySize))
+		got := image.NewRGBA(image.Rect(0, 0, displaySize, displaySize))
+		drawGridSet(grid, want, palette, cellSize, gridSize)
+		drawGridDynamic(grid, got, palette, cellSize, gridSize)
+		if string(want.Pix) != string(got.Pix) {
+			t.Errorf("cell size %d: output differs from img.Set renderer", cellSize)
+		}
+	}
+}
+
+func benchmarkDraw(b *testing.B, cellSize int, draw func(*Grid, *image.RGBA, ColorPalette, int, int)) {
+	grid, palette, gridSize := benchGrid(cellSize)
+	img := image.NewRGBA(image.Rect(0, 0, displaySize, displaySize))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		draw(grid, img, palette, cellSize, gridSize)
+	}
+}
+
+func BenchmarkDrawGridSet2px(b *testing.B) { benchmarkDraw(b, 2, drawGridSet) }
+func BenchmarkDrawGridPix2px(b *testing.B) { benchmarkDraw(b, 2, drawGridDynamic) }
+func BenchmarkDrawGridSet5px(b *testing.B) { benchmarkDraw(b, 5, drawGridSet) }
+func BenchmarkDrawGridPix5px(b *testing.B) { benchmarkDraw(b, 5, drawGridDynamic) }
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// rleLineWidth is the longest pattern line written, as the format asks.
+const rleLineWidth = 70
+
+// maxRLERun bounds the runs read, far above any grid size, so a corrupt
+// file cannot exhaust memory.
+const maxRLERun = 1 << 16
+
+// maxPatternCells bounds the cells of a pattern read, rows counting as one
+// cell at least, so a short file of long runs cannot exhaust memory either.
+const maxPatternCells = 1 << 22
+
+// rleAgeRule names the rule of the RLE files that keep ages.
+const rleAgeRule = "LivingNumbers"
+
+// rleState is the token of a cell: b and o in plain RLE, and with ages the
+// multi-state tokens of Golly, where the age is the state: . for empty
+// cells, A to X for ages 1 to 24, then pA to pX, qA to qX...
+func rleState(v int, ages bool) string {
+	switch {
+	case v <= 0 && ages:
+		return "."
+	case v <= 0:
+		return "b"
+	case !ages:
+		return "o"
+	case v <= 24:
+		return string(rune('A' + v - 1))
+	default:
+		return string(rune('p'+(v-25)/24)) + string(rune('A'+(v-25)%24))
+	}
+}
+
+// writeRLE writes the living cells of values as an RLE pattern cropped to
+// their bounding box. Plain RLE keeps only whether cells live, for Life
+// tools; with ages every cell keeps its age, for this app.
+func writeRLE(w io.Writer, values [][]int, generation int, ages bool) error {
+	minX, minY, maxX, maxY := len(values), len(values), -1, -1
+	for y, row := range values {
+		for x, v := range row {
+			if v > 0 {
+				minX, minY = min(minX, x), min(minY, y)
+				maxX, maxY = max(maxX, x), max(maxY, y)
+			}
+		}
+	}
+	if maxX < 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0 // an empty pattern
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "#C Living numbers, generation %d\n", generation)
+	fmt.Fprintf(out, "x = %d, y = %d", maxX-minX+1, maxY-minY+1)
+	if ages {
+		fmt.Fprintf(out, ", rule = %s", rleAgeRule)
+	}
+	out.WriteByte('\n')
+
+	line := 0
+	emit := func(count int, token string) {
+		item := token
+		if count > 1 {
+			item = strconv.Itoa(count) + token
+		}
+		if line+len(item) > rleLineWidth {
+			out.WriteByte('\n')
+			line = 0
+		}
+		out.WriteString(item)
+		line += len(item)
+	}
+	blankRows := 0
+	for y := minY; y <= maxY; y++ {
+		row := values[y][minX : maxX+1]
+		end := len(row) // trailing empty cells are left out
+		for end > 0 && row[end-1] <= 0 {
+			end--
+		}
+		if end == 0 && y < maxY {
+			blankRows++
+			continue
+		}
+		if y > minY {
+			emit(blankRows+1, "$")
+		}
+		blankRows = 0
+		for x := 0; x < end; {
+			token := rleState(row[x], ages)
+			run := 1
+			for x+run < end && rleState(row[x+run], ages) == token {
+				run++
+			}
+			emit(run, token)
+			x += run
+		}
+	}
+	emit(1, "!")
+	out.WriteByte('\n')
+	return out.Flush()
+}
+
+// parseRLE reads an RLE pattern, plain or with ages, into grid values. Live
+// cells of plain RLE are newborns, of age 1.
+func parseRLE(text string) ([][]int, error) {
+	var values [][]int
+	row := []int{}
+	count := 0
+	cells := 0
+	prefix := 0 // multi-state prefix p to y, as 1 to 10
+	add := func(v int) {
+		for range max(count, 1) {
+			row = append(row, v)
+		}
+		cells += max(count, 1)
+		count = 0
+	}
+	header := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !header && strings.HasPrefix(line, "x") {
+			header = true
+			continue
+		}
+		for _, r := range line {
+			if cells > maxPatternCells {
+				return nil, fmt.Errorf("RLE pattern larger than %d cells", maxPatternCells)
+			}
+			switch {
+			case r >= '0' && r <= '9':
+				count = count*10 + int(r-'0')
+				if count > maxRLERun {
+					return nil, fmt.Errorf("RLE run longer than %d cells", maxRLERun)
+				}
+			case r >= 'p' && r <= 'y':
+				prefix = int(r-'p') + 1
+			case r >= 'A' && r <= 'X':
+				add(24*prefix + int(r-'A') + 1)
+				prefix = 0
+			case r == 'b' || r == '.':
+				add(0)
+			case r == '$':
+				for range max(count, 1) {
+					values = append(values, row)
+					row = []int{}
+				}
+				cells += max(count, 1)
+				count = 0
+			case r == '!':
+				return append(values, row), nil
+			case unicode.IsLetter(r):
+				add(1) // o, and the other letters Life tools read as live
+			case unicode.IsSpace(r):
+			default:
+				return nil, fmt.Errorf("invalid RLE character %q", r)
+			}
+		}
+	}
+	if !header {
+		return nil, errors.New("not an RLE pattern: missing x = header")
+	}
+	return append(values, row), nil
+}
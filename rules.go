@@ -0,0 +1,166 @@
+package main
+
+import (
+	"image"
+	"math"
+	"strconv"
+)
+
+// Rule is an alternative engine for the grid, a rule family other than the
+// built-in living numbers. A rule either works on sim.grid directly or keeps
+// its own state and mirrors it into sim.grid, as values from 0 to maxCellAge,
+// so statistics, colonies, events and the view modes keep working.
+type Rule interface {
+	Name() string
+	// seed scatters a random initial state from sim.rng.
+	seed(sim *Simulation)
+	// load takes the current values of sim.grid as the state.
+	load(sim *Simulation)
+	// step advances one generation and returns the number of births.
+	step(sim *Simulation) (births int)
+	// set changes one cell, given as a grid value.
+	set(x, y, val int)
+}
+
+// ruleDrawer is implemented by rules with their own flat rendering, e.g.
+// continuous states drawn as smooth gradients.
+type ruleDrawer interface {
+	draw(img *image.RGBA, palette ColorPalette, cellSize int)
+}
+
+// tunable is implemented by rules with parameters, set when the rule is
+// created and whenever their slider moves.
+type tunable interface {
+	tune(name string, value float64)
+}
+
+// ruleParam is a parameter of a rule family, shown as a slider below the
+// Rule family selector while the family is selected.
+type ruleParam struct {
+	name           string
+	min, max, step float64
+	value          float64 // default
+}
+
+// statsReporter is implemented by rules adding their own figures to the
+// statistics of each generation.
+type statsReporter interface {
+	report(s *Stats)
+}
+
+// strider is implemented by rules advancing several generations per step,
+// counted as such by the generation counter.
+type strider interface {
+	stride() int
+}
+
+// ruleEvent is a notable change reported by a rule for the event log.
+type ruleEvent struct {
+	kind, msg string
+}
+
+// ruleFamily is an engine offered by the Rule family selector.
+type ruleFamily struct {
+	name    string
+	newRule func() Rule
+	params  []ruleParam
+}
+
+// ruleFamilies lists the rule families in display order. The built-in
+// rules have no Rule value.
+var ruleFamilies = []ruleFamily{
+	{"Living numbers", nil, nil},
+	{"Lenia", func() Rule { return &lenia{} }, nil},
+	{"Brian's Brain", func() Rule { return briansBrain{} }, nil},
+	{"Wireworld", func() Rule { return wireworld{} }, nil},
+	{"Conway's Life", func() Rule { return conwayLife{} }, nil},
+	{"Conway's Life (HashLife)", func() Rule { return &hashLife{} }, []ruleParam{
+		{"Jump (2^n generations)", 0, 12, 1, 0},
+	}},
+	{"Forest fire", func() Rule { return &forestFire{} }, []ruleParam{
+		{"Tree growth", 0, 0.1, 0.001, 0.01},
+		{"Lightning", 0, 0.001, 0.00001, 0.00005},
+	}},
+	{"Wa-Tor", func() Rule { return &wator{} }, []ruleParam{
+		{"Fish breeding", 1, 20, 1, 3},
+		{"Shark breeding", 1, 30, 1, 10},
+		{"Shark starvation", 1, 20, 1, 3},
+	}},
+	{"Gray-Scott", func() Rule { return &grayScott{} }, []ruleParam{
+		{"Feed", 0.01, 0.1, 0.0001, 0.0367},
+		{"Kill", 0.04, 0.07, 0.0001, 0.0649},
+	}},
+	{"Elementary 1D", func() Rule { return &elementary{} }, []ruleParam{
+		{"Wolfram rule", 0, 255, 1, 30},
+	}},
+}
+
+func ruleFamilyNames() []string {
+	names := make([]string, len(ruleFamilies))
+	for i, f := range ruleFamilies {
+		names[i] = f.name
+	}
+	return names
+}
+
+// newRuleFamily returns a fresh engine of the named family, tuned with the
+// default parameters, or nil for the built-in rules or an unknown name.
+func newRuleFamily(name string) Rule {
+	for _, f := range ruleFamilies {
+		if f.name == name && f.newRule != nil {
+			rule := f.newRule()
+			tuneRule(rule, defaultRuleParams()[name])
+			return rule
+		}
+	}
+	return nil
+}
+
+// ruleFamilyParams returns the parameters of the named family.
+func ruleFamilyParams(name string) []ruleParam {
+	for _, f := range ruleFamilies {
+		if f.name == name {
+			return f.params
+		}
+	}
+	return nil
+}
+
+// format writes a value of the parameter with the decimals of its step.
+func (p ruleParam) format(value float64) string {
+	decimals := max(0, int(math.Ceil(-math.Log10(p.step))))
+	return strconv.FormatFloat(value, 'f', decimals, 64)
+}
+
+// defaultRuleParams returns the default parameter values of every family
+// with parameters.
+func defaultRuleParams() map[string][]float64 {
+	values := make(map[string][]float64)
+	for _, f := range ruleFamilies {
+		for _, p := range f.params {
+			values[f.name] = append(values[f.name], p.value)
+		}
+	}
+	return values
+}
+
+// tuneRule passes values, in the order of the family's parameters, to rule.
+func tuneRule(rule Rule, values []float64) {
+	t, ok := rule.(tunable)
+	if !ok {
+		return
+	}
+	for i, p := range ruleFamilyParams(rule.Name()) {
+		if i < len(values) {
+			t.tune(p.name, values[i])
+		}
+	}
+}
+
+// ruleName is the family name of rule, empty for the built-in rules.
+func ruleName(rule Rule) string {
+	if rule == nil {
+		return ""
+	}
+	return rule.Name()
+}
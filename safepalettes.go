@@ -0,0 +1,80 @@
+package main
+
+import (
+	"image"
+	"image/color"
+)
+
+// Palette modes readable with a color vision deficiency, marked 👁 in the
+// palette selector.
+const (
+	deuteranopiaMode = 5
+	protanopiaMode   = 6
+	tritanopiaMode   = 7
+	monochromeMode   = 8
+)
+
+// safeBases are the young, mature and old colors of the colorblind-safe
+// modes, after the Okabe-Ito set: the three bands differ in hue along the
+// axis each deficiency keeps and, in case that is not enough, in lightness.
+var safeBases = map[int][3]color.RGBA{
+	deuteranopiaMode: {{86, 180, 233, 255}, {230, 159, 0, 255}, {0, 70, 150, 255}},
+	protanopiaMode:   {{240, 228, 66, 255}, {86, 180, 233, 255}, {0, 90, 170, 255}},
+	tritanopiaMode:   {{255, 150, 170, 255}, {0, 158, 115, 255}, {170, 20, 20, 255}},
+	monochromeMode:   {{240, 240, 240, 255}, {160, 160, 160, 255}, {90, 90, 90, 255}},
+}
+
+// fillSafeRamps fills the ramps of a colorblind-safe palette: shades of the
+// band's base color only, without the random jitter of the other palettes,
+// so the bands never drift towards each other.
+func fillSafeRamps(p *ColorPalette, bases [3]color.RGBA) {
+	shade := func(c color.RGBA, f float64) color.Color {
+		return color.RGBA{uint8(float64(c.R) * f), uint8(float64(c.G) * f), uint8(float64(c.B) * f), 255}
+	}
+	for i := range p.young {
+		p.young[i] = shade(bases[0], 0.8+0.2*float64(i)/float64(len(p.young)-1))
+	}
+	for i := range p.mature {
+		p.mature[i] = shade(bases[1], 1-0.2*float64(i)/float64(len(p.mature)-1))
+	}
+	for i := range p.old {
+		p.old[i] = shade(bases[2], 1-0.4*float64(i)/float64(len(p.old)-1))
+	}
+	p.bloomIntensity = 0.2
+}
+
+// minPatternCell is the smallest cell size, in pixels, that fits a pattern.
+const minPatternCell = 3
+
+// drawBandPatterns marks the age bands of a patterned palette with a
+// texture in the dead color, readable without any color: young cells stay
+// plain, mature cells get a dot in their center and old cells diagonal
+// stripes.
+func drawBandPatterns(img *image.RGBA, grid *Grid, palette ColorPalette, cellSize int) {
+	if cellSize < minPatternCell {
+		return
+	}
+	dead := color.RGBAModel.Convert(palette.dead).(color.RGBA)
+	bounds := img.Bounds()
+	set := func(px, py int) {
+		if p := image.Pt(bounds.Min.X+px, bounds.Min.Y+py); p.In(bounds) {
+			img.SetRGBA(p.X, p.Y, dead)
+		}
+	}
+	for y := range grid.size {
+		for x, c := range grid.row(y) {
+			switch cellBand(int(c)) {
+			case bandMature:
+				set(x*cellSize+cellSize/2, y*cellSize+cellSize/2)
+			case bandOld:
+				for dy := range cellSize {
+					for dx := range cellSize {
+						if (dx+dy)%3 == 2 {
+							set(x*cellSize+dx, y*cellSize+dy)
+						}
+					}
+				}
+			}
+		}
+	}
+}
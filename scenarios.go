@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+type ScenarioStatus int
+
+const (
+	ScenarioRunning ScenarioStatus = iota
+	ScenarioSuccess
+	ScenarioFailed
+)
+
+// scenarioProgress is the per-run bookkeeping of a challenge.
+type scenarioProgress struct {
+	streak int
+}
+
+// Scenario is a predefined challenge with starting parameters and an
+// automatic success/failure check run after every generation.
+type Scenario struct {
+	name           string
+	description    string
+	growthRate     float64
+	mutationChance float64
+	maxGrowth      float64 // 0 when the growth rate is not constrained
+	check          func(s Stats, full bool, p *scenarioProgress) (ScenarioStatus, string)
+}
+
+// streakCheck succeeds once cond has held for n consecutive generations and
+// fails on extinction, a full grid or after limit generations.
+func streakCheck(n, limit int, what string, cond func(Stats) bool) func(Stats, bool, *scenarioProgress) (ScenarioStatus, string) {
+	return func(s Stats, full bool, p *scenarioProgress) (ScenarioStatus, string) {
+		if cond(s) {
+			p.streak++
+		} else {
+			p.streak = 0
+		}
+		switch {
+		case p.streak >= n:
+			return ScenarioSuccess, fmt.Sprintf(lang.L("%s for %d generations!"), lang.L(what), n)
+		case s.population == 0:
+			return ScenarioFailed, lang.L("Population went extinct")
+		case full:
+			return ScenarioFailed, lang.L("The grid filled up")
+		case s.generation >= limit:
+			return ScenarioFailed, fmt.Sprintf(lang.L("Time is up after %d generations"), limit)
+		}
+		return ScenarioRunning, fmt.Sprintf(lang.L("%s: %d/%d generations"), lang.L(what), p.streak, n)
+	}
+}
+
+var scenarios = []*Scenario{
+	{
+		name:           "Fast colonizer",
+		description:    "Fill the whole grid in under 500 generations with growth ≤ 0.10.",
+		growthRate:     0.10,
+		mutationChance: 0.01,
+		maxGrowth:      0.10,
+		check: func(s Stats, full bool, p *scenarioProgress) (ScenarioStatus, string) {
+			switch {
+			case full && s.generation < 500:
+				return ScenarioSuccess, fmt.Sprintf(lang.L("Grid filled in %d generations!"), s.generation)
+			case s.generation >= 500:
+				return ScenarioFailed, "500 generations passed before the grid filled"
+			case s.population == 0:
+				return ScenarioFailed, lang.L("Population went extinct")
+			}
+			return ScenarioRunning, fmt.Sprintf(lang.L("Density %.1f%% at gen %d/500"), s.density*100, s.generation)
+		},
+	},
+	{
+		name:           "Balanced population",
+		description:    "Keep the density between 30% and 50% for 200 consecutive generations.",
+		growthRate:     0.05,
+		mutationChance: 0.02,
+		check: streakCheck(200, 3000, "Density within 30-50%", func(s Stats) bool {
+			return s.density >= 0.3 && s.density <= 0.5
+		}),
+	},
+	{
+		name:           "Archipelago",
+		description:    "Maintain at least 10 separate colonies for 100 consecutive generations.",
+		growthRate:     0.05,
+		mutationChance: 0.0,
+		check: streakCheck(100, 2000, "10+ colonies", func(s Stats) bool {
+			return s.colonies >= 10
+		}),
+	},
+	{
+		name:           "Slow and steady",
+		description:    "Reach generation 1000 without the grid filling up or dying out.",
+		growthRate:     0.05,
+		mutationChance: 0.05,
+		check: func(s Stats, full bool, p *scenarioProgress) (ScenarioStatus, string) {
+			switch {
+			case full:
+				return ScenarioFailed, lang.L("The grid filled up")
+			case s.population == 0:
+				return ScenarioFailed, lang.L("Population went extinct")
+			case s.generation >= 1000:
+				return ScenarioSuccess, lang.L("Still evolving after 1000 generations!")
+			}
+			return ScenarioRunning, fmt.Sprintf(lang.L("Generation %d/1000"), s.generation)
+		},
+	},
+}
+
+// ActiveScenario follows the challenge chosen for the current run.
+type ActiveScenario struct {
+	scenario *Scenario
+	progress scenarioProgress
+	status   ScenarioStatus
+	message  string
+}
+
+func (a *ActiveScenario) start(growthRate float64) {
+	a.progress = scenarioProgress{}
+	a.status = ScenarioRunning
+	a.message = lang.L(a.scenario.description)
+	if a.scenario.maxGrowth > 0 && growthRate > a.scenario.maxGrowth+1e-9 {
+		a.status = ScenarioFailed
+		a.message = fmt.Sprintf(lang.L("Growth rate %.2f exceeds the %.2f limit"), growthRate, a.scenario.maxGrowth)
+	}
+}
+
+// update evaluates the challenge and reports whether it just finished.
+func (a *ActiveScenario) update(s Stats, full bool) bool {
+	if a.status != ScenarioRunning {
+		return false
+	}
+	a.status, a.message = a.scenario.check(s, full, &a.progress)
+	return a.status != ScenarioRunning
+}
+
+func (a *ActiveScenario) String() string {
+	data := map[string]any{"Scenario": lang.L(a.scenario.name), "Message": a.message}
+	switch a.status {
+	case ScenarioSuccess:
+		return lang.LocalizeKey("scenario.success", "🏆 {{.Scenario}}: SUCCESS - {{.Message}}", data)
+	case ScenarioFailed:
+		return lang.LocalizeKey("scenario.failed", "🏆 {{.Scenario}}: FAILED - {{.Message}}", data)
+	}
+	return lang.LocalizeKey("scenario.running", "🏆 {{.Scenario}}: {{.Message}}", data)
+}
+
+// showScenarioPicker lets the user choose a challenge; onPick receives nil
+// when free play is selected.
+func showScenarioPicker(w fyne.Window, onPick func(*Scenario)) {
+	names := []string{lang.L("Free play (no challenge)")}
+	for _, s := range scenarios {
+		names = append(names, lang.L(s.name))
+	}
+	selected := 0
+
+	details := widget.NewLabel(lang.L("Experiment freely without objectives."))
+	details.Wrapping = fyne.TextWrapWord
+
+	list := widget.NewList(
+		func() int { return len(names) },
+		func() fyne.CanvasObject { return widget.NewLabel(lang.L("Balanced population")) },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(names[id])
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		if id == 0 {
+			details.SetText(lang.L("Experiment freely without objectives."))
+			return
+		}
+		s := scenarios[id-1]
+		details.SetText(fmt.Sprintf(lang.L("%s\n\nStarting parameters: growth %.2f, mutation %.3f"),
+			lang.L(s.description), s.growthRate, s.mutationChance))
+	}
+	list.Select(0)
+
+	content := container.NewBorder(nil, details, nil, nil, list)
+	d := dialog.NewCustomConfirm(lang.L("Choose a scenario"), lang.L("Play"), lang.L("Cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		if selected == 0 {
+			onPick(nil)
+			return
+		}
+		onPick(scenarios[selected-1])
+	}, w)
+	d.Resize(fyne.NewSize(420, 380))
+	d.Show()
+}
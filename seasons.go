@@ -0,0 +1,34 @@
+package main
+
+import "math"
+
+// Seasonal swings at the height of summer and winter: the growth rate is
+// scaled by 1 ± seasonGrowth and the ageing threshold moved by
+// ± seasonAging.
+const (
+	seasonGrowth = 0.6
+	seasonAging  = 6.0
+)
+
+// seasonNames are the quarters of a season cycle.
+var seasonNames = [4]string{"Spring", "Summer", "Autumn", "Winter"}
+
+// seasonWave is the season's modulation at the current generation, from -1
+// in midwinter to 1 in midsummer, 0 while seasons are off. Each quarter of
+// the cycle is centered on a season, the first on the rise of spring.
+func (s *Simulation) seasonWave() float64 {
+	if s.seasonPeriod <= 0 {
+		return 0
+	}
+	return math.Sin(2*math.Pi*float64(s.generation)/float64(s.seasonPeriod) - math.Pi/4)
+}
+
+// season returns the name of the current season, empty while seasons are
+// off or a rule family runs, and whether it began with this generation.
+func (s *Simulation) season() (name string, began bool) {
+	quarter := s.seasonPeriod / 4
+	if quarter <= 0 || s.rule != nil {
+		return "", false
+	}
+	return seasonNames[s.generation/quarter%4], s.generation%quarter == 0
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// sessionStore is the on-disk directory backing one application session.
+type sessionStore struct {
+	dir string
+}
+
+type storedEvent struct {
+	Generation int    `json:"generation"`
+	Type       string `json:"type"`
+	Message    string `json:"message"`
+}
+
+// keptSessions is how many session directories are kept, the new one
+// included; older ones are deleted when a session opens.
+const keptSessions = 10
+
+// openSessionStore creates a fresh session directory under the user cache
+// and prunes the oldest ones.
+func openSessionStore() (*sessionStore, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	sessions := filepath.Join(base, "living-numbers", "sessions")
+	dir := filepath.Join(sessions, time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	pruneSessions(sessions, keptSessions)
+	return &sessionStore{dir: dir}, nil
+}
+
+// pruneSessions deletes all but the newest keep session directories under
+// sessions. Their timestamped names sort by age.
+func pruneSessions(sessions string, keep int) {
+	entries, err := os.ReadDir(sessions)
+	if err != nil {
+		return
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs = append(dirs, e.Name())
+		}
+	}
+	slices.Sort(dirs)
+	for _, name := range dirs[:max(len(dirs)-keep, 0)] {
+		os.RemoveAll(filepath.Join(sessions, name))
+	}
+}
+
+func (s *sessionStore) eventsPath() string {
+	return filepath.Join(s.dir, "events.jsonl")
+}
+
+// appendEvents adds events to the session's event file, one JSON object per line.
+func (s *sessionStore) appendEvents(events []Event) error {
+	f, err := os.OpenFile(s.eventsPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, e := range events {
+		if err := enc.Encode(storedEvent{e.generation, e.eventType, e.message}); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// readEvents returns every event previously appended, oldest first.
+func (s *sessionStore) readEvents() ([]Event, error) {
+	f, err := os.Open(s.eventsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var se storedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &se); err != nil {
+			return events, err
+		}
+		events = append(events, Event{generation: se.Generation, eventType: se.Type, message: se.Message})
+	}
+	return events, scanner.Err()
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// shareURLEnv presets the share server endpoint; nothing is ever uploaded
+// unless the user confirms it in the share dialog.
+const shareURLEnv = "LIVING_NUMBERS_SHARE_URL"
+
+const thumbnailSize = 96
+
+// sharedConfig is the recipe exchanged with the share server: the run
+// parameters plus a PNG thumbnail of the grid when it was shared.
+type sharedConfig struct {
+	ID             string    `json:"id,omitempty"`
+	Name           string    `json:"name"`
+	Author         string    `json:"author,omitempty"`
+	Created        time.Time `json:"created"`
+	GrowthRate     float64   `json:"growth_rate"`
+	MutationChance float64   `json:"mutation_chance"`
+	CellSize       int       `json:"cell_size"`
+	Speed          int       `json:"speed"`
+	PaletteMode    int       `json:"palette_mode"`
+	Symmetry       string    `json:"symmetry"`
+	GrowthCurve    string    `json:"growth_curve,omitempty"`
+	MutationCurve  string    `json:"mutation_curve,omitempty"`
+	Generation     int       `json:"generation"`
+	Thumbnail      []byte    `json:"thumbnail,omitempty"`
+}
+
+type shareSummary struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Author  string    `json:"author"`
+	Created time.Time `json:"created"`
+}
+
+func captureShare(state *SimulationState, img image.Image, name, author string) (sharedConfig, error) {
+	cfg := sharedConfig{
+		Name:           name,
+		Author:         author,
+		Created:        time.Now().UTC(),
+		GrowthRate:     state.growthRate,
+		MutationChance: state.mutationChance,
+		CellSize:       state.cellSize,
+		Speed:          state.speed,
+		PaletteMode:    state.paletteMode,
+		Symmetry:       state.symmetry.String(),
+		Generation:     state.stats.generation,
+	}
+	if state.automation.enabled {
+		cfg.GrowthCurve = state.automation.growth.String()
+		cfg.MutationCurve = state.automation.mutation.String()
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, thumbnail(img, thumbnailSize)); err != nil {
+		return cfg, err
+	}
+	cfg.Thumbnail = buf.Bytes()
+	return cfg, nil
+}
+
+// thumbnail downscales img by nearest-neighbour sampling.
+func thumbnail(img image.Image, size int) *image.RGBA {
+	src := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dst.Set(x, y, img.At(src.Min.X+x*src.Dx()/size, src.Min.Y+y*src.Dy()/size))
+		}
+	}
+	return dst
+}
+
+// shareClient talks to a share server exposing POST/GET /shares and
+// GET /shares/{id}.
+type shareClient struct {
+	base string
+	http *http.Client
+}
+
+func newShareClient(endpoint string) (*shareClient, error) {
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid share server URL %q", endpoint)
+	}
+	return &shareClient{base: endpoint, http: &http.Client{Timeout: 15 * time.Second}}, nil
+}
+
+func (c *shareClient) do(req *http.Request, out any) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("share server: %s %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 8<<20)).Decode(out)
+}
+
+// upload publishes cfg and returns its share link.
+func (c *shareClient) upload(cfg sharedConfig) (string, error) {
+	body, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.base+"/shares", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var reply struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := c.do(req, &reply); err != nil {
+		return "", err
+	}
+	if reply.URL != "" {
+		return reply.URL, nil
+	}
+	if reply.ID == "" {
+		return "", errors.New("share server returned no id")
+	}
+	return c.base + "/shares/" + url.PathEscape(reply.ID), nil
+}
+
+func (c *shareClient) list() ([]shareSummary, error) {
+	req, err := http.NewRequest(http.MethodGet, c.base+"/shares", nil)
+	if err != nil {
+		return nil, err
+	}
+	var shares []shareSummary
+	return shares, c.do(req, &shares)
+}
+
+func (c *shareClient) fetch(id string) (sharedConfig, error) {
+	var cfg sharedConfig
+	req, err := http.NewRequest(http.MethodGet, c.base+"/shares/"+url.PathEscape(id), nil)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, c.do(req, &cfg)
+}
+
+func shareEndpointEntry(state *SimulationState) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("https://example.org/living-numbers")
+	if state.shareEndpoint == "" {
+		state.shareEndpoint = os.Getenv(shareURLEnv)
+	}
+	entry.SetText(state.shareEndpoint)
+	return entry
+}
+
+// showShareDialog uploads the current configuration after explicit consent.
+func showShareDialog(w fyne.Window, driver fyne.Driver, state *SimulationState, img image.Image) {
+	endpointEntry := shareEndpointEntry(state)
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder(lang.L("My discovery"))
+	authorEntry := widget.NewEntry()
+	authorEntry.SetPlaceHolder(lang.L("optional"))
+	consent := widget.NewCheck(lang.L("Publish these parameters and a thumbnail of the grid on this server"), nil)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(lang.L("Server"), endpointEntry),
+		widget.NewFormItem(lang.L("Name"), nameEntry),
+		widget.NewFormItem(lang.L("Author"), authorEntry),
+		widget.NewFormItem("", consent),
+	}
+	d := dialog.NewForm(lang.L("🌐 Share configuration"), lang.L("Upload"), lang.L("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		if !consent.Checked {
+			dialog.ShowInformation(lang.L("Share"), lang.L("Nothing was uploaded: publishing was not confirmed."), w)
+			return
+		}
+		client, err := newShareClient(endpointEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		state.shareEndpoint = client.base
+		name := strings.TrimSpace(nameEntry.Text)
+		if name == "" {
+			name = "Untitled"
+		}
+		state.mu.Lock()
+		cfg, err := captureShare(state, img, name, strings.TrimSpace(authorEntry.Text))
+		state.mu.Unlock()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		go func() {
+			link, err := client.upload(cfg)
+			runOnMain(driver, func() {
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				addEvent(state, "SHARE", "Configuration shared: "+link)
+				linkEntry := widget.NewEntry()
+				linkEntry.SetText(link)
+				copyButton := widget.NewButton(lang.L("📋 Copy link"), func() {
+					fyne.CurrentApp().Clipboard().SetContent(link)
+				})
+				dialog.ShowCustom(lang.L("Shared!"), lang.L("Close"), container.NewVBox(linkEntry, copyButton), w)
+			})
+		}()
+	}, w)
+	d.Resize(fyne.NewSize(460, 280))
+	d.Show()
+}
+
+// showBrowseSharedDialog lists the configurations on the share server;
+// onLoad receives the one the user picks.
+func showBrowseSharedDialog(w fyne.Window, driver fyne.Driver, state *SimulationState, onLoad func(sharedConfig)) {
+	endpointEntry := shareEndpointEntry(state)
+	var shares []shareSummary
+	var client *shareClient
+	var selected *sharedConfig
+
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	preview := canvas.NewImageFromImage(image.NewRGBA(image.Rect(0, 0, thumbnailSize, thumbnailSize)))
+	preview.FillMode = canvas.ImageFillContain
+	preview.ScaleMode = canvas.ImageScalePixels
+	preview.SetMinSize(fyne.NewSize(thumbnailSize, thumbnailSize))
+
+	loadButton := widget.NewButton(lang.L("Load"), func() {
+		if selected != nil {
+			onLoad(*selected)
+		}
+	})
+	loadButton.Disable()
+
+	list := widget.NewList(
+		func() int { return len(shares) },
+		func() fyne.CanvasObject { return widget.NewLabel(lang.L("Shared configuration name")) },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			s := shares[id]
+			text := s.Name
+			if s.Author != "" {
+				text = lang.LocalizeKey("share.by_author", "{{.Name}} by {{.Author}}", map[string]any{"Name": s.Name, "Author": s.Author})
+			}
+			o.(*widget.Label).SetText(text)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		shareID := shares[id].ID
+		selected = nil
+		loadButton.Disable()
+		status.SetText(lang.L("Downloading..."))
+		go func() {
+			cfg, err := client.fetch(shareID)
+			runOnMain(driver, func() {
+				if err != nil {
+					status.SetText(err.Error())
+					return
+				}
+				selected = &cfg
+				status.SetText(fmt.Sprintf(lang.L("%s\nGrowth %.2f, mutation %.3f, cells %dpx, %s\nShared at generation %d"),
+					cfg.Name, cfg.GrowthRate, cfg.MutationChance, cfg.CellSize, lang.L(cfg.Symmetry), cfg.Generation))
+				if thumb, err := png.Decode(bytes.NewReader(cfg.Thumbnail)); err == nil {
+					preview.Image = thumb
+					preview.Refresh()
+				}
+				loadButton.Enable()
+			})
+		}()
+	}
+
+	refreshButton := widget.NewButton(lang.L("🔄 Refresh"), func() {
+		c, err := newShareClient(endpointEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		client = c
+		state.shareEndpoint = c.base
+		status.SetText(lang.L("Loading list..."))
+		go func() {
+			fetched, err := c.list()
+			runOnMain(driver, func() {
+				if err != nil {
+					status.SetText(err.Error())
+					return
+				}
+				shares = fetched
+				list.UnselectAll()
+				list.Refresh()
+				status.SetText(fmt.Sprintf(lang.L("%d shared configurations"), len(shares)))
+			})
+		}()
+	})
+
+	top := container.NewBorder(nil, nil, widget.NewLabel(lang.L("Server")), refreshButton, endpointEntry)
+	details := container.NewBorder(nil, nil, preview, nil, status)
+	content := container.NewBorder(top, container.NewVBox(details, loadButton), nil, nil, list)
+	d := dialog.NewCustom(lang.L("🌐 Browse shared"), lang.L("Close"), content, w)
+	d.Resize(fyne.NewSize(480, 460))
+	d.Show()
+}
+
+// validate clamps a downloaded configuration to the ranges the UI accepts.
+func (c *sharedConfig) validate() {
+	c.GrowthRate = clampFloat(c.GrowthRate, 0.05, 0.5)
+	c.MutationChance = clampFloat(c.MutationChance, 0, 0.1)
+	c.CellSize = int(clampFloat(float64(c.CellSize), 2, 8))
+	c.Speed = int(clampFloat(float64(c.Speed), 10, 200))
+	if c.PaletteMode < 0 || c.PaletteMode >= len(paletteModeNames) {
+		c.PaletteMode = 3
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// shutdownTimeout bounds how long exit waits for background work.
+const shutdownTimeout = 3 * time.Second
+
+// lifetime ties background goroutines to the application: its context is
+// cancelled when the main window closes, and shutdown waits for the
+// goroutines so files being written are completed before the process exits.
+type lifetime struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+}
+
+func newLifetime() *lifetime {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifetime{ctx: ctx, cancel: cancel}
+}
+
+// spawn runs fn in a goroutine that shutdown waits for. fn must return once
+// l.ctx (or a context derived from it) is done.
+func (l *lifetime) spawn(fn func()) {
+	l.workers.Go(fn)
+}
+
+// shutdown cancels the context and waits for spawned goroutines. It reports
+// false if some were still running after shutdownTimeout.
+func (l *lifetime) shutdown() bool {
+	l.cancel()
+	done := make(chan struct{})
+	go func() {
+		l.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(shutdownTimeout):
+		return false
+	}
+}
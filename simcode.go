@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// simCodePrefix starts every simulation code and carries its version.
+const simCodePrefix = "LN1-"
+
+// maxSimCode bounds a decoded code, which is a few hundred bytes at most.
+const maxSimCode = 1 << 16
+
+var errNotSimCode = errors.New("not a simulation code")
+
+// simCode is a run in a line of text: the seed and every parameter the
+// generations depend on, so another instance replays the same run. What
+// the user does during a run (painting, supernovas, loaded grids) is not
+// part of it. Short keys, left out at their defaults, keep it short.
+type simCode struct {
+	Seed           int64        `json:"s"`
+	GrowthRate     float64      `json:"g"`
+	MutationChance float64      `json:"m"`
+	CellSize       int          `json:"c"`
+	GridCells      int          `json:"gs,omitempty"` // one of largeGridSizes, 0 fits the display
+	Speed          int          `json:"v"`
+	PaletteMode    int          `json:"p"`
+	Symmetry       string       `json:"y,omitempty"`
+	Rule           string       `json:"r,omitempty"`
+	RuleParams     []float64    `json:"rp,omitempty"`
+	Temperature    float64      `json:"t,omitempty"`
+	Survival       []float64    `json:"as,omitempty"`
+	Fertility      []float64    `json:"af,omitempty"`
+	BirthCurve     []float64    `json:"b,omitempty"`
+	Metabolism     bool         `json:"e,omitempty"`
+	Nutrients      bool         `json:"n,omitempty"`
+	SeasonPeriod   int          `json:"sp,omitempty"`
+	DriftDirection int          `json:"dd,omitempty"`
+	DriftStrength  float64      `json:"ds,omitempty"`
+	RuleDrift      bool         `json:"rd,omitempty"`
+	ZoneLayout     int          `json:"z,omitempty"`
+	Climates       [][3]float64 `json:"zc,omitempty"` // growth, survival, aging
+	Ants           int          `json:"a,omitempty"`
+	MovementRate   float64      `json:"mv,omitempty"`
+	Immigration    float64      `json:"i,omitempty"`
+	EntryEdge      int          `json:"ie,omitempty"`
+	GrowthCurve    string       `json:"gc,omitempty"`
+	MutationCurve  string       `json:"mc,omitempty"`
+	InfectionRate  float64      `json:"ir,omitempty"` // 0 for the default
+	InfectionSpan  int          `json:"is,omitempty"` // 0 for the default
+	Radiation      bool         `json:"ra,omitempty"`
+	NovaRadius     int          `json:"nr,omitempty"` // 0 for the default
+	Catastrophe    string       `json:"k,omitempty"`  // scheduled disaster, "" for none
+	CatastropheMin int          `json:"k1,omitempty"` // generations between two,
+	CatastropheMax int          `json:"k2,omitempty"` // drawn from min to max
+}
+
+// captureSimCode copies the parameters of state and the ants of sim for a
+// run of seed. The caller holds state.mu.
+func captureSimCode(state *SimulationState, sim *Simulation, seed int64) simCode {
+	code := simCode{
+		Seed:           seed,
+		Ants:           len(sim.ants),
+		GrowthRate:     state.growthRate,
+		MutationChance: state.mutationChance,
+		CellSize:       state.cellSize,
+		GridCells:      state.gridCells,
+		Speed:          state.speed,
+		PaletteMode:    state.paletteMode,
+		Rule:           state.ruleFamily,
+		RuleParams:     slices.Clone(state.ruleParams[state.ruleFamily]),
+		Temperature:    state.temperature,
+		Metabolism:     state.metabolism,
+		Nutrients:      state.nutrients,
+		SeasonPeriod:   state.seasonPeriod,
+		DriftDirection: state.driftDirection,
+		DriftStrength:  state.driftStrength,
+		RuleDrift:      state.ruleDrift,
+		ZoneLayout:     state.zoneLayout,
+		MovementRate:   state.movementRate,
+		Immigration:    state.immigration,
+		EntryEdge:      state.entryEdge,
+		InfectionRate:  state.infectionRate,
+		InfectionSpan:  state.infectionSpan,
+		Radiation:      state.radiation,
+		NovaRadius:     state.novaRadius,
+	}
+	if c := state.catastrophes; c.enabled {
+		code.Catastrophe, code.CatastropheMin, code.CatastropheMax = c.kind, c.minGap, c.maxGap
+	}
+	if state.symmetry != SymmetryNone {
+		code.Symmetry = state.symmetry.String()
+	}
+	if state.ageCurves != defaultAgeCurves {
+		code.Survival = slices.Clone(state.ageCurves.survival[:])
+		code.Fertility = slices.Clone(state.ageCurves.fertility[:])
+	}
+	if state.birthCurve != defaultBirthCurve {
+		code.BirthCurve = slices.Clone(state.birthCurve[:])
+	}
+	if state.climates != defaultClimates {
+		for _, c := range state.climates {
+			code.Climates = append(code.Climates, [3]float64{c.growth, c.thresholds.survival, c.thresholds.aging})
+		}
+	}
+	if state.automation.enabled {
+		code.GrowthCurve = state.automation.growth.String()
+		code.MutationCurve = state.automation.mutation.String()
+	}
+	return code
+}
+
+// String encodes the code as its prefix and the deflated JSON in URL-safe
+// base64, so it survives chat clients and issue trackers.
+func (c simCode) String() string {
+	data, _ := json.Marshal(c)
+	var buf bytes.Buffer
+	zw, _ := flate.NewWriter(&buf, flate.BestCompression)
+	zw.Write(data)
+	zw.Close()
+	return simCodePrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
+
+// parseSimCode decodes a code from String and clamps its values to the
+// ranges the controls accept.
+func parseSimCode(text string) (simCode, error) {
+	text = strings.Join(strings.Fields(text), "")
+	payload, ok := strings.CutPrefix(text, simCodePrefix)
+	if !ok {
+		return simCode{}, errNotSimCode
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return simCode{}, fmt.Errorf("%w: %v", errNotSimCode, err)
+	}
+	data, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(raw)), maxSimCode))
+	if err != nil {
+		return simCode{}, fmt.Errorf("%w: %v", errNotSimCode, err)
+	}
+	var c simCode
+	if err := json.Unmarshal(data, &c); err != nil {
+		return simCode{}, fmt.Errorf("%w: %v", errNotSimCode, err)
+	}
+	if c.Rule == "" {
+		c.Rule = ruleFamilyNames()[0]
+	}
+	if !slices.Contains(ruleFamilyNames(), c.Rule) {
+		return simCode{}, fmt.Errorf("unknown rule family %q", c.Rule)
+	}
+	if !slices.Contains(largeGridSizes, c.GridCells) {
+		return simCode{}, fmt.Errorf("unsupported grid size %d", c.GridCells)
+	}
+	if c.Symmetry == "" {
+		c.Symmetry = SymmetryNone.String()
+	}
+	params := ruleFamilyParams(c.Rule)
+	if len(c.RuleParams) != len(params) {
+		c.RuleParams = nil
+	}
+	for i := range c.RuleParams {
+		c.RuleParams[i] = clampFloat(c.RuleParams[i], params[i].min, params[i].max)
+	}
+	c.GrowthRate = clampFloat(c.GrowthRate, 0.05, 0.5)
+	c.MutationChance = clampFloat(c.MutationChance, 0, 0.1)
+	c.CellSize = int(clampFloat(float64(c.CellSize), 2, 8))
+	c.Speed = int(clampFloat(float64(c.Speed), 10, 200))
+	c.Temperature = clampFloat(c.Temperature, 0, 5)
+	c.SeasonPeriod = int(clampFloat(float64(c.SeasonPeriod), 0, 2000))
+	c.DriftStrength = clampFloat(c.DriftStrength, 0, 1)
+	c.MovementRate = clampFloat(c.MovementRate, 0, 1)
+	c.Immigration = clampFloat(c.Immigration, 0, 0.2)
+	c.Ants = int(clampFloat(float64(c.Ants), 0, maxAnts))
+	if c.InfectionRate == 0 {
+		c.InfectionRate = defaultInfectionRate
+	}
+	if c.InfectionSpan == 0 {
+		c.InfectionSpan = defaultInfectionSpan
+	}
+	if c.NovaRadius == 0 {
+		c.NovaRadius = defaultNovaRadius
+	}
+	c.InfectionRate = clampFloat(c.InfectionRate, 0.05, 1)
+	c.InfectionSpan = clampInt(c.InfectionSpan, 1, 50)
+	c.NovaRadius = clampInt(c.NovaRadius, 3, 40)
+	if c.Catastrophe != "" && !slices.Contains(catastropheKinds, c.Catastrophe) {
+		c.Catastrophe = ""
+	}
+	c.CatastropheMin = clampInt(c.CatastropheMin, 1, 100000)
+	c.CatastropheMax = clampInt(c.CatastropheMax, c.CatastropheMin, 100000)
+	if c.PaletteMode < 0 || c.PaletteMode >= len(paletteModeNames) {
+		c.PaletteMode = 3
+	}
+	if c.DriftDirection < 0 || c.DriftDirection >= len(driftDirections) {
+		c.DriftDirection = 0
+	}
+	if c.ZoneLayout < 0 || c.ZoneLayout >= len(zoneLayouts) {
+		c.ZoneLayout = 0
+	}
+	if c.EntryEdge < 0 || c.EntryEdge >= len(immigrationEdges) {
+		c.EntryEdge = 0
+	}
+	if (c.Survival != nil && len(c.Survival) != 3) || (c.Fertility != nil && len(c.Fertility) != 3) {
+		c.Survival, c.Fertility = nil, nil
+	}
+	if c.BirthCurve != nil && len(c.BirthCurve) != len(defaultBirthCurve) {
+		c.BirthCurve = nil
+	}
+	if c.Climates != nil && len(c.Climates) != len(defaultClimates) {
+		c.Climates = nil
+	}
+	return c, nil
+}
+
+// applyHidden sets the parameters of c that have no control in the main
+// window. The caller holds state.mu.
+func (c simCode) applyHidden(state *SimulationState) {
+	if c.RuleParams != nil {
+		state.ruleParams[c.Rule] = slices.Clone(c.RuleParams)
+	}
+	state.ageCurves = defaultAgeCurves
+	if c.Survival != nil {
+		copy(state.ageCurves.survival[:], c.Survival)
+		copy(state.ageCurves.fertility[:], c.Fertility)
+	}
+	state.birthCurve = defaultBirthCurve
+	if c.BirthCurve != nil {
+		copy(state.birthCurve[:], c.BirthCurve)
+	}
+	state.climates = defaultClimates
+	for i, z := range c.Climates {
+		state.climates[i] = climate{growth: z[0], thresholds: thresholds{survival: z[1], aging: z[2]}}
+	}
+	state.zoneLayout = c.ZoneLayout
+	state.catastrophes = Catastrophes{kind: catastropheKinds[0]}
+	if c.Catastrophe != "" {
+		state.catastrophes = Catastrophes{enabled: true, kind: c.Catastrophe, minGap: c.CatastropheMin, maxGap: c.CatastropheMax}
+	}
+	state.automation = Automation{}
+	if c.GrowthCurve != "" || c.MutationCurve != "" {
+		growth, err1 := parseKeyframes(c.GrowthCurve)
+		mutation, err2 := parseKeyframes(c.MutationCurve)
+		if err1 == nil && err2 == nil {
+			state.automation = Automation{enabled: true, growth: growth, mutation: mutation}
+		}
+	}
+}
+
+// showSimCodeDialog shows code, already copied to the clipboard.
+func showSimCodeDialog(w fyne.Window, code string) {
+	entry := widget.NewMultiLineEntry()
+	entry.Wrapping = fyne.TextWrapBreak
+	entry.SetText(code)
+	content := container.NewBorder(widget.NewLabel(lang.L("Copied to the clipboard. Paste it into another instance to replay this run.")), nil, nil, nil, entry)
+	d := dialog.NewCustom(lang.L("🔗 Simulation code"), lang.L("Close"), content, w)
+	d.Resize(fyne.NewSize(460, 240))
+	d.Show()
+}
+
+// showPasteSimCodeDialog asks for a code, filled in from the clipboard when
+// it holds one, and passes it on decoded.
+func showPasteSimCodeDialog(w fyne.Window, clipboard string, onLoad func(simCode)) {
+	entry := widget.NewMultiLineEntry()
+	entry.Wrapping = fyne.TextWrapBreak
+	entry.SetPlaceHolder(simCodePrefix + "…")
+	if strings.HasPrefix(strings.TrimSpace(clipboard), simCodePrefix) {
+		entry.SetText(strings.TrimSpace(clipboard))
+	}
+	d := dialog.NewCustomConfirm(lang.L("🔗 Load simulation code"), lang.L("Load"), lang.L("Cancel"), entry, func(ok bool) {
+		if !ok {
+			return
+		}
+		code, err := parseSimCode(entry.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		onLoad(code)
+	}, w)
+	d.Resize(fyne.NewSize(460, 240))
+	d.Show()
+}
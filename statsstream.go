@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// statsOutput is the stream given with -stats-out, nil without one.
+var statsOutput *statsStream
+
+// statsStream writes one JSON object per generation, JSON Lines style, with
+// the statistics of the generation and the events logged since the last
+// one, so dashboards and scripts can follow a run.
+type statsStream struct {
+	mu      sync.Mutex
+	out     io.WriteCloser
+	enc     *json.Encoder
+	pending []Event
+	err     error // the first write error, which ends the stream
+}
+
+// statsRecord is the JSON form of one generation.
+type statsRecord struct {
+	Generation    int           `json:"generation"`
+	Population    int           `json:"population"`
+	Density       float64       `json:"density"`
+	AvgAge        float64       `json:"avg_age"`
+	Entropy       float64       `json:"entropy"`
+	Births        int           `json:"births"`
+	Rebirths      int           `json:"rebirths"`
+	Colonies      int           `json:"colonies"`
+	LargestColony int           `json:"largest_colony"`
+	AgeHistogram  [50]int       `json:"age_histogram"`
+	AvgEnergy     float64       `json:"avg_energy"`
+	Starved       int           `json:"starved"`
+	Prey          int           `json:"prey"`
+	Predators     int           `json:"predators"`
+	Infected      int           `json:"infected"`
+	DiseaseDeaths int           `json:"disease_deaths"`
+	Events        []eventRecord `json:"events"`
+}
+
+type eventRecord struct {
+	Generation int    `json:"generation"`
+	Type       string `json:"type"`
+	Message    string `json:"message"`
+}
+
+// openStatsStream writes to path, or to stdout for "-". An existing file is
+// replaced.
+func openStatsStream(path string) (*statsStream, error) {
+	var out io.WriteCloser = nopCloser{os.Stdout}
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		out = f
+	}
+	return &statsStream{out: out, enc: json.NewEncoder(out)}, nil
+}
+
+// note queues an event for the next record. A nil stream ignores it.
+func (s *statsStream) note(e Event) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, e)
+}
+
+// write emits the record of a generation. A nil stream ignores it.
+func (s *statsStream) write(stats Stats) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	record := statsRecord{
+		Generation:    stats.generation,
+		Population:    stats.population,
+		Density:       stats.density,
+		AvgAge:        stats.avgAge,
+		Entropy:       stats.entropy,
+		Births:        stats.births,
+		Rebirths:      stats.rebirths,
+		Colonies:      stats.colonies,
+		LargestColony: stats.largestColony,
+		AgeHistogram:  stats.ageHistogram,
+		AvgEnergy:     stats.avgEnergy,
+		Starved:       stats.starved,
+		Prey:          stats.prey,
+		Predators:     stats.predators,
+		Infected:      stats.infected,
+		DiseaseDeaths: stats.diseaseDeaths,
+		Events:        make([]eventRecord, len(s.pending)),
+	}
+	for i, e := range s.pending {
+		record.Events[i] = eventRecord{Generation: e.generation, Type: e.eventType, Message: e.message}
+	}
+	s.pending = s.pending[:0]
+	if s.err = s.enc.Encode(record); s.err != nil {
+		slog.Warn("stats stream stopped", "err", s.err)
+	}
+}
+
+func (s *statsStream) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.out.Close()
+}
@@ -0,0 +1,48 @@
+package main
+
+// summedArea is the summed-area table of a grid: sums[y*(n+1)+x] is the
+// sum of the cells above and to the left of (x, y), so the sum over any
+// rectangle, and so any neighbourhood whatever its radius, takes four
+// lookups instead of one per cell.
+type summedArea struct {
+	n    int
+	sums []int32
+}
+
+// build computes the table of g, reusing the storage of the last one.
+func (t *summedArea) build(g *Grid) {
+	n := g.size
+	if t.n != n || t.sums == nil {
+		t.n = n
+		t.sums = make([]int32, (n+1)*(n+1))
+	}
+	w := n + 1
+	for y := range n {
+		above := t.sums[y*w : y*w+w]
+		cur := t.sums[(y+1)*w : (y+1)*w+w]
+		rowSum := int32(0)
+		for x, c := range g.row(y) {
+			rowSum += int32(c)
+			cur[x+1] = above[x+1] + rowSum
+		}
+	}
+}
+
+// rect returns the sum of the cells in [x0, x1) × [y0, y1), clipped to the
+// grid.
+func (t *summedArea) rect(x0, y0, x1, y1 int) int {
+	x0, y0 = max(x0, 0), max(y0, 0)
+	x1, y1 = min(x1, t.n), min(y1, t.n)
+	if x0 >= x1 || y0 >= y1 {
+		return 0
+	}
+	w := t.n + 1
+	return int(t.sums[y1*w+x1] - t.sums[y0*w+x1] - t.sums[y1*w+x0] + t.sums[y0*w+x0])
+}
+
+// around returns the sum of the cells within r cells of (x, y), the cell
+// itself excluded, val being its value. Cells past the edges count as empty,
+// as in neighbors, which it matches for r = 1.
+func (t *summedArea) around(x, y, r, val int) int {
+	return t.rect(x-r, y-r, x+r+1, y+r+1) - val
+}
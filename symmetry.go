@@ -0,0 +1,90 @@
+package main
+
+import "image"
+
+// Symmetry constrains the grid to stay invariant under a group of mirrors or
+// rotations, producing mandala-like evolutions.
+type Symmetry int
+
+const (
+	SymmetryNone Symmetry = iota
+	SymmetryMirrorX
+	SymmetryMirrorY
+	SymmetryMirror4
+	SymmetryRotate4
+	SymmetryKaleidoscope
+)
+
+var symmetryNames = []string{"No symmetry", "Mirror ↔", "Mirror ↕", "4-fold mirror", "4-fold rotation", "8-fold kaleidoscope"}
+
+func (s Symmetry) String() string {
+	if int(s) < len(symmetryNames) {
+		return symmetryNames[s]
+	}
+	return symmetryNames[0]
+}
+
+func symmetryFromName(name string) Symmetry {
+	for i, n := range symmetryNames {
+		if n == name {
+			return Symmetry(i)
+		}
+	}
+	return SymmetryNone
+}
+
+// orbit appends to dst every cell that (x,y) maps to under the symmetry of
+// an n×n grid, including (x,y) itself. Duplicates are possible on axes.
+func (s Symmetry) orbit(dst []image.Point, x, y, n int) []image.Point {
+	m := n - 1
+	dst = append(dst, image.Pt(x, y))
+	switch s {
+	case SymmetryMirrorX:
+		dst = append(dst, image.Pt(m-x, y))
+	case SymmetryMirrorY:
+		dst = append(dst, image.Pt(x, m-y))
+	case SymmetryMirror4:
+		dst = append(dst, image.Pt(m-x, y), image.Pt(x, m-y), image.Pt(m-x, m-y))
+	case SymmetryRotate4:
+		dst = append(dst, image.Pt(m-y, x), image.Pt(m-x, m-y), image.Pt(y, m-x))
+	case SymmetryKaleidoscope:
+		dst = append(dst, image.Pt(m-x, y), image.Pt(x, m-y), image.Pt(m-x, m-y),
+			image.Pt(y, x), image.Pt(m-y, x), image.Pt(y, m-x), image.Pt(m-y, m-x))
+	}
+	return dst
+}
+
+// symmetrize copies the value of each orbit's representative (its first
+// cell in row-major order) onto the rest of the orbit. Because the rules
+// are local and isotropic, only the random parts of a generation can break
+// symmetry, so this keeps the whole evolution on the symmetric orbit. The
+// changes are recorded in t unless it is nil.
+func symmetrize(grid *Grid, s Symmetry, t *cellTally) {
+	if s == SymmetryNone {
+		return
+	}
+	n := grid.size
+	var pts []image.Point
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			pts = s.orbit(pts[:0], x, y, n)
+			rep := true
+			for _, p := range pts[1:] {
+				if p.Y < y || (p.Y == y && p.X < x) {
+					rep = false
+					break
+				}
+			}
+			if !rep {
+				continue
+			}
+			val := grid.at(x, y)
+			for _, p := range pts[1:] {
+				if t != nil {
+					t.change(grid.at(p.X, p.Y), val)
+				}
+				grid.set(p.X, p.Y, val)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import "math"
+
+// cellTally is the population, total age and age histogram of a grid. The
+// built-in rules keep the tally of sim.grid up to date as cells change,
+// so a generation costs no extra pass over the grid; count rescans it
+// when a grid is replaced wholesale.
+type cellTally struct {
+	population int
+	totalAge   int
+	histogram  [50]int
+}
+
+// count recomputes the tally of g from scratch.
+func (t *cellTally) count(g *Grid) {
+	*t = cellTally{}
+	for _, c := range g.cells {
+		t.add(int(c))
+	}
+}
+
+// add counts a cell of age val, dead cells being ignored.
+func (t *cellTally) add(val int) {
+	if val <= 0 {
+		return
+	}
+	t.population++
+	t.totalAge += val
+	t.histogram[min(val, len(t.histogram))-1]++
+}
+
+// remove uncounts a cell of age val.
+func (t *cellTally) remove(val int) {
+	if val <= 0 {
+		return
+	}
+	t.population--
+	t.totalAge -= val
+	t.histogram[min(val, len(t.histogram))-1]--
+}
+
+// change records a cell going from age old to val.
+func (t *cellTally) change(old, val int) {
+	if old != val {
+		t.remove(old)
+		t.add(val)
+	}
+}
+
+// stats derives the statistics of a generation from the tally.
+func (t *cellTally) stats(generation, gridSize int) Stats {
+	s := Stats{
+		generation:   generation,
+		population:   t.population,
+		ageHistogram: t.histogram,
+	}
+	total := float64(gridSize * gridSize)
+	s.density = float64(t.population) / total
+	if t.population > 0 {
+		s.avgAge = float64(t.totalAge) / float64(t.population)
+	}
+	if p := s.density; p > 0 && p < 1 {
+		s.entropy = -p*math.Log2(p) - (1-p)*math.Log2(1-p)
+	}
+	return s
+}
+
+// change stores val as the age of cell i of sim.grid, row-major, keeping the
+// tally up to date.
+func (s *Simulation) change(i, val int) {
+	if s.edit != nil {
+		s.edit.cells.note(i, s.grid.cells[i], uint8(val))
+	}
+	s.tally.change(int(s.grid.cells[i]), val)
+	s.grid.cells[i] = uint8(val)
+}
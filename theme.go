@@ -0,0 +1,175 @@
+package main
+
+import (
+	"image/color"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+var themeModes = []string{"System theme", "Dark theme", "Light theme"}
+
+// accentColors are the primary colors offered next to the theme selector.
+var accentColors = []struct {
+	name  string
+	color color.Color // nil keeps the theme default
+}{
+	{"Default accent", nil},
+	{"Green accent", color.RGBA{40, 180, 70, 255}},
+	{"Orange accent", color.RGBA{240, 130, 20, 255}},
+	{"Purple accent", color.RGBA{150, 80, 220, 255}},
+	{"Red accent", color.RGBA{220, 50, 50, 255}},
+}
+
+// appTheme is the default Fyne theme with a forced variant, an optional
+// accent color and larger touch targets on mobile.
+type appTheme struct {
+	mode   string
+	accent color.Color
+	touch  bool // enlarge touch targets
+}
+
+// touchScale enlarges paddings and icons on touch screens so controls are
+// easier to hit with a finger.
+const touchScale = 1.5
+
+func (t *appTheme) variant(v fyne.ThemeVariant) fyne.ThemeVariant {
+	switch t.mode {
+	case "Dark theme":
+		return theme.VariantDark
+	case "Light theme":
+		return theme.VariantLight
+	}
+	return v
+}
+
+func (t *appTheme) Color(n fyne.ThemeColorName, v fyne.ThemeVariant) color.Color {
+	if n == theme.ColorNamePrimary && t.accent != nil {
+		return t.accent
+	}
+	return theme.DefaultTheme().Color(n, t.variant(v))
+}
+
+func (t *appTheme) Font(s fyne.TextStyle) fyne.Resource {
+	return theme.DefaultTheme().Font(s)
+}
+
+func (t *appTheme) Icon(n fyne.ThemeIconName) fyne.Resource {
+	return theme.DefaultTheme().Icon(n)
+}
+
+func (t *appTheme) Size(n fyne.ThemeSizeName) float32 {
+	size := theme.DefaultTheme().Size(n)
+	if t.touch {
+		switch n {
+		case theme.SizeNamePadding, theme.SizeNameInnerPadding, theme.SizeNameInlineIcon:
+			return size * touchScale
+		}
+	}
+	return size
+}
+
+// The simulation canvas follows the theme: empty cells use the theme
+// background, unless the user picked a dead color, and palettes are darkened
+// on light backgrounds. Palettes are generated from several goroutines,
+// hence the lock.
+var (
+	canvasThemeMu    sync.RWMutex
+	canvasBackground color.RGBA = color.RGBA{0, 0, 0, 255}
+	lightCanvas      bool
+	deadColor        *color.RGBA // nil follows the theme
+)
+
+func canvasTheme() (background color.RGBA, light bool) {
+	canvasThemeMu.RLock()
+	defer canvasThemeMu.RUnlock()
+	return canvasBackground, lightCanvas
+}
+
+// setDeadColor sets the color of empty cells, nil following the theme.
+func setDeadColor(c *color.RGBA) {
+	canvasThemeMu.Lock()
+	deadColor = c
+	canvasThemeMu.Unlock()
+}
+
+// currentDeadColor returns the color set with setDeadColor.
+func currentDeadColor() *color.RGBA {
+	canvasThemeMu.RLock()
+	defer canvasThemeMu.RUnlock()
+	return deadColor
+}
+
+// cellBackground is the color of empty cells and whether it is light.
+func cellBackground() (dead color.RGBA, light bool) {
+	canvasThemeMu.RLock()
+	defer canvasThemeMu.RUnlock()
+	if deadColor == nil {
+		return canvasBackground, lightCanvas
+	}
+	c := *deadColor
+	// A transparent background shows the theme behind it
+	if c.A == 0 {
+		return c, lightCanvas
+	}
+	return c, (299*int(c.R)+587*int(c.G)+114*int(c.B))/1000 > 128
+}
+
+// syncCanvasTheme updates the canvas colors from the app's effective theme.
+func syncCanvasTheme(a fyne.App, t *appTheme) {
+	variant := t.variant(a.Settings().ThemeVariant())
+	r, g, b, _ := t.Color(theme.ColorNameBackground, variant).RGBA()
+	canvasThemeMu.Lock()
+	canvasBackground = color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 255}
+	lightCanvas = variant == theme.VariantLight
+	canvasThemeMu.Unlock()
+}
+
+// maxLightLuma caps cell brightness on light backgrounds so yellows and
+// pale blues keep enough contrast against white.
+const maxLightLuma = 140
+
+func forLightBackground(c color.Color) color.Color {
+	r, g, b, _ := c.RGBA()
+	rgba := color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 255}
+	luma := (299*int(rgba.R) + 587*int(rgba.G) + 114*int(rgba.B)) / 1000
+	if luma <= maxLightLuma {
+		return rgba
+	}
+	scale := func(v uint8) uint8 { return uint8(int(v) * maxLightLuma / luma) }
+	return color.RGBA{scale(rgba.R), scale(rgba.G), scale(rgba.B), 255}
+}
+
+// adaptToCanvas applies the current canvas theme to a freshly generated
+// palette.
+func (p *ColorPalette) adaptToCanvas() {
+	background, light := cellBackground()
+	p.dead = background
+	if !light {
+		return
+	}
+	for i := range p.young {
+		p.young[i] = forLightBackground(p.young[i])
+	}
+	for i := range p.mature {
+		p.mature[i] = forLightBackground(p.mature[i])
+	}
+	for i := range p.old {
+		p.old[i] = forLightBackground(p.old[i])
+	}
+	// Bloom only brightens, which washes cells out on a light background
+	p.bloomIntensity *= 0.4
+}
+
+// gridLineColor is a subtle line color just off the canvas background.
+func gridLineColor() color.RGBA {
+	background, light := canvasTheme()
+	shift := func(v uint8) uint8 {
+		if light {
+			return v - v/8
+		}
+		return v + (255-v)/6
+	}
+	return color.RGBA{shift(background.R), shift(background.G), shift(background.B), 255}
+}
@@ -0,0 +1,44 @@
+package main
+
+import "fmt"
+
+// thresholds are the neighbour sums at which the built-in rules switch: a
+// cell survives above survival and ages above aging.
+type thresholds struct {
+	survival float64
+	aging    float64
+}
+
+// defaultThresholds are the classic living numbers rules: survival from a
+// sum of 3, ageing from 21.
+var defaultThresholds = thresholds{survival: 2.5, aging: 20.5}
+
+// Rule evolution settings: each generation, both thresholds take a random
+// step of up to thresholdStep either way, within these bounds.
+const (
+	thresholdStep = 0.05
+	minSurvival   = 0.5
+	maxSurvival   = 8.5
+	minAging      = 8.5
+	maxAging      = 40.5
+)
+
+// setRuleDrift turns rule evolution on or off, turning it off restoring the
+// default thresholds.
+func (s *Simulation) setRuleDrift(on bool) {
+	s.ruleDrift = on
+	if !on {
+		s.thresholds = defaultThresholds
+	}
+}
+
+// mutate moves the thresholds one random step.
+func (t *thresholds) mutate(rng Rand) {
+	step := func() float64 { return (2*rng.Float64() - 1) * thresholdStep }
+	t.survival = min(max(t.survival+step(), minSurvival), maxSurvival)
+	t.aging = min(max(t.aging+step(), minAging), maxAging)
+}
+
+func (t thresholds) String() string {
+	return fmt.Sprintf("survival > %.2f, ageing > %.2f", t.survival, t.aging)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Contact sheet layout: thumbnails are thumbSize pixels wide, and at most
+// maxSheetFrames are kept, evenly spread over the whole capture.
+const (
+	thumbSize      = 160
+	maxSheetFrames = 100
+)
+
+// timelapse saves the displayed frame every few generations as a numbered
+// PNG in a folder, so long runs can be summarized, and can gather them into
+// a contact sheet when it stops.
+type timelapse struct {
+	mu     sync.Mutex // save runs on the simulation goroutine, finish on the main thread
+	dir    string
+	every  int
+	sheet  bool
+	thumbs []*image.RGBA // for the contact sheet
+	stride int           // captures between two kept thumbnails
+	shots  int
+}
+
+func newTimelapse(dir string, every int, sheet bool) (*timelapse, error) {
+	if every < 1 {
+		return nil, fmt.Errorf("capture interval must be at least 1, got %d", every)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &timelapse{dir: dir, every: every, sheet: sheet, stride: 1}, nil
+}
+
+// due reports whether generation is to be captured. A nil timelapse
+// captures nothing.
+func (t *timelapse) due(generation int) bool {
+	return t != nil && generation%t.every == 0
+}
+
+// save writes frame as gen-NNNNNN.png. Once the contact sheet holds
+// maxSheetFrames, every other thumbnail is dropped and the stride doubles.
+func (t *timelapse) save(frame *image.RGBA, generation int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	f, err := os.Create(filepath.Join(t.dir, fmt.Sprintf("gen-%06d.png", generation)))
+	if err != nil {
+		return err
+	}
+	err = png.Encode(f, frame)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil || !t.sheet {
+		return err
+	}
+	if t.shots%t.stride == 0 {
+		t.thumbs = append(t.thumbs, thumbnail(frame, thumbSize))
+		if len(t.thumbs) == maxSheetFrames {
+			for i := range maxSheetFrames / 2 {
+				t.thumbs[i] = t.thumbs[2*i]
+			}
+			t.thumbs = t.thumbs[:maxSheetFrames/2]
+			t.stride *= 2
+		}
+	}
+	t.shots++
+	return nil
+}
+
+// finish writes contact-sheet.png, the kept thumbnails in rows, oldest
+// first. It does nothing without a contact sheet or captures.
+func (t *timelapse) finish() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.sheet || len(t.thumbs) == 0 {
+		return nil
+	}
+	size := t.thumbs[0].Bounds().Size()
+	cols := int(math.Ceil(math.Sqrt(float64(len(t.thumbs)))))
+	rows := (len(t.thumbs) + cols - 1) / cols
+	const gap = 4
+	sheet := image.NewRGBA(image.Rect(0, 0, cols*(size.X+gap)+gap, rows*(size.Y+gap)+gap))
+	for i, thumb := range t.thumbs {
+		at := image.Pt(gap+i%cols*(size.X+gap), gap+i/cols*(size.Y+gap))
+		draw.Draw(sheet, thumb.Bounds().Add(at), thumb, image.Point{}, draw.Src)
+	}
+	f, err := os.Create(filepath.Join(t.dir, "contact-sheet.png"))
+	if err != nil {
+		return err
+	}
+	err = png.Encode(f, sheet)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// showTimelapseDialog asks for the folder, the interval and whether to make
+// a contact sheet, then starts the capture.
+func showTimelapseDialog(w fyne.Window, onStart func(t *timelapse)) {
+	folderLabel := widget.NewLabel(lang.L("No folder chosen"))
+	var folder string
+	chooseButton := widget.NewButton(lang.L("Choose…"), func() {
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if dir != nil {
+				folder = dir.Path()
+				folderLabel.SetText(folder)
+			}
+		}, w)
+	})
+	everyEntry := widget.NewEntry()
+	everyEntry.SetText("100")
+	sheetCheck := widget.NewCheck(lang.L("Assemble a contact sheet when stopped"), nil)
+	sheetCheck.Checked = true
+
+	form := widget.NewForm(
+		widget.NewFormItem(lang.L("Folder"), container.NewBorder(nil, nil, nil, chooseButton, folderLabel)),
+		widget.NewFormItem(lang.L("Every (generations)"), everyEntry),
+	)
+	content := container.NewVBox(
+		widget.NewLabel(lang.L("Saves the grid as shown as gen-NNNNNN.png every N generations,\nuntil the button is pressed again.")),
+		form,
+		sheetCheck,
+	)
+	d := dialog.NewCustomConfirm(lang.L("⏱ Timelapse"), lang.L("Start"), lang.L("Cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		if folder == "" {
+			dialog.ShowError(errors.New(lang.L("Choose a folder for the frames")), w)
+			return
+		}
+		every, err := strconv.Atoi(strings.TrimSpace(everyEntry.Text))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("capture interval: %w", err), w)
+			return
+		}
+		t, err := newTimelapse(folder, every, sheetCheck.Checked)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		onStart(t)
+	}, w)
+	d.Resize(fyne.NewSize(450, 0))
+	d.Show()
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"image"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/driver/mobile"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxZoom is the largest magnification of the grid view.
+const maxZoom = 8
+
+// gridView shows the grid image with zoom and pan, and reports taps and
+// drags on it as image pixels so cells can be painted, then onStrokeEnd
+// once the tap or drag is over, and ctrl-clicks to onAim. The mouse wheel,
+// double taps and two-finger pinches zoom; once zoomed in, dragging pans. Shift-drags go to onPan, in image pixels, to
+// move over a grid larger than the image. The pixel under the mouse is kept
+// for pastes.
+type gridView struct {
+	widget.BaseWidget
+	image       *canvas.Image
+	onPaint     func(image.Point)
+	onStrokeEnd func()
+	onAim       func(image.Point)
+	onPan       func(dx, dy float32)
+	aiming      bool // the pressed mouse button came with ctrl
+	panning     bool // the pressed mouse button came with shift
+	hover       image.Point
+	hovered     bool // the mouse is over the image, at hover
+
+	zoom   float32
+	center fyne.Position // image point at the middle of the view, in 0-1 units
+
+	// Fyne reports every finger of a pinch as drags of the same widget,
+	// so the two fingers are told apart by position.
+	touches       int
+	fingers       [2]fyne.Position
+	pinchDistance float32
+	pinchZoom     float32
+}
+
+func newGridView(img *canvas.Image, onPaint func(image.Point)) *gridView {
+	v := &gridView{image: img, onPaint: onPaint, zoom: 1, center: fyne.NewPos(0.5, 0.5)}
+	v.ExtendBaseWidget(v)
+	return v
+}
+
+func (v *gridView) CreateRenderer() fyne.WidgetRenderer {
+	return &gridViewRenderer{view: v, clip: container.NewClip(container.NewWithoutLayout(v.image))}
+}
+
+// placement returns the position and side of the image in a view of the
+// given size: the largest square that fits, times the zoom.
+func (v *gridView) placement(size fyne.Size) (fyne.Position, float32) {
+	side := fyne.Min(size.Width, size.Height) * v.zoom
+	return fyne.NewPos(size.Width/2-v.center.X*side, size.Height/2-v.center.Y*side), side
+}
+
+// imagePoint converts a position in the view to a pixel of the image.
+func (v *gridView) imagePoint(pos fyne.Position) (image.Point, bool) {
+	origin, side := v.placement(v.Size())
+	if side <= 0 || v.image.Image == nil {
+		return image.Point{}, false
+	}
+	u := pos.Subtract(origin)
+	ux, uy := u.X/side, u.Y/side
+	if ux < 0 || uy < 0 || ux >= 1 || uy >= 1 {
+		return image.Point{}, false
+	}
+	b := v.image.Image.Bounds()
+	return image.Pt(b.Min.X+int(ux*float32(b.Dx())), b.Min.Y+int(uy*float32(b.Dy()))), true
+}
+
+// clampCenter keeps the view inside the image, or centered on it while the
+// image is smaller than the view.
+func (v *gridView) clampCenter() {
+	size := v.Size()
+	side := fyne.Min(size.Width, size.Height) * v.zoom
+	if side <= 0 {
+		return
+	}
+	clampAxis := func(c, view float32) float32 {
+		half := view / 2 / side
+		if half >= 0.5 {
+			return 0.5
+		}
+		return fyne.Max(half, fyne.Min(c, 1-half))
+	}
+	v.center = fyne.NewPos(clampAxis(v.center.X, size.Width), clampAxis(v.center.Y, size.Height))
+}
+
+// zoomAt changes the zoom, keeping the image point under pos in place.
+func (v *gridView) zoomAt(pos fyne.Position, zoom float32) {
+	zoom = fyne.Max(1, fyne.Min(zoom, maxZoom))
+	origin, side := v.placement(v.Size())
+	if side <= 0 {
+		return
+	}
+	newSide := side / v.zoom * zoom
+	scale := newSide / side
+	newOrigin := fyne.NewPos(pos.X-(pos.X-origin.X)*scale, pos.Y-(pos.Y-origin.Y)*scale)
+	size := v.Size()
+	v.zoom = zoom
+	v.center = fyne.NewPos((size.Width/2-newOrigin.X)/newSide, (size.Height/2-newOrigin.Y)/newSide)
+	v.clampCenter()
+	v.Refresh()
+}
+
+func (v *gridView) pan(d fyne.Delta) {
+	_, side := v.placement(v.Size())
+	if side <= 0 {
+		return
+	}
+	v.center = fyne.NewPos(v.center.X-d.DX/side, v.center.Y-d.DY/side)
+	v.clampCenter()
+	v.Refresh()
+}
+
+func (v *gridView) paint(pos fyne.Position) {
+	if p, ok := v.imagePoint(pos); ok && v.onPaint != nil {
+		v.onPaint(p)
+	}
+}
+
+func (v *gridView) Tapped(ev *fyne.PointEvent) {
+	if p, ok := v.imagePoint(ev.Position); ok && v.aiming && v.onAim != nil {
+		v.onAim(p)
+		return
+	}
+	v.paint(ev.Position)
+	v.endStroke()
+}
+
+func (v *gridView) endStroke() {
+	if v.onStrokeEnd != nil {
+		v.onStrokeEnd()
+	}
+}
+
+func (v *gridView) MouseDown(ev *desktop.MouseEvent) {
+	v.aiming = ev.Modifier&(fyne.KeyModifierControl|fyne.KeyModifierSuper) != 0
+	v.panning = ev.Modifier&fyne.KeyModifierShift != 0
+}
+
+func (v *gridView) MouseUp(*desktop.MouseEvent) {}
+
+func (v *gridView) MouseIn(ev *desktop.MouseEvent) {
+	v.MouseMoved(ev)
+}
+
+func (v *gridView) MouseMoved(ev *desktop.MouseEvent) {
+	v.hover, v.hovered = v.imagePoint(ev.Position)
+}
+
+func (v *gridView) MouseOut() {
+	v.hovered = false
+}
+
+// cursor returns the image pixel under the mouse, if any.
+func (v *gridView) cursor() (image.Point, bool) {
+	return v.hover, v.hovered
+}
+
+// DoubleTapped zooms in on the tapped point, or back out when zoomed.
+func (v *gridView) DoubleTapped(ev *fyne.PointEvent) {
+	if v.zoom > 1 {
+		v.zoomAt(ev.Position, 1)
+	} else {
+		v.zoomAt(ev.Position, 2)
+	}
+}
+
+func (v *gridView) Scrolled(ev *fyne.ScrollEvent) {
+	v.zoomAt(ev.Position, v.zoom*float32(math.Pow(1.02, float64(ev.Scrolled.DY))))
+}
+
+func (v *gridView) Dragged(ev *fyne.DragEvent) {
+	switch {
+	case v.touches >= 2:
+		v.pinch(ev.Position)
+	case v.panning && v.onPan != nil:
+		_, side := v.placement(v.Size())
+		if side > 0 && v.image.Image != nil {
+			scale := float32(v.image.Image.Bounds().Dx()) / side
+			v.onPan(ev.Dragged.DX*scale, ev.Dragged.DY*scale)
+		}
+	case v.zoom > 1:
+		v.pan(ev.Dragged)
+	default:
+		v.paint(ev.Position)
+	}
+}
+
+func (v *gridView) DragEnd() {
+	v.touches = 0
+	v.endStroke()
+}
+
+func (v *gridView) TouchDown(ev *mobile.TouchEvent) {
+	if v.touches < 2 {
+		v.fingers[v.touches] = ev.Position
+	}
+	v.touches++
+	if v.touches == 2 {
+		v.pinchDistance = distance(v.fingers[0], v.fingers[1])
+		v.pinchZoom = v.zoom
+	}
+}
+
+func (v *gridView) TouchUp(*mobile.TouchEvent) {
+	v.touches = max(v.touches-1, 0)
+}
+
+func (v *gridView) TouchCancel(*mobile.TouchEvent) {
+	v.touches = max(v.touches-1, 0)
+}
+
+// pinch moves the finger nearest to pos and zooms by the change of the
+// distance between the two fingers, around their midpoint.
+func (v *gridView) pinch(pos fyne.Position) {
+	i := 0
+	if distance(pos, v.fingers[1]) < distance(pos, v.fingers[0]) {
+		i = 1
+	}
+	v.fingers[i] = pos
+	if v.pinchDistance <= 0 {
+		return
+	}
+	mid := fyne.NewPos((v.fingers[0].X+v.fingers[1].X)/2, (v.fingers[0].Y+v.fingers[1].Y)/2)
+	v.zoomAt(mid, v.pinchZoom*distance(v.fingers[0], v.fingers[1])/v.pinchDistance)
+}
+
+func distance(a, b fyne.Position) float32 {
+	return float32(math.Hypot(float64(a.X-b.X), float64(a.Y-b.Y)))
+}
+
+// gridViewRenderer clips the zoomed image to the view.
+type gridViewRenderer struct {
+	view *gridView
+	clip *container.Clip
+}
+
+func (r *gridViewRenderer) Layout(size fyne.Size) {
+	r.clip.Resize(size)
+	origin, side := r.view.placement(size)
+	r.view.image.Move(origin)
+	r.view.image.Resize(fyne.NewSquareSize(side))
+}
+
+func (r *gridViewRenderer) MinSize() fyne.Size {
+	return r.view.image.MinSize()
+}
+
+func (r *gridViewRenderer) Refresh() {
+	r.view.clampCenter()
+	r.Layout(r.view.Size())
+	canvas.Refresh(r.view.image)
+}
+
+func (r *gridViewRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.clip}
+}
+
+func (r *gridViewRenderer) Destroy() {}
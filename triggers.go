@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+var triggerMetrics = []string{"population", "density %", "avg age", "entropy", "colonies", "rebirths", "generation"}
+
+var triggerOps = []string{"<", "<=", ">", ">="}
+
+// Trigger is a user-defined condition on the statistics. It fires once each
+// time the condition becomes true, not on every generation it stays true.
+type Trigger struct {
+	metric    string
+	op        string
+	threshold float64
+	pause     bool
+	wasMet    bool
+}
+
+func (t *Trigger) String() string {
+	s := fmt.Sprintf("%s %s %g", t.metric, t.op, t.threshold)
+	if t.pause {
+		s += " (pause)"
+	}
+	return s
+}
+
+func metricValue(s Stats, metric string) float64 {
+	switch metric {
+	case "population":
+		return float64(s.population)
+	case "density %":
+		return s.density * 100
+	case "avg age":
+		return s.avgAge
+	case "entropy":
+		return s.entropy
+	case "colonies":
+		return float64(s.colonies)
+	case "rebirths":
+		return float64(s.rebirths)
+	default:
+		return float64(s.generation)
+	}
+}
+
+func (t *Trigger) isMet(s Stats) bool {
+	v := metricValue(s, t.metric)
+	switch t.op {
+	case "<":
+		return v < t.threshold
+	case "<=":
+		return v <= t.threshold
+	case ">":
+		return v > t.threshold
+	default:
+		return v >= t.threshold
+	}
+}
+
+// checkTriggers returns the triggers whose condition just became true.
+func checkTriggers(triggers []*Trigger, s Stats) []*Trigger {
+	var fired []*Trigger
+	for _, t := range triggers {
+		met := t.isMet(s)
+		if met && !t.wasMet {
+			fired = append(fired, t)
+		}
+		t.wasMet = met
+	}
+	return fired
+}
+
+// resetTriggers re-arms every trigger, e.g. when a new run starts.
+func resetTriggers(triggers []*Trigger) {
+	for _, t := range triggers {
+		t.wasMet = false
+	}
+}
+
+// showTriggersDialog is the condition builder for state.triggers.
+func showTriggersDialog(w fyne.Window, state *SimulationState) {
+	metricSelect := widget.NewSelect(localized(triggerMetrics), nil)
+	metricSelect.SetSelected(lang.L(triggerMetrics[0]))
+	opSelect := widget.NewSelect(triggerOps, nil)
+	opSelect.SetSelected("<")
+	thresholdEntry := widget.NewEntry()
+	thresholdEntry.SetText("100")
+	pauseCheck := widget.NewCheck(lang.L("Pause when met"), nil)
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int {
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			return len(state.triggers)
+		},
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewButton("✖", nil), widget.NewLabel(lang.L("condition")))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*fyne.Container)
+			state.mu.Lock()
+			text := state.triggers[id].String()
+			state.mu.Unlock()
+			row.Objects[0].(*widget.Label).SetText(text)
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				state.mu.Lock()
+				state.triggers = append(state.triggers[:id], state.triggers[id+1:]...)
+				state.mu.Unlock()
+				list.Refresh()
+			}
+		},
+	)
+
+	addButton := widget.NewButton(lang.L("➕ Add condition"), func() {
+		v, err := strconv.ParseFloat(thresholdEntry.Text, 64)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("invalid threshold %q", thresholdEntry.Text), w)
+			return
+		}
+		state.mu.Lock()
+		state.triggers = append(state.triggers, &Trigger{
+			metric:    unlocalized(triggerMetrics, metricSelect.Selected),
+			op:        opSelect.Selected,
+			threshold: v,
+			pause:     pauseCheck.Checked,
+		})
+		state.mu.Unlock()
+		list.Refresh()
+	})
+
+	builder := container.NewVBox(
+		container.NewGridWithColumns(3, metricSelect, opSelect, thresholdEntry),
+		pauseCheck,
+		addButton,
+		widget.NewSeparator(),
+	)
+	content := container.NewBorder(builder, nil, nil, nil, list)
+
+	d := dialog.NewCustom(lang.L("Event triggers"), lang.L("Close"), content, w)
+	d.Resize(fyne.NewSize(420, 360))
+	d.Show()
+}
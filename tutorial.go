@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+var highlightColor = color.RGBA{255, 200, 0, 255}
+
+// highlight wraps a control so the tutorial can draw a pulsing outline
+// around it.
+type highlight struct {
+	outline *canvas.Rectangle
+	anim    *fyne.Animation
+}
+
+func newHighlight(obj fyne.CanvasObject) (*highlight, fyne.CanvasObject) {
+	h := &highlight{outline: canvas.NewRectangle(color.Transparent)}
+	h.outline.StrokeWidth = 3
+	h.outline.Hide()
+	h.anim = canvas.NewColorRGBAAnimation(highlightColor, color.RGBA{255, 200, 0, 60}, 700*time.Millisecond, func(c color.Color) {
+		h.outline.StrokeColor = c
+		h.outline.Refresh()
+	})
+	h.anim.AutoReverse = true
+	h.anim.RepeatCount = fyne.AnimationRepeatForever
+	return h, container.NewStack(obj, h.outline)
+}
+
+func (h *highlight) on() {
+	h.outline.Show()
+	h.anim.Start()
+}
+
+func (h *highlight) off() {
+	h.anim.Stop()
+	h.outline.Hide()
+}
+
+// tutorialStep waits for the user to perform action on the highlighted
+// control; an empty action is advanced with the Next button.
+type tutorialStep struct {
+	title  string
+	text   string
+	action string
+	target *highlight
+}
+
+// Tutorial is the guided tour shown above the controls. The UI reports
+// user actions through advance, which moves to the next step when the
+// action matches the one the current step is waiting for.
+type Tutorial struct {
+	steps   []tutorialStep
+	current int
+	active  bool
+
+	panel      *fyne.Container
+	titleLabel *widget.Label
+	textLabel  *widget.Label
+	nextButton *widget.Button
+}
+
+func newTutorial(steps []tutorialStep) *Tutorial {
+	t := &Tutorial{steps: steps}
+	t.titleLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	t.textLabel = widget.NewLabel("")
+	t.textLabel.Wrapping = fyne.TextWrapWord
+	t.nextButton = widget.NewButton(lang.L("Next ▶"), func() { t.next() })
+	skipButton := widget.NewButton(lang.L("Skip tutorial"), func() { t.stop() })
+	t.panel = container.NewVBox(
+		t.titleLabel,
+		t.textLabel,
+		container.NewHBox(t.nextButton, skipButton),
+		widget.NewSeparator(),
+	)
+	t.panel.Hide()
+	return t
+}
+
+func (t *Tutorial) start() {
+	t.active = true
+	t.current = 0
+	t.show()
+	t.panel.Show()
+}
+
+func (t *Tutorial) stop() {
+	if t.current < len(t.steps) && t.steps[t.current].target != nil {
+		t.steps[t.current].target.off()
+	}
+	t.active = false
+	t.panel.Hide()
+}
+
+func (t *Tutorial) show() {
+	step := t.steps[t.current]
+	t.titleLabel.SetText(fmt.Sprintf(lang.L("Tutorial %d/%d - %s"), t.current+1, len(t.steps), step.title))
+	t.textLabel.SetText(step.text)
+	if step.action == "" {
+		t.nextButton.Show()
+	} else {
+		t.nextButton.Hide()
+	}
+	if t.current == len(t.steps)-1 {
+		t.nextButton.SetText(lang.L("Finish ✔"))
+	} else {
+		t.nextButton.SetText(lang.L("Next ▶"))
+	}
+	if step.target != nil {
+		step.target.on()
+	}
+}
+
+func (t *Tutorial) next() {
+	if t.steps[t.current].target != nil {
+		t.steps[t.current].target.off()
+	}
+	t.current++
+	if t.current >= len(t.steps) {
+		t.stop()
+		return
+	}
+	t.show()
+}
+
+// advance is called by the controls whenever the user performs action.
+func (t *Tutorial) advance(action string) {
+	if t.active && t.steps[t.current].action == action {
+		t.next()
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"slices"
+
+	"fyne.io/fyne/v2/data/binding"
+)
+
+var errReadOnly = errors.New("read-only binding")
+
+// modelFloat binds a number of the model, so the slider and label showing
+// it, undo and the console all go through the one value in
+// SimulationState. get and set access the field with state.mu held. Like
+// the widgets it drives, it is only used on the UI thread; changes the
+// engine makes reach it through the bus, see changed.
+type modelFloat struct {
+	state     *SimulationState
+	get       func() float64
+	set       func(float64)
+	listeners []binding.DataListener
+	hooks     []func(float64)
+}
+
+func newModelFloat(state *SimulationState, get func() float64, set func(float64)) *modelFloat {
+	return &modelFloat{state: state, get: get, set: set}
+}
+
+func (m *modelFloat) Get() (float64, error) {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+	return m.get(), nil
+}
+
+// Set writes v into the model and notifies the listeners when it changed.
+func (m *modelFloat) Set(v float64) error {
+	m.state.mu.Lock()
+	if m.get() == v {
+		m.state.mu.Unlock()
+		return nil
+	}
+	m.set(v)
+	m.state.mu.Unlock()
+	m.changed()
+	return nil
+}
+
+// AddListener notifies l of the current value, then of every change.
+func (m *modelFloat) AddListener(l binding.DataListener) {
+	m.listeners = append(m.listeners, l)
+	l.DataChanged()
+}
+
+func (m *modelFloat) RemoveListener(l binding.DataListener) {
+	m.listeners = slices.DeleteFunc(m.listeners, func(o binding.DataListener) bool { return o == l })
+}
+
+// onChange runs fn with the new value after each change, for the side
+// effects of a parameter such as logging it.
+func (m *modelFloat) onChange(fn func(float64)) {
+	m.hooks = append(m.hooks, fn)
+}
+
+// changed notifies the listeners that the value changed, also for changes
+// made by the engine directly.
+func (m *modelFloat) changed() {
+	for _, l := range m.listeners {
+		l.DataChanged()
+	}
+	v, _ := m.Get()
+	for _, fn := range m.hooks {
+		fn(v)
+	}
+}
+
+// text shows the value through format, for the label of its slider.
+func (m *modelFloat) text(format func(float64) string) binding.String {
+	return modelText{m, format}
+}
+
+// modelText is a modelFloat as read-only text.
+type modelText struct {
+	*modelFloat
+	format func(float64) string
+}
+
+func (t modelText) Get() (string, error) {
+	v, err := t.modelFloat.Get()
+	return t.format(v), err
+}
+
+func (modelText) Set(string) error { return errReadOnly }
+
+// viewModel is what the window shows of the model: its parameters, bound
+// to their sliders and labels, and the texts of the run's labels, which
+// the bus subscribers set. The controls, undo and the console all change
+// the parameters through it, so they never disagree.
+type viewModel struct {
+	growthRate, mutationChance, temperature, seasonPeriod *modelFloat
+	driftStrength, movementRate, immigration              *modelFloat
+	infectionRate, infectionSpan, speed                   *modelFloat
+	paletteSpeed, novaRadius                              *modelFloat
+
+	status, stats, events, scenario binding.String
+}
+
+func newViewModel(state *SimulationState) *viewModel {
+	number := func(field *float64) *modelFloat {
+		return newModelFloat(state, func() float64 { return *field }, func(v float64) { *field = v })
+	}
+	count := func(field *int) *modelFloat {
+		return newModelFloat(state, func() float64 { return float64(*field) }, func(v float64) { *field = int(v) })
+	}
+	return &viewModel{
+		growthRate:     number(&state.growthRate),
+		mutationChance: number(&state.mutationChance),
+		temperature:    number(&state.temperature),
+		seasonPeriod:   count(&state.seasonPeriod),
+		driftStrength:  number(&state.driftStrength),
+		movementRate:   number(&state.movementRate),
+		immigration:    number(&state.immigration),
+		infectionRate:  number(&state.infectionRate),
+		infectionSpan:  count(&state.infectionSpan),
+		speed:          count(&state.speed),
+		paletteSpeed:   number(&state.paletteSpeed),
+		novaRadius:     count(&state.novaRadius),
+		status:         binding.NewString(),
+		stats:          binding.NewString(),
+		events:         binding.NewString(),
+		scenario:       binding.NewString(),
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestViewModelBindsSliders(t *testing.T) {
+	test.NewTempApp(t)
+	state := &SimulationState{runModel: runModel{speed: 50}}
+	vm := newViewModel(state)
+	var logged []float64
+	vm.speed.onChange(func(v float64) { logged = append(logged, v) })
+	slider := widget.NewSliderWithData(10, 200, vm.speed)
+	label := widget.NewLabelWithData(vm.speed.text(func(v float64) string { return fmt.Sprintf("%dms", int(v)) }))
+	if slider.Value != 50 || label.Text != "50ms" {
+		t.Fatalf("bound to 50: slider %g, label %q", slider.Value, label.Text)
+	}
+
+	slider.SetValue(80)
+	if state.speed != 80 || label.Text != "80ms" {
+		t.Errorf("slider moved to 80: model %d, label %q", state.speed, label.Text)
+	}
+
+	// Changes made by the engine show once announced
+	state.speed = 120
+	vm.speed.changed()
+	if slider.Value != 120 || label.Text != "120ms" {
+		t.Errorf("model changed to 120: slider %g, label %q", slider.Value, label.Text)
+	}
+
+	// Undo tracks the slider, and engine changes made quietly are not recorded
+	commands := newCommandStack()
+	commands.track("speed", slider)
+	commands.quietly(func() {
+		state.speed = 150
+		vm.speed.changed()
+	})
+	slider.SetValue(100)
+	commands.undo()
+	if state.speed != 150 || slider.Value != 150 {
+		t.Errorf("undo: model %d, slider %g, want 150", state.speed, slider.Value)
+	}
+	if _, err := commands.undo(); err == nil {
+		t.Error("a change made by the engine was undone")
+	}
+
+	if want := []float64{80, 120, 150, 100, 150}; fmt.Sprint(logged) != fmt.Sprint(want) {
+		t.Errorf("changes %v, want %v", logged, want)
+	}
+	if err := vm.speed.text(nil).Set("1"); err == nil {
+		t.Error("set a label's text")
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"image"
+)
+
+// largeGridSizes are the grid sides offered by the Grid size selector, in
+// cells; 0 fits the grid to the display at the chosen cell size.
+var largeGridSizes = []int{0, 500, 1000, 2000}
+
+func gridSizeNames() []string {
+	names := make([]string, len(largeGridSizes))
+	for i, n := range largeGridSizes {
+		names[i] = "Fit the display"
+		if n > 0 {
+			names[i] = fmt.Sprintf("%d × %d", n, n)
+		}
+	}
+	return names
+}
+
+// gridSide is the side of the grid for a chosen size of largeGridSizes
+// drawn cellSize pixels a cell.
+func gridSide(chosen, cellSize int) int {
+	if chosen > 0 {
+		return chosen
+	}
+	return displaySize / cellSize
+}
+
+// viewport is the window of the grid drawn on the display when the grid is
+// larger than the display holds: its top-left cell and its side in cells.
+// Only the cells inside it are drawn, however large the grid.
+type viewport struct {
+	x, y, side int
+}
+
+// fit sizes the window for a grid of gridSize cells drawn cellSize pixels a
+// cell, keeping it inside the grid.
+func (v *viewport) fit(gridSize, cellSize int) {
+	v.side = min(displaySize/cellSize, gridSize)
+	v.x = clampInt(v.x, 0, gridSize-v.side)
+	v.y = clampInt(v.y, 0, gridSize-v.side)
+}
+
+// whole reports whether the window shows the whole grid.
+func (v viewport) whole(gridSize int) bool {
+	return v.side >= gridSize
+}
+
+// center moves the window to the middle of the grid.
+func (v *viewport) center(gridSize int) {
+	v.x = (gridSize - v.side) / 2
+	v.y = v.x
+}
+
+// pan moves the window by dx, dy cells, within the grid.
+func (v *viewport) pan(dx, dy, gridSize int) {
+	v.x = clampInt(v.x+dx, 0, gridSize-v.side)
+	v.y = clampInt(v.y+dy, 0, gridSize-v.side)
+}
+
+// cell returns the grid cell under pixel p of the display.
+func (v viewport) cell(p image.Point, cellSize int) (x, y int) {
+	return v.x + p.X/cellSize, v.y + p.Y/cellSize
+}
+
+// drawViewport draws the window of the grid flat, colored by c, by age when
+// unset, with the ants inside it. The other view modes and overlays need
+// the whole grid on the display.
+func drawViewport(img *image.RGBA, sim *Simulation, palette ColorPalette, c Colorizer, cellSize int, v viewport) {
+	if c.colors == nil {
+		c = colorizers[0]
+	}
+	colors := c.colors(sim, palette)
+	drawCells(img, cellSize, v.side, func(x, y int) [4]uint8 {
+		return colors(v.x+x, v.y+y)
+	})
+	var ants []ant
+	for _, a := range sim.ants {
+		if a.x >= v.x && a.x < v.x+v.side && a.y >= v.y && a.y < v.y+v.side {
+			ants = append(ants, ant{x: a.x - v.x, y: a.y - v.y, dir: a.dir})
+		}
+	}
+	drawAnts(img, ants, cellSize)
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// WallpaperConfig describes how the background simulation is rendered to the
+// desktop.
+type WallpaperConfig struct {
+	width          int
+	height         int
+	cellSize       int
+	interval       time.Duration
+	gensPerUpdate  int
+	growthRate     float64
+	mutationChance float64
+	paletteMode    int
+	bloomEffect    bool
+	bloom          BloomSettings
+}
+
+// renderWallpaper draws the simulation at the configured resolution. The
+// grid is square, so cells falling outside the image are simply cropped.
+func renderWallpaper(sim *Simulation, palette ColorPalette, cfg WallpaperConfig) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, cfg.width, cfg.height))
+	drawGridDynamic(sim.grid, img, palette, cfg.cellSize, sim.gridSize)
+	if cfg.bloomEffect {
+		applyBloom(img, palette, cfg.bloom)
+	}
+	return img
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// setWallpaper asks the desktop environment to use the image at path as the
+// background.
+func setWallpaper(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		uri := "file://" + path
+		if _, err := exec.LookPath("gsettings"); err == nil {
+			if err := exec.Command("gsettings", "set", "org.gnome.desktop.background", "picture-uri", uri).Run(); err != nil {
+				return err
+			}
+			cmd = exec.Command("gsettings", "set", "org.gnome.desktop.background", "picture-uri-dark", uri)
+		} else if _, err := exec.LookPath("feh"); err == nil {
+			cmd = exec.Command("feh", "--bg-fill", path)
+		} else {
+			return errors.New("no supported wallpaper helper found (gsettings or feh)")
+		}
+	case "darwin":
+		script := fmt.Sprintf(`tell application "System Events" to tell every desktop to set picture to %q`, path)
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -TypeDefinition 'using System.Runtime.InteropServices; public class W { [DllImport("user32.dll", CharSet=CharSet.Auto)] public static extern int SystemParametersInfo(int a, int b, string c, int d); }'; [W]::SystemParametersInfo(20, 0, '%s', 3)`, strings.ReplaceAll(path, "'", "''"))
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+	default:
+		return fmt.Errorf("setting the wallpaper is not supported on %s", runtime.GOOS)
+	}
+	return cmd.Run()
+}
+
+// wallpaperRunner evolves its own simulation in the background and pushes a
+// new wallpaper after every interval.
+type wallpaperRunner struct {
+	cfg     WallpaperConfig
+	sim     *Simulation
+	rng     *rand.Rand
+	cycle   float64
+	updates int
+	dir     string
+	onFrame func(msg string)
+}
+
+func newWallpaperRunner(cfg WallpaperConfig, seed int64, onFrame func(string)) (*wallpaperRunner, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	dir = filepath.Join(dir, "living-numbers")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	gridSize := cfg.width
+	if cfg.height > gridSize {
+		gridSize = cfg.height
+	}
+	gridSize = (gridSize + cfg.cellSize - 1) / cfg.cellSize
+
+	r := &wallpaperRunner{
+		cfg:     cfg,
+		sim:     newSimulation(gridSize, seed),
+		rng:     rand.New(rand.NewSource(seed)),
+		dir:     dir,
+		onFrame: onFrame,
+	}
+	r.sim.growthRate = cfg.growthRate
+	r.sim.mutationChance = cfg.mutationChance
+	r.sim.reset(seed)
+	return r, nil
+}
+
+func (r *wallpaperRunner) update() {
+	for i := 0; i < r.cfg.gensPerUpdate; i++ {
+		r.sim.step()
+		r.cycle += 0.05
+		if r.sim.isFull() {
+			r.sim.reset(r.rng.Int63())
+		}
+	}
+
+	palette := generateDynamicPalette(r.rng, r.cycle+r.sim.stats.avgAge*0.1, r.cfg.paletteMode)
+	img := renderWallpaper(r.sim, palette, r.cfg)
+
+	// Alternate file names: some desktops ignore a changed image at the same path.
+	path := filepath.Join(r.dir, fmt.Sprintf("wallpaper-%d.png", r.updates%2))
+	r.updates++
+	if err := writePNG(path, img); err != nil {
+		r.onFrame(lang.LocalizeKey("wallpaper.write_failed", "Wallpaper write failed: {{.Err}}", map[string]any{"Err": err}))
+		return
+	}
+	if err := setWallpaper(path); err != nil {
+		r.onFrame(lang.LocalizeKey("wallpaper.update_failed", "Wallpaper update failed: {{.Err}}", map[string]any{"Err": err}))
+		return
+	}
+	r.onFrame(fmt.Sprintf(lang.L("Wallpaper updated at %s - Gen %d, Pop %d"),
+		time.Now().Format("15:04"), r.sim.generation, r.sim.stats.population))
+}
+
+// run updates the wallpaper every interval until ctx is done.
+func (r *wallpaperRunner) run(ctx context.Context) {
+	r.update()
+	ticker := time.NewTicker(r.cfg.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.update()
+		}
+	}
+}
+
+// openWallpaperWindow shows the wallpaper mode settings and starts or stops
+// the background runner.
+func openWallpaperWindow(a fyne.App, life *lifetime, seed int64, state *SimulationState) {
+	w := a.NewWindow(lang.L("Living Numbers Game - Wallpaper Mode"))
+
+	widthEntry := widget.NewEntry()
+	widthEntry.SetText("1920")
+	heightEntry := widget.NewEntry()
+	heightEntry.SetText("1080")
+	cellEntry := widget.NewEntry()
+	cellEntry.SetText("8")
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetText("10")
+	gensEntry := widget.NewEntry()
+	gensEntry.SetText("20")
+
+	state.mu.Lock()
+	growthRate, mutationChance := state.growthRate, state.mutationChance
+	bloomSlot := *state.effects.find("Bloom")
+	state.mu.Unlock()
+
+	growthLabel := widget.NewLabel(fmt.Sprintf(lang.L("Growth rate: %.2f"), growthRate))
+	growthSlider := widget.NewSlider(0.05, 0.5)
+	growthSlider.Step = 0.01
+	growthSlider.Value = growthRate
+	growthSlider.OnChanged = func(v float64) {
+		growthLabel.SetText(fmt.Sprintf(lang.L("Growth rate: %.2f"), v))
+	}
+
+	mutationLabel := widget.NewLabel(fmt.Sprintf(lang.L("Mutation: %.3f"), mutationChance))
+	mutationSlider := widget.NewSlider(0, 0.1)
+	mutationSlider.Step = 0.001
+	mutationSlider.Value = mutationChance
+	mutationSlider.OnChanged = func(v float64) {
+		mutationLabel.SetText(fmt.Sprintf(lang.L("Mutation: %.3f"), v))
+	}
+
+	bloomCheck := widget.NewCheck(lang.L("Bloom Effect"), nil)
+	bloomCheck.Checked = bloomSlot.enabled
+
+	statusLabel := widget.NewLabel(lang.L("Wallpaper mode stopped"))
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	form := widget.NewForm(
+		widget.NewFormItem(lang.L("Width (px)"), widthEntry),
+		widget.NewFormItem(lang.L("Height (px)"), heightEntry),
+		widget.NewFormItem(lang.L("Cell size (px)"), cellEntry),
+		widget.NewFormItem(lang.L("Interval (min)"), intervalEntry),
+		widget.NewFormItem(lang.L("Generations/update"), gensEntry),
+	)
+	inputs := []fyne.Disableable{widthEntry, heightEntry, cellEntry, intervalEntry, gensEntry, growthSlider, mutationSlider, bloomCheck}
+
+	var runner *wallpaperRunner
+	var cancelRunner context.CancelFunc
+	driver := a.Driver()
+
+	stopRunner := func() {
+		if runner != nil {
+			cancelRunner()
+			runner = nil
+		}
+		for _, in := range inputs {
+			in.Enable()
+		}
+	}
+
+	toggleButton := widget.NewButton(lang.L("▶ Start wallpaper mode"), nil)
+	toggleButton.OnTapped = func() {
+		if runner != nil {
+			stopRunner()
+			toggleButton.SetText(lang.L("▶ Start wallpaper mode"))
+			statusLabel.SetText(lang.L("Wallpaper mode stopped"))
+			return
+		}
+
+		ints := make([]int, 5)
+		for i, e := range []*widget.Entry{widthEntry, heightEntry, cellEntry, intervalEntry, gensEntry} {
+			v, err := strconv.Atoi(e.Text)
+			if err != nil || v <= 0 {
+				statusLabel.SetText(lang.LocalizeKey("wallpaper.invalid", "Invalid value: {{.Value}}", map[string]any{"Value": e.Text}))
+				return
+			}
+			ints[i] = v
+		}
+		cfg := WallpaperConfig{
+			width:          ints[0],
+			height:         ints[1],
+			cellSize:       ints[2],
+			interval:       time.Duration(ints[3]) * time.Minute,
+			gensPerUpdate:  ints[4],
+			growthRate:     growthSlider.Value,
+			mutationChance: mutationSlider.Value,
+			paletteMode:    state.paletteMode,
+			bloomEffect:    bloomCheck.Checked,
+			bloom:          bloomSettings(bloomSlot),
+		}
+
+		r, err := newWallpaperRunner(cfg, seed, func(msg string) {
+			runOnMain(driver, func() {
+				statusLabel.SetText(msg)
+			})
+		})
+		if err != nil {
+			statusLabel.SetText(lang.LocalizeKey("wallpaper.start_failed", "Cannot start wallpaper mode: {{.Err}}", map[string]any{"Err": err}))
+			return
+		}
+		ctx, cancel := context.WithCancel(life.ctx)
+		runner, cancelRunner = r, cancel
+		for _, in := range inputs {
+			in.Disable()
+		}
+		toggleButton.SetText(lang.L("⏹ Stop wallpaper mode"))
+		statusLabel.SetText(lang.L("Rendering first wallpaper..."))
+		life.spawn(func() { r.run(ctx) })
+	}
+
+	w.SetContent(container.NewVBox(
+		widget.NewLabel(lang.L("🖼 Desktop background evolves slowly through the day")),
+		widget.NewSeparator(),
+		form,
+		growthLabel,
+		growthSlider,
+		mutationLabel,
+		mutationSlider,
+		bloomCheck,
+		toggleButton,
+		statusLabel,
+	))
+	w.SetOnClosed(stopRunner)
+	w.Resize(fyne.NewSize(400, 0))
+	w.Show()
+}
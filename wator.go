@@ -0,0 +1,215 @@
+package main
+
+import "image"
+
+// Kinds of Wa-Tor cells.
+const (
+	watorWater = iota
+	watorFish
+	watorShark
+)
+
+// wator is Dewdney's Wa-Tor predator-prey model on a wrap-around ocean:
+// fish (young) swim to a free neighbouring cell and breed every few
+// generations; sharks (old) eat a neighbouring fish when there is one,
+// breed likewise and starve after some generations without food.
+type wator struct {
+	size   int
+	kind   []uint8
+	timer  []int // generations since the last breeding
+	hunger []int // generations since a shark last ate
+	moved  []bool
+
+	fishBreed, sharkBreed, sharkStarve int
+	fish, sharks                       int
+}
+
+func (*wator) Name() string { return "Wa-Tor" }
+
+func (w *wator) tune(name string, value float64) {
+	switch name {
+	case "Fish breeding":
+		w.fishBreed = int(value)
+	case "Shark breeding":
+		w.sharkBreed = int(value)
+	case "Shark starvation":
+		w.sharkStarve = int(value)
+	}
+}
+
+func (w *wator) resize(n int) {
+	if w.kind != nil && w.size == n {
+		clear(w.kind)
+		clear(w.timer)
+		clear(w.hunger)
+		return
+	}
+	w.size = n
+	w.kind = make([]uint8, n*n)
+	w.timer = make([]int, n*n)
+	w.hunger = make([]int, n*n)
+	w.moved = make([]bool, n*n)
+}
+
+// seed fills about a third of the ocean with fish and a twentieth with
+// sharks, at random points of their breeding cycle.
+func (w *wator) seed(sim *Simulation) {
+	n := sim.gridSize
+	w.resize(n)
+	var pts []image.Point
+	for y := range n {
+		for x := range n {
+			var k uint8
+			switch r := sim.rng.Intn(20); {
+			case r == 0:
+				k = watorShark
+			case r < 7:
+				k = watorFish
+			default:
+				continue
+			}
+			timer := sim.rng.Intn(max(w.fishBreed, 1))
+			pts = sim.symmetry.orbit(pts[:0], x, y, n)
+			for _, p := range pts {
+				w.kind[p.Y*n+p.X] = k
+				w.timer[p.Y*n+p.X] = timer
+			}
+		}
+	}
+	w.publish(sim)
+}
+
+func (w *wator) load(sim *Simulation) {
+	w.resize(sim.gridSize)
+	for y := range sim.gridSize {
+		for x, c := range sim.grid.row(y) {
+			w.set(x, y, int(c))
+		}
+	}
+	w.count()
+}
+
+// step moves the sharks, then the fish, each in a random order.
+func (w *wator) step(sim *Simulation) (births int) {
+	clear(w.moved)
+	order := sim.rng.Perm(len(w.kind))
+	for _, i := range order {
+		if w.kind[i] == watorShark && !w.moved[i] {
+			births += w.moveShark(sim, i)
+		}
+	}
+	for _, i := range order {
+		if w.kind[i] == watorFish && !w.moved[i] {
+			births += w.moveFish(sim, i)
+		}
+	}
+	w.publish(sim)
+	return births
+}
+
+// neighbor picks a random neighbour of cell i holding kind k, or -1.
+func (w *wator) neighbor(sim *Simulation, i int, k uint8) int {
+	n := w.size
+	x, y := i%n, i/n
+	cells := [4]int{
+		((y+n-1)%n)*n + x,
+		y*n + (x+1)%n,
+		((y+1)%n)*n + x,
+		y*n + (x+n-1)%n,
+	}
+	var found [4]int
+	count := 0
+	for _, c := range cells {
+		if w.kind[c] == k {
+			found[count] = c
+			count++
+		}
+	}
+	if count == 0 {
+		return -1
+	}
+	return found[sim.rng.Intn(count)]
+}
+
+// swim moves the creature of cell from to cell to, leaving a newborn of the
+// same kind behind when it is due to breed. It returns the births.
+func (w *wator) swim(from, to, breed int) int {
+	w.kind[to], w.timer[to], w.hunger[to] = w.kind[from], w.timer[from]+1, w.hunger[from]
+	w.moved[to] = true
+	w.kind[from], w.timer[from], w.hunger[from] = watorWater, 0, 0
+	if w.timer[to] < breed {
+		return 0
+	}
+	w.timer[to] = 0
+	w.kind[from] = w.kind[to]
+	w.moved[from] = true
+	return 1
+}
+
+func (w *wator) moveFish(sim *Simulation, i int) int {
+	to := w.neighbor(sim, i, watorWater)
+	if to < 0 {
+		w.timer[i]++
+		return 0
+	}
+	return w.swim(i, to, w.fishBreed)
+}
+
+func (w *wator) moveShark(sim *Simulation, i int) int {
+	w.hunger[i]++
+	to := w.neighbor(sim, i, watorFish)
+	if to >= 0 {
+		w.hunger[i] = 0
+	} else if w.hunger[i] >= w.sharkStarve {
+		w.kind[i], w.timer[i], w.hunger[i] = watorWater, 0, 0
+		return 0
+	} else if to = w.neighbor(sim, i, watorWater); to < 0 {
+		w.timer[i]++
+		return 0
+	}
+	return w.swim(i, to, w.sharkBreed)
+}
+
+func (w *wator) set(x, y, val int) {
+	i := y*w.size + x
+	switch cellBand(val) {
+	case bandDead:
+		w.kind[i] = watorWater
+	case bandOld:
+		w.kind[i] = watorShark
+	default:
+		w.kind[i] = watorFish
+	}
+	w.timer[i], w.hunger[i] = 0, 0
+}
+
+// publish draws fish and sharks into sim.grid and counts them.
+func (w *wator) publish(sim *Simulation) {
+	for i, kind := range w.kind {
+		switch kind {
+		case watorFish:
+			sim.grid.cells[i] = youngState
+		case watorShark:
+			sim.grid.cells[i] = oldState
+		default:
+			sim.grid.cells[i] = 0
+		}
+	}
+	w.count()
+}
+
+func (w *wator) count() {
+	w.fish, w.sharks = 0, 0
+	for _, k := range w.kind {
+		switch k {
+		case watorFish:
+			w.fish++
+		case watorShark:
+			w.sharks++
+		}
+	}
+}
+
+func (w *wator) report(s *Stats) {
+	s.prey, s.predators = w.fish, w.sharks
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"io"
+	"slices"
+	"time"
+)
+
+// Animated WebP export: every frame is a lossless VP8L bitstream, written
+// without transforms or color cache. Runs of pixels equal to their left or
+// upper neighbour, which make up most of a grid of square cells, become
+// backward references.
+
+// VP8L alphabet sizes: green with the 24 length prefixes, red, blue, alpha
+// and distance prefixes.
+var vp8lAlphabets = [5]int{256 + 24, 256, 256, 256, 40}
+
+// vp8lCodeOrder is the order code length code lengths are written in.
+var vp8lCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// Distance codes of the upper and left neighbours, and the longest match.
+const (
+	vp8lAbove    = 1
+	vp8lLeft     = 2
+	vp8lMaxMatch = 4096
+	vp8lMinMatch = 3
+)
+
+// bitWriter packs bits least significant first, as VP8L reads them.
+type bitWriter struct {
+	buf   bytes.Buffer
+	bits  uint64
+	nBits uint
+}
+
+func (w *bitWriter) write(v uint32, n uint) {
+	w.bits |= uint64(v) << w.nBits
+	w.nBits += n
+	for w.nBits >= 8 {
+		w.buf.WriteByte(byte(w.bits))
+		w.bits >>= 8
+		w.nBits -= 8
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.nBits > 0 {
+		w.buf.WriteByte(byte(w.bits))
+		w.bits, w.nBits = 0, 0
+	}
+	return w.buf.Bytes()
+}
+
+// prefixCode is a canonical Huffman code. A code with a single symbol takes
+// no bits at all.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint32 // bit-reversed, ready for bitWriter
+	single  bool
+}
+
+// huffmanLengths returns code lengths of at most maxLen bits for freq,
+// halving the counts until the tree is shallow enough.
+func huffmanLengths(freq []int, maxLen int) []uint8 {
+	type node struct {
+		weight      int
+		symbol      int // -1 for inner nodes
+		left, right int
+	}
+	freq = slices.Clone(freq)
+	for {
+		var nodes []node
+		var queue []int
+		for s, f := range freq {
+			if f > 0 {
+				nodes = append(nodes, node{weight: f, symbol: s})
+				queue = append(queue, len(nodes)-1)
+			}
+		}
+		lengths := make([]uint8, len(freq))
+		if len(queue) == 1 {
+			lengths[nodes[0].symbol] = 1
+			return lengths
+		}
+		for len(queue) > 1 {
+			slices.SortFunc(queue, func(a, b int) int { return cmp.Compare(nodes[a].weight, nodes[b].weight) })
+			nodes = append(nodes, node{weight: nodes[queue[0]].weight + nodes[queue[1]].weight, symbol: -1, left: queue[0], right: queue[1]})
+			queue = append(queue[2:], len(nodes)-1)
+		}
+		deepest := 0
+		var walk func(n, depth int)
+		walk = func(n, depth int) {
+			if nodes[n].symbol >= 0 {
+				lengths[nodes[n].symbol] = uint8(depth)
+				deepest = max(deepest, depth)
+				return
+			}
+			walk(nodes[n].left, depth+1)
+			walk(nodes[n].right, depth+1)
+		}
+		if len(queue) == 1 {
+			walk(queue[0], 0)
+		}
+		if deepest <= maxLen {
+			return lengths
+		}
+		for s, f := range freq {
+			if f > 0 {
+				freq[s] = (f + 1) / 2
+			}
+		}
+	}
+}
+
+func newPrefixCode(lengths []uint8) *prefixCode {
+	c := &prefixCode{lengths: lengths, codes: make([]uint32, len(lengths))}
+	var count [16]uint32
+	used := 0
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+			used++
+		}
+	}
+	c.single = used <= 1
+	var next [16]uint32
+	code := uint32(0)
+	for l := 1; l < 16; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+	next[0] = 0
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		v := next[l]
+		next[l]++
+		rev := uint32(0)
+		for range l {
+			rev = rev<<1 | v&1
+			v >>= 1
+		}
+		c.codes[s] = rev
+	}
+	return c
+}
+
+func (c *prefixCode) emit(w *bitWriter, symbol int) {
+	if !c.single {
+		w.write(c.codes[symbol], uint(c.lengths[symbol]))
+	}
+}
+
+// writePrefixCode writes the code for freq and returns it: the simple form
+// for a single 8-bit symbol, the normal form otherwise.
+func writePrefixCode(w *bitWriter, freq []int) *prefixCode {
+	used := 0
+	symbol := 0
+	for s, f := range freq {
+		if f > 0 {
+			used++
+			symbol = s
+		}
+	}
+	if used <= 1 && symbol < 256 {
+		w.write(1, 1) // simple code
+		w.write(0, 1) // of one symbol
+		if symbol < 2 {
+			w.write(0, 1)
+			w.write(uint32(symbol), 1)
+		} else {
+			w.write(1, 1)
+			w.write(uint32(symbol), 8)
+		}
+		lengths := make([]uint8, len(freq))
+		lengths[symbol] = 1
+		return newPrefixCode(lengths)
+	}
+
+	code := newPrefixCode(huffmanLengths(freq, 15))
+	var lengthFreq [19]int
+	for _, l := range code.lengths {
+		lengthFreq[l]++
+	}
+	lengthCode := newPrefixCode(huffmanLengths(lengthFreq[:], 7))
+	n := len(vp8lCodeOrder)
+	for n > 4 && lengthCode.lengths[vp8lCodeOrder[n-1]] == 0 {
+		n--
+	}
+	w.write(0, 1) // normal code
+	w.write(uint32(n-4), 4)
+	for _, s := range vp8lCodeOrder[:n] {
+		w.write(uint32(lengthCode.lengths[s]), 3)
+	}
+	w.write(0, 1) // every symbol has a length
+	for _, l := range code.lengths {
+		lengthCode.emit(w, int(l))
+	}
+	return code
+}
+
+// vp8lPrefix splits a length or distance code into its prefix symbol and
+// extra bits.
+func vp8lPrefix(v int) (symbol int, extraBits uint, extra uint32) {
+	v--
+	if v < 4 {
+		return v, 0, 0
+	}
+	high := 31
+	for v>>high == 0 {
+		high--
+	}
+	second := v >> (high - 1) & 1
+	extraBits = uint(high - 1)
+	return 2*high + second, extraBits, uint32(v) & (1<<extraBits - 1)
+}
+
+// vp8lToken is a literal pixel, or a copy of length pixels from distance
+// code dist.
+type vp8lToken struct {
+	argb   uint32
+	length int
+	dist   int
+}
+
+// encodeVP8L writes img as a lossless VP8L bitstream.
+func encodeVP8L(img image.Image) []byte {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	pix := make([]uint32, 0, width*height)
+	opaque := true
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			pix = append(pix, uint32(c.A)<<24|uint32(c.R)<<16|uint32(c.G)<<8|uint32(c.B))
+			opaque = opaque && c.A == 255
+		}
+	}
+
+	// Greedy matching against the left and upper neighbours
+	var tokens []vp8lToken
+	run := func(i, dist int) int {
+		n := 0
+		for i+n < len(pix) && n < vp8lMaxMatch && pix[i+n] == pix[i+n-dist] {
+			n++
+		}
+		return n
+	}
+	for i := 0; i < len(pix); {
+		left, above := 0, 0
+		if i > 0 {
+			left = run(i, 1)
+		}
+		if i >= width {
+			above = run(i, width)
+		}
+		switch {
+		case above >= vp8lMinMatch && above >= left:
+			tokens = append(tokens, vp8lToken{length: above, dist: vp8lAbove})
+			i += above
+		case left >= vp8lMinMatch:
+			tokens = append(tokens, vp8lToken{length: left, dist: vp8lLeft})
+			i += left
+		default:
+			tokens = append(tokens, vp8lToken{argb: pix[i]})
+			i++
+		}
+	}
+
+	var freq [5][]int
+	for i, size := range vp8lAlphabets {
+		freq[i] = make([]int, size)
+	}
+	for _, t := range tokens {
+		if t.length == 0 {
+			freq[0][t.argb>>8&0xff]++
+			freq[1][t.argb>>16&0xff]++
+			freq[2][t.argb&0xff]++
+			freq[3][t.argb>>24]++
+			continue
+		}
+		ls, _, _ := vp8lPrefix(t.length)
+		ds, _, _ := vp8lPrefix(t.dist)
+		freq[0][256+ls]++
+		freq[4][ds]++
+	}
+
+	w := &bitWriter{}
+	w.write(0x2f, 8)
+	w.write(uint32(width-1), 14)
+	w.write(uint32(height-1), 14)
+	if opaque {
+		w.write(0, 1)
+	} else {
+		w.write(1, 1)
+	}
+	w.write(0, 3) // version
+	w.write(0, 1) // no transform
+	w.write(0, 1) // no color cache
+	w.write(0, 1) // one prefix code group
+	var codes [5]*prefixCode
+	for i := range codes {
+		codes[i] = writePrefixCode(w, freq[i])
+	}
+	for _, t := range tokens {
+		if t.length == 0 {
+			codes[0].emit(w, int(t.argb>>8&0xff))
+			codes[1].emit(w, int(t.argb>>16&0xff))
+			codes[2].emit(w, int(t.argb&0xff))
+			codes[3].emit(w, int(t.argb>>24))
+			continue
+		}
+		ls, lBits, lExtra := vp8lPrefix(t.length)
+		codes[0].emit(w, 256+ls)
+		w.write(lExtra, lBits)
+		ds, dBits, dExtra := vp8lPrefix(t.dist)
+		codes[4].emit(w, ds)
+		w.write(dExtra, dBits)
+	}
+	return w.bytes()
+}
+
+// riffChunk writes a RIFF chunk, padded to an even size.
+func riffChunk(w io.Writer, fourCC string, data []byte) {
+	io.WriteString(w, fourCC)
+	binary.Write(w, binary.LittleEndian, uint32(len(data)))
+	w.Write(data)
+	if len(data)%2 == 1 {
+		w.Write([]byte{0})
+	}
+}
+
+// uint24 appends v as 3 little-endian bytes.
+func uint24(b []byte, v int) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16))
+}
+
+// writeAnimatedWebP writes frames as an endlessly looping animated WebP,
+// each shown for delay.
+func writeAnimatedWebP(out io.Writer, frames frameSource, delay time.Duration) error {
+	if frames.count == 0 {
+		return errNoFrames
+	}
+	size := frames.frame(0).Bounds().Size()
+	var body bytes.Buffer
+	io.WriteString(&body, "WEBP")
+	vp8x := []byte{0x02 | 0x10, 0, 0, 0} // animation, alpha
+	vp8x = uint24(vp8x, size.X-1)
+	vp8x = uint24(vp8x, size.Y-1)
+	riffChunk(&body, "VP8X", vp8x)
+	riffChunk(&body, "ANIM", []byte{0, 0, 0, 0, 0, 0}) // background, loop forever
+	for i := range frames.count {
+		var anmf bytes.Buffer
+		header := uint24(nil, 0) // x/2
+		header = uint24(header, 0)
+		header = uint24(header, size.X-1)
+		header = uint24(header, size.Y-1)
+		header = uint24(header, int(delay.Milliseconds()))
+		header = append(header, 0x02) // no blending, no disposal
+		anmf.Write(header)
+		riffChunk(&anmf, "VP8L", encodeVP8L(frames.frame(i)))
+		riffChunk(&body, "ANMF", anmf.Bytes())
+	}
+	io.WriteString(out, "RIFF")
+	binary.Write(out, binary.LittleEndian, uint32(body.Len()))
+	_, err := out.Write(body.Bytes())
+	return err
+}
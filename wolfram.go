@@ -0,0 +1,54 @@
+package main
+
+// elementary runs an elementary (one-dimensional) cellular automaton given
+// by its Wolfram code: the bottom row is the current generation, computed
+// from the row above it, and older generations scroll upward, ageing by one
+// per row so the history reads through the palette bands. The row wraps
+// around.
+type elementary struct {
+	code int // Wolfram rule, 0-255
+}
+
+func (*elementary) Name() string { return "Elementary 1D" }
+
+func (e *elementary) tune(name string, value float64) {
+	if name == "Wolfram rule" {
+		e.code = int(value)
+	}
+}
+
+// seed starts from a single live cell in the middle of the bottom row.
+func (*elementary) seed(sim *Simulation) {
+	sim.grid.set(sim.gridSize/2, sim.gridSize-1, 1)
+}
+
+func (*elementary) load(*Simulation) {}
+
+func (e *elementary) step(sim *Simulation) (births int) {
+	g := sim.grid
+	n := g.size
+	copy(g.cells, g.cells[n:])
+	for i, c := range g.cells[:n*(n-1)] {
+		if c > 0 {
+			g.cells[i] = min(c+1, maxCellAge)
+		}
+	}
+	prev, cur := g.row(max(n-2, 0)), g.row(n-1)
+	alive := func(x int) int {
+		if prev[(x+n)%n] > 0 {
+			return 1
+		}
+		return 0
+	}
+	for x := range cur {
+		pattern := alive(x-1)<<2 | alive(x)<<1 | alive(x+1)
+		cur[x] = 0
+		if e.code>>pattern&1 == 1 {
+			cur[x] = 1
+			births++
+		}
+	}
+	return births
+}
+
+func (*elementary) set(x, y, val int) {}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// zoneNames label the zones, indexed by zone.
+var zoneNames = []string{"Zone A", "Zone B", "Zone C", "Zone D"}
+
+// zoneLayouts are the presets of the zone selector, indexed by layout.
+// Any layout, "Off" included, can then be repainted with the zone brush.
+var zoneLayouts = []string{"Off", "Halves ↔", "Halves ↕", "Quadrants"}
+
+// zoneBrushRadius is the radius in cells of the zone brush.
+const zoneBrushRadius = 2
+
+// climate is the local variant of the built-in rules in one zone: the
+// growth rate is scaled by growth, and the thresholds replace the default
+// ones. With evolving rules, every zone moves with the rule in effect.
+type climate struct {
+	growth     float64
+	thresholds thresholds
+}
+
+// defaultClimates give each zone a character of its own: temperate,
+// fertile, harsh and sheltered.
+var defaultClimates = [4]climate{
+	{growth: 1, thresholds: defaultThresholds},
+	{growth: 1.6, thresholds: thresholds{survival: 2.5, aging: 16.5}},
+	{growth: 0.6, thresholds: thresholds{survival: 4.5, aging: 20.5}},
+	{growth: 1, thresholds: thresholds{survival: 1.5, aging: 28.5}},
+}
+
+// zoneColors outline the zones, indexed by zone.
+var zoneColors = []color.RGBA{
+	{230, 230, 230, 255},
+	{90, 220, 90, 255},
+	{230, 90, 70, 255},
+	{90, 140, 240, 255},
+}
+
+// setZones lays the zones out following zoneLayouts[layout], or drops them
+// for "Off".
+func (s *Simulation) setZones(layout int) {
+	if layout <= 0 {
+		s.zones = nil
+		return
+	}
+	n := s.gridSize
+	s.zones = make([]uint8, n*n)
+	for y := range n {
+		for x := range n {
+			var z uint8
+			switch zoneLayouts[layout] {
+			case "Halves ↔":
+				if x >= n/2 {
+					z = 1
+				}
+			case "Halves ↕":
+				if y >= n/2 {
+					z = 1
+				}
+			case "Quadrants":
+				if x >= n/2 {
+					z++
+				}
+				if y >= n/2 {
+					z += 2
+				}
+			}
+			s.zones[y*n+x] = z
+		}
+	}
+}
+
+// paintZone assigns the disc of the zone brush around (cx, cy) to zone,
+// starting from a grid all in the first zone when there are none yet.
+func (s *Simulation) paintZone(cx, cy, zone int) {
+	n := s.gridSize
+	if s.zones == nil {
+		s.zones = make([]uint8, n*n)
+	}
+	r := zoneBrushRadius
+	for y := max(cy-r, 0); y <= min(cy+r, n-1); y++ {
+		for x := max(cx-r, 0); x <= min(cx+r, n-1); x++ {
+			dx, dy := x-cx, y-cy
+			if dx*dx+dy*dy <= r*r {
+				if s.edit != nil {
+					s.edit.zones.note(y*n+x, s.zones[y*n+x], uint8(zone))
+				}
+				s.zones[y*n+x] = uint8(zone)
+			}
+		}
+	}
+}
+
+// zoneClimates returns the climates of the generation to come, their
+// thresholds shifted by how far the rules have evolved.
+func (s *Simulation) zoneClimates() []climate {
+	out := slices.Clone(s.climates[:])
+	for i := range out {
+		out[i].thresholds.survival += s.thresholds.survival - defaultThresholds.survival
+		out[i].thresholds.aging += s.thresholds.aging - defaultThresholds.aging
+	}
+	return out
+}
+
+// zonePopulations counts the living cells of each zone.
+func zonePopulations(grid *Grid, zones []uint8) []int {
+	counts := make([]int, len(zoneNames))
+	for i, c := range grid.cells {
+		if c > 0 {
+			counts[zones[i]]++
+		}
+	}
+	return counts
+}
+
+// zoneSummary is the population line of the statistics.
+func zoneSummary(counts []int) string {
+	text := lang.L("Zones:")
+	for i, c := range counts {
+		text += fmt.Sprintf(" %s %d", lang.L(zoneNames[i]), c)
+	}
+	return text
+}
+
+// drawZoneBorders draws a line in the color of the zone on each side of the
+// cells bordering another zone.
+func drawZoneBorders(img *image.RGBA, zones []uint8, gridSize, cellSize int) {
+	bounds := img.Bounds()
+	set := func(px, py int, z uint8) {
+		p := image.Pt(bounds.Min.X+px, bounds.Min.Y+py)
+		if p.In(bounds) {
+			img.SetRGBA(p.X, p.Y, zoneColors[z])
+		}
+	}
+	for y := range gridSize {
+		for x := range gridSize {
+			z := zones[y*gridSize+x]
+			if x+1 < gridSize && zones[y*gridSize+x+1] != z {
+				for d := range cellSize {
+					set((x+1)*cellSize-1, y*cellSize+d, z)
+				}
+			}
+			if x > 0 && zones[y*gridSize+x-1] != z {
+				for d := range cellSize {
+					set(x*cellSize, y*cellSize+d, z)
+				}
+			}
+			if y+1 < gridSize && zones[(y+1)*gridSize+x] != z {
+				for d := range cellSize {
+					set(x*cellSize+d, (y+1)*cellSize-1, z)
+				}
+			}
+			if y > 0 && zones[(y-1)*gridSize+x] != z {
+				for d := range cellSize {
+					set(x*cellSize+d, y*cellSize, z)
+				}
+			}
+		}
+	}
+}
+
+// showZonesDialog picks the zone layout and brush and edits the climate of
+// each zone; climates apply to a running simulation at once, a new layout
+// replaces the painted zones.
+func showZonesDialog(w fyne.Window, state *SimulationState) {
+	state.mu.Lock()
+	layout, brush := state.zoneLayout, state.zoneBrush
+	climates := state.climates
+	state.mu.Unlock()
+
+	layoutSelect := widget.NewSelect(localized(zoneLayouts), func(shown string) {
+		l := slices.Index(zoneLayouts, unlocalized(zoneLayouts, shown))
+		state.mu.Lock()
+		if l != state.zoneLayout {
+			state.zoneLayout = l
+			state.zonesChanged = true
+		}
+		state.mu.Unlock()
+		logParam("zone_layout", zoneLayouts[l])
+	})
+	layoutSelect.SetSelected(lang.L(zoneLayouts[layout]))
+	brushSelect := widget.NewSelect(localized(zoneNames), func(shown string) {
+		state.mu.Lock()
+		state.zoneBrush = slices.Index(zoneNames, unlocalized(zoneNames, shown))
+		state.mu.Unlock()
+	})
+	brushSelect.SetSelected(lang.L(zoneNames[brush]))
+
+	// One slider per climate setting, label above
+	slider := func(format string, lo, hi, step, value float64, set func(c *climate, v float64), zone int, key string) fyne.CanvasObject {
+		label := widget.NewLabel(fmt.Sprintf(lang.L(format), value))
+		s := widget.NewSlider(lo, hi)
+		s.Step = step
+		s.Value = value
+		s.OnChanged = func(v float64) {
+			state.mu.Lock()
+			set(&state.climates[zone], v)
+			state.mu.Unlock()
+			logParam(key+"_"+zoneNames[zone], v)
+			label.SetText(fmt.Sprintf(lang.L(format), v))
+		}
+		return container.NewVBox(label, s)
+	}
+	tabs := container.NewAppTabs()
+	for i, c := range climates {
+		tabs.Append(container.NewTabItem(lang.L(zoneNames[i]), container.NewVBox(
+			slider("Growth rate ×%.2f", 0, 3, 0.05, c.growth, func(c *climate, v float64) { c.growth = v }, i, "zone_growth"),
+			slider("Survival above %.1f", minSurvival, maxSurvival, 0.5, c.thresholds.survival, func(c *climate, v float64) { c.thresholds.survival = v }, i, "zone_survival"),
+			slider("Ageing above %.1f", minAging, maxAging, 0.5, c.thresholds.aging, func(c *climate, v float64) { c.thresholds.aging = v }, i, "zone_aging"),
+		)))
+	}
+
+	content := container.NewVBox(
+		widget.NewLabel(lang.L("Each zone runs the built-in rules with its own growth rate and thresholds.\nPaint zones on the grid of a run with the 🗺 Zone brush tool.")),
+		widget.NewForm(
+			widget.NewFormItem(lang.L("Layout"), layoutSelect),
+			widget.NewFormItem(lang.L("Brush"), brushSelect),
+		),
+		tabs,
+	)
+	d := dialog.NewCustom(lang.L("🗺 Zones"), lang.L("Close"), content, w)
+	d.Resize(fyne.NewSize(450, 0))
+	d.Show()
+}